@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
 	"path/filepath"
@@ -58,9 +59,20 @@ func main() {
 		logger.Fatal("Ошибка инициализации репозитория: %v", err)
 	}
 
+	// фоновая проверка ограничительных метрик активных экспериментов
+	monitorCtx, stopMonitor := context.WithCancel(context.Background())
+	defer stopMonitor()
+	if config.Monitoring.GuardrailInterval > 0 {
+		go rep.RunGuardrailMonitor(monitorCtx, config.Monitoring.GuardrailInterval)
+	}
+	// фоновый запуск и завершение экспериментов по плановым датам
+	if config.Monitoring.SchedulerInterval > 0 {
+		go rep.RunExperimentScheduler(monitorCtx, config.Monitoring.SchedulerInterval)
+	}
+
 	// создание UI
 	fyneApp := app.New()
-	mainWindow := ui.NewMainWindow(fyneApp, rep)
+	mainWindow := ui.NewMainWindow(fyneApp, rep, config.Operator)
 	mainWindow.CreateUI()
 	mainWindow.Show()
 
@@ -0,0 +1,74 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing-platform/pkg/jsonschema"
+	"time"
+)
+
+// допустимое системное имя алгоритма
+var algorithmNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// Algorithm представляет алгоритм рекомендаций в реестре. Устаревший алгоритм остается
+// у существующих экспериментов, но не назначается вариантам новых
+type Algorithm struct {
+	Name        string    `db:"name" json:"name"`
+	Description string    `db:"description" json:"description"`
+	Owner       string    `db:"owner" json:"owner"`
+	Version     string    `db:"version" json:"version"`
+	Deprecated  bool      `db:"deprecated" json:"deprecated"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+
+	// ParamsSchema JSON-схема параметров вариантов; пустая схема допускает любые параметры
+	ParamsSchema map[string]any `db:"params_schema" json:"params_schema"`
+}
+
+// возврат имени таблицы в БД
+func (Algorithm) TableName() string {
+	return "algorithms"
+}
+
+// проверка корректности алгоритма
+func (a *Algorithm) Validate() error {
+	if !algorithmNamePattern.MatchString(a.Name) || len(a.Name) > 50 {
+		return errors.New("имя алгоритма должно состоять из строчных латинских букв, цифр и '_', начинаться с буквы и быть не длиннее 50 символов")
+	}
+	if len(a.Owner) > 100 {
+		return errors.New("владелец алгоритма слишком длинный (максимум 100 символов)")
+	}
+	version := strings.TrimSpace(a.Version)
+	if version == "" {
+		return errors.New("версия алгоритма не может быть пустой")
+	}
+	if len(version) > 50 {
+		return errors.New("версия алгоритма слишком длинная (максимум 50 символов)")
+	}
+	if err := jsonschema.CheckSchema(a.ParamsSchema); err != nil {
+		return fmt.Errorf("некорректная схема параметров алгоритма: %w", err)
+	}
+	return nil
+}
+
+// AlgorithmNames возвращает имена алгоритмов
+func AlgorithmNames(algorithms []Algorithm) []string {
+	names := make([]string, len(algorithms))
+	for i, a := range algorithms {
+		names[i] = a.Name
+	}
+	return names
+}
+
+// ValidateParams проверяет параметры варианта по схеме параметров алгоритма
+func (a *Algorithm) ValidateParams(params map[string]any) error {
+	var value any = map[string]any{}
+	if params != nil {
+		value = params
+	}
+	if err := jsonschema.Validate(a.ParamsSchema, value); err != nil {
+		return fmt.Errorf("параметры алгоритма %s: %w", a.Name, err)
+	}
+	return nil
+}
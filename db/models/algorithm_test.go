@@ -0,0 +1,65 @@
+package models
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAlgorithmValidate(t *testing.T) {
+	schema := map[string]any{"type": "object", "properties": map[string]any{"k": map[string]any{"type": "integer"}}}
+	tests := []struct {
+		name      string
+		algorithm Algorithm
+		wantErr   bool
+	}{
+		{"корректный", Algorithm{Name: "svd_v2", Version: "1.0", ParamsSchema: schema}, false},
+		{"без схемы", Algorithm{Name: "popular", Version: "1"}, false},
+		{"заглавные буквы", Algorithm{Name: "SVD", Version: "1"}, true},
+		{"начинается с цифры", Algorithm{Name: "2tower", Version: "1"}, true},
+		{"пустое имя", Algorithm{Version: "1"}, true},
+		{"длинное имя", Algorithm{Name: strings.Repeat("a", 51), Version: "1"}, true},
+		{"длинный владелец", Algorithm{Name: "svd", Version: "1", Owner: strings.Repeat("o", 101)}, true},
+		{"пустая версия", Algorithm{Name: "svd", Version: "  "}, true},
+		{"длинная версия", Algorithm{Name: "svd", Version: strings.Repeat("1", 51)}, true},
+		{"некорректная схема", Algorithm{Name: "svd", Version: "1", ParamsSchema: map[string]any{"type": "obj"}}, true},
+	}
+	for _, tt := range tests {
+		if err := tt.algorithm.Validate(); (err != nil) != tt.wantErr {
+			t.Errorf("%s: ошибка %v, ожидается ошибка: %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestAlgorithmNames(t *testing.T) {
+	got := AlgorithmNames([]Algorithm{{Name: "popular"}, {Name: "svd"}})
+	if !reflect.DeepEqual(got, []string{"popular", "svd"}) {
+		t.Errorf("AlgorithmNames = %v, ожидается [popular svd]", got)
+	}
+	if got := AlgorithmNames(nil); len(got) != 0 {
+		t.Errorf("AlgorithmNames(nil) = %v, ожидается пустой список", got)
+	}
+}
+
+func TestAlgorithmValidateParams(t *testing.T) {
+	a := &Algorithm{Name: "svd", ParamsSchema: map[string]any{
+		"type":                 "object",
+		"required":             []any{"factors"},
+		"additionalProperties": false,
+		"properties":           map[string]any{"factors": map[string]any{"type": "integer", "minimum": 1.0}},
+	}}
+	if err := a.ValidateParams(map[string]any{"factors": 64.0}); err != nil {
+		t.Errorf("корректные параметры: неожиданная ошибка: %v", err)
+	}
+	// отсутствующие параметры проверяются как пустой объект
+	err := a.ValidateParams(nil)
+	if err == nil || err.Error() != `параметры алгоритма svd: $: отсутствует обязательное свойство "factors"` {
+		t.Errorf("без параметров: ошибка %v", err)
+	}
+	if err := a.ValidateParams(map[string]any{"factors": 0.0}); err == nil {
+		t.Error("factors = 0: ожидается ошибка")
+	}
+	if err := (&Algorithm{Name: "popular"}).ValidateParams(map[string]any{"any": "value"}); err != nil {
+		t.Errorf("пустая схема: неожиданная ошибка: %v", err)
+	}
+}
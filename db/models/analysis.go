@@ -0,0 +1,437 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// RatingGroupStats представляет распределение оценок одной группы
+type RatingGroupStats struct {
+	Group    string  `json:"group"`
+	N        int     `json:"n"` // количество оценок (rating > 0)
+	Mean     float64 `json:"mean"`
+	Variance float64 `json:"variance"`
+	StdDev   float64 `json:"std_dev"`
+	Median   float64 `json:"median"`
+}
+
+// WelchTestResult представляет результат t-теста Уэлча
+type WelchTestResult struct {
+	MeanDiff         float64 `json:"mean_diff"`
+	StandardError    float64 `json:"standard_error"`
+	TStatistic       float64 `json:"t_statistic"`
+	DegreesOfFreedom float64 `json:"degrees_of_freedom"`
+	PValue           float64 `json:"p_value"`
+	CILower          float64 `json:"ci_lower"`
+	CIUpper          float64 `json:"ci_upper"`
+}
+
+// MannWhitneyResult представляет результат U-теста Манна–Уитни
+type MannWhitneyResult struct {
+	U                      float64 `json:"u"`
+	ZScore                 float64 `json:"z_score"`
+	PValue                 float64 `json:"p_value"`
+	ProbabilitySuperiority float64 `json:"probability_superiority"` // вероятность, что оценка теста выше оценки контроля
+}
+
+// RatingComparison представляет сравнение оценок тестовой группы с контрольной
+type RatingComparison struct {
+	ControlGroup   string             `json:"control_group"`
+	TreatmentGroup string             `json:"treatment_group"`
+	Welch          *WelchTestResult   `json:"welch,omitempty"`
+	MannWhitney    *MannWhitneyResult `json:"mann_whitney,omitempty"`
+}
+
+// RatingStats представляет анализ оценок эксперимента
+type RatingStats struct {
+	ExperimentID int                         `json:"experiment_id"`
+	Confidence   float64                     `json:"confidence"`
+	Groups       map[string]RatingGroupStats `json:"groups"`
+	Comparisons  []RatingComparison          `json:"comparisons,omitempty"`
+}
+
+// RatingValues возможные значения оценки results.rating (0 — без оценки)
+var RatingValues = []int{0, 1, 2, 3, 4, 5}
+
+// ChiSquareResult представляет результат критерия хи-квадрат
+type ChiSquareResult struct {
+	Statistic        float64 `json:"statistic"`
+	DF               int     `json:"df"`
+	PValue           float64 `json:"p_value"`
+	LowExpectedCells int     `json:"low_expected_cells"` // ячейки с ожидаемой частотой меньше 5
+}
+
+// RatingDistribution представляет распределение значений оценки по группам эксперимента
+// и проверку однородности распределений критерием хи-квадрат
+type RatingDistribution struct {
+	ExperimentID int                  `json:"experiment_id"`
+	Values       []int                `json:"values"`
+	Counts       map[string][]int     `json:"counts"` // частоты по группам, выровнены по Values
+	Shares       map[string][]float64 `json:"shares"`
+	ChiSquare    *ChiSquareResult     `json:"chi_square,omitempty"`
+}
+
+// BayesianGroupStats представляет апостериорное распределение CTR одной группы
+type BayesianGroupStats struct {
+	Group           string  `json:"group"`
+	Clicks          int     `json:"clicks"`
+	Recommendations int     `json:"recommendations"`
+	Alpha           float64 `json:"alpha"` // параметры апостериорного Beta-распределения
+	Beta            float64 `json:"beta"`
+	PosteriorMean   float64 `json:"posterior_mean"`
+	CredibleLower   float64 `json:"credible_lower"`
+	CredibleUpper   float64 `json:"credible_upper"`
+	ProbBeatControl float64 `json:"prob_beat_control"` // P(CTR группы > CTR контроля)
+	ProbBest        float64 `json:"prob_best"`         // вероятность того, что группа лучшая
+	ExpectedLoss    float64 `json:"expected_loss"`     // ожидаемые потери CTR при выборе группы
+}
+
+// BayesianStats представляет байесовский анализ CTR эксперимента
+type BayesianStats struct {
+	ExperimentID int                  `json:"experiment_id"`
+	Credibility  float64              `json:"credibility"` // уровень интервалов достоверности
+	Draws        int                  `json:"draws"`       // число выборок Монте-Карло
+	PriorAlpha   float64              `json:"prior_alpha"`
+	PriorBeta    float64              `json:"prior_beta"`
+	Groups       []BayesianGroupStats `json:"groups"` // контрольная группа первая
+}
+
+// решения последовательного теста
+const (
+	SequentialContinue     = "continue"
+	SequentialStopPrefix   = "stop_for_"
+	DefaultSequentialAlpha = 0.05
+)
+
+// SequentialStopFor возвращает решение об остановке эксперимента в пользу группы
+func SequentialStopFor(group string) string {
+	return SequentialStopPrefix + group
+}
+
+// SequentialLook представляет один просмотр последовательного теста (mSPRT) тестовой группы против контроля
+type SequentialLook struct {
+	ID                       int       `db:"id" json:"id"`
+	ExperimentID             int       `db:"experiment_id" json:"experiment_id"`
+	TreatmentGroup           string    `db:"treatment_group" json:"treatment_group"`
+	LookNumber               int       `db:"look_number" json:"look_number"`
+	LookedAt                 time.Time `db:"looked_at" json:"looked_at"`
+	ControlRecommendations   int       `db:"control_recommendations" json:"control_recommendations"`
+	ControlClicks            int       `db:"control_clicks" json:"control_clicks"`
+	TreatmentRecommendations int       `db:"treatment_recommendations" json:"treatment_recommendations"`
+	TreatmentClicks          int       `db:"treatment_clicks" json:"treatment_clicks"`
+	Estimate                 float64   `db:"estimate" json:"estimate"` // разность CTR (тест - контроль)
+	ZScore                   float64   `db:"z_score" json:"z_score"`
+	LikelihoodRatio          float64   `db:"likelihood_ratio" json:"likelihood_ratio"`
+	ZBoundary                float64   `db:"z_boundary" json:"z_boundary"` // текущая граница остановки по |z|
+	PValue                   float64   `db:"p_value" json:"p_value"`       // всегда валидное p-значение
+	Alpha                    float64   `db:"alpha" json:"alpha"`
+	Decision                 string    `db:"decision" json:"decision"`
+}
+
+// возврат имени таблицы в БД
+func (SequentialLook) TableName() string {
+	return "sequential_looks"
+}
+
+// SequentialStatus представляет текущее состояние последовательного теста эксперимента
+type SequentialStatus struct {
+	ExperimentID int              `json:"experiment_id"`
+	Decision     string           `json:"decision"`           // continue или stop_for_<группа>
+	Boundary     *float64         `json:"boundary,omitempty"` // граница |z| последнего просмотра
+	UpdatedAt    *time.Time       `json:"updated_at,omitempty"`
+	Looks        []SequentialLook `json:"looks,omitempty"` // последний просмотр по каждой тестовой группе
+}
+
+// SampleSizeInput представляет параметры планирования размера выборки эксперимента
+type SampleSizeInput struct {
+	BaselineCTR         float64  `json:"baseline_ctr"`          // ожидаемый CTR контрольной группы
+	MinDetectableEffect float64  `json:"min_detectable_effect"` // относительный минимальный эффект (0.05 = +5%)
+	Alpha               float64  `json:"alpha"`
+	Power               float64  `json:"power"`
+	Groups              int      `json:"groups"`       // количество групп эксперимента (по умолчанию 2)
+	UserPercent         float64  `json:"user_percent"` // планируемый процент пользователей
+	AlgorithmA          string   `json:"algorithm_a"`  // алгоритмы и теги для поиска похожих экспериментов
+	AlgorithmB          string   `json:"algorithm_b"`
+	Tags                []string `json:"tags,omitempty"`
+}
+
+// SampleSizePlan представляет результат планирования размера выборки и длительности эксперимента
+type SampleSizePlan struct {
+	Input                   SampleSizeInput `json:"input"`
+	RecommendationsPerGroup int             `json:"recommendations_per_group"` // показов рекомендаций на группу
+	RecommendationsPerUser  float64         `json:"recommendations_per_user"`  // по истории похожих экспериментов
+	UsersPerGroup           int             `json:"users_per_group"`
+	TotalUsers              int             `json:"total_users"`
+	SimilarExperiments      int             `json:"similar_experiments"`      // экспериментов в основе оценки темпа
+	DailyUsersPerPercent    float64         `json:"daily_users_per_percent"`  // пользователей в день на 1% трафика
+	DailyUsers              float64         `json:"daily_users"`              // ожидаемый темп при UserPercent
+	EstimatedDays           float64         `json:"estimated_days,omitempty"` // 0, если темп неизвестен
+}
+
+// UserPercentForDays возвращает процент пользователей, необходимый для набора выборки за days дней
+func (p *SampleSizePlan) UserPercentForDays(days float64) float64 {
+	if days <= 0 || p.DailyUsersPerPercent <= 0 {
+		return 0
+	}
+	return float64(p.TotalUsers) / (p.DailyUsersPerPercent * days)
+}
+
+// SRMThreshold порог p-значения, ниже которого фиксируется несоответствие соотношения групп
+const SRMThreshold = 0.001
+
+// SRMCheck представляет проверку соотношения размеров групп (sample ratio mismatch):
+// критерий хи-квадрат наблюдаемого числа пользователей против ожидаемого распределения
+type SRMCheck struct {
+	ExperimentID int                `json:"experiment_id"`
+	Observed     map[string]int     `json:"observed"` // пользователей в группе
+	Expected     map[string]float64 `json:"expected"` // ожидаемое число пользователей
+	ChiSquare    float64            `json:"chi_square"`
+	DF           int                `json:"df"`
+	PValue       float64            `json:"p_value"`
+	Mismatch     bool               `json:"mismatch"` // PValue < SRMThreshold
+}
+
+// UserObservation представляет наблюдения одного пользователя эксперимента (единица бутстрэпа)
+type UserObservation struct {
+	UserID          int    `json:"user_id"` // users.id
+	Group           string `json:"group"`
+	Recommendations int    `json:"recommendations"`
+	Clicks          int    `json:"clicks"`
+	Ratings         []int  `json:"ratings,omitempty"` // поставленные оценки (rating > 0)
+}
+
+// BootstrapComparison представляет бутстрэп-интервал разности метрики тестовой группы и контроля
+type BootstrapComparison struct {
+	ControlGroup   string  `json:"control_group"`
+	TreatmentGroup string  `json:"treatment_group"`
+	ControlValue   float64 `json:"control_value"`
+	TreatmentValue float64 `json:"treatment_value"`
+	Difference     float64 `json:"difference"`
+	StandardError  float64 `json:"standard_error"`
+	CILower        float64 `json:"ci_lower"`
+	CIUpper        float64 `json:"ci_upper"`
+	Iterations     int     `json:"iterations"` // итераций с определенным значением метрики
+}
+
+// BootstrapStats представляет результат бутстрэп-анализа метрики эксперимента
+type BootstrapStats struct {
+	ExperimentID int                   `json:"experiment_id"`
+	Metric       string                `json:"metric"`
+	Method       string                `json:"method"` // percentile или bca
+	Iterations   int                   `json:"iterations"`
+	Confidence   float64               `json:"confidence"`
+	Users        map[string]int        `json:"users"` // пользователей в группах
+	Comparisons  []BootstrapComparison `json:"comparisons,omitempty"`
+}
+
+// CUPEDComparison представляет сравнение группы с контролем до и после CUPED-корректировки
+type CUPEDComparison struct {
+	ControlGroup         string           `json:"control_group"`
+	TreatmentGroup       string           `json:"treatment_group"`
+	ControlMean          float64          `json:"control_mean"`   // средний CTR пользователя в контроле
+	TreatmentMean        float64          `json:"treatment_mean"` // средний CTR пользователя в тестовой группе
+	RelativeLiftRaw      float64          `json:"relative_lift_raw"`
+	RelativeLiftAdjusted float64          `json:"relative_lift_adjusted"`
+	Raw                  *WelchTestResult `json:"raw"`
+	Adjusted             *WelchTestResult `json:"adjusted"`
+	VarianceReduction    float64          `json:"variance_reduction"` // 1 - SE²(скорр.) / SE²(исх.)
+}
+
+// CUPEDStats представляет анализ CTR эксперимента с CUPED-корректировкой
+type CUPEDStats struct {
+	ExperimentID      int               `json:"experiment_id"`
+	Confidence        float64           `json:"confidence"`
+	Users             int               `json:"users"`              // пользователей с рекомендациями
+	UsersWithHistory  int               `json:"users_with_history"` // из них с данными предпериода
+	Coverage          float64           `json:"coverage"`           // UsersWithHistory / Users
+	Theta             float64           `json:"theta"`
+	Correlation       float64           `json:"correlation"`
+	VarianceReduction float64           `json:"variance_reduction"` // снижение дисперсии метрики по всем группам
+	Comparisons       []CUPEDComparison `json:"comparisons,omitempty"`
+}
+
+// PValueEntry представляет одну статистическую проверку эксперимента с поправками на множественность
+type PValueEntry struct {
+	Family                string  `json:"family"` // источник проверки: CTR, оценки, CUPED, метрики реестра, сегменты
+	Test                  string  `json:"test"`   // название проверки
+	ControlGroup          string  `json:"control_group"`
+	TreatmentGroup        string  `json:"treatment_group"`
+	PValue                float64 `json:"p_value"`
+	BonferroniPValue      float64 `json:"bonferroni_p_value"`
+	BHAdjustedPValue      float64 `json:"bh_adjusted_p_value"`
+	Significant           bool    `json:"significant"`            // без поправки
+	SignificantBonferroni bool    `json:"significant_bonferroni"` // контроль FWER
+	SignificantBH         bool    `json:"significant_bh"`         // контроль FDR
+}
+
+// MultipleTestingReport представляет все p-значения эксперимента с поправками и сводкой по семейству проверок
+type MultipleTestingReport struct {
+	ExperimentID          int           `json:"experiment_id"`
+	Alpha                 float64       `json:"alpha"`
+	Tests                 []PValueEntry `json:"tests"`
+	SignificantRaw        int           `json:"significant_raw"`
+	SignificantBonferroni int           `json:"significant_bonferroni"`
+	SignificantBH         int           `json:"significant_bh"`
+	// FamilyWiseErrorRate вероятность хотя бы одного ложного срабатывания без поправки
+	// при независимых проверках и верных нулевых гипотезах: 1 - (1 - alpha)^m
+	FamilyWiseErrorRate float64 `json:"family_wise_error_rate"`
+	// Items z-тесты CTR отдельных рекомендаций — самое большое семейство проверок;
+	// поправки к нему применяются отдельно, чтобы не растворять в нем проверки эксперимента
+	Items *MultipleTestingReport `json:"items,omitempty"`
+}
+
+// семейства проверок в отчете о множественных сравнениях
+const (
+	PValueFamilyCTR      = "CTR"
+	PValueFamilyRatings  = "Оценки"
+	PValueFamilyCUPED    = "CUPED"
+	PValueFamilyMetrics  = "Метрики реестра"
+	PValueFamilySegments = "Сегменты"
+	PValueFamilyItems    = "Рекомендации"
+)
+
+// названия проверок в отчете о множественных сравнениях; проверки метрик реестра
+// называются заголовком метрики, проверки сегментов — SegmentPValueTest
+const (
+	PValueTestCTR             = "z-тест CTR"
+	PValueTestWelch           = "t-тест Уэлча"
+	PValueTestMannWhitney     = "U-тест Манна–Уитни"
+	PValueTestCUPED           = "t-тест Уэлча после CUPED"
+	PValueTestRatingChiSquare = "хи-квадрат распределения оценок"
+)
+
+// тестовая группа проверки, которая сравнивает сразу все группы эксперимента
+const PValueAllGroups = "все"
+
+// SegmentPValueTest возвращает название проверки CTR в сегменте segment измерения dimension
+func SegmentPValueTest(dimension, segment string) string {
+	return fmt.Sprintf("CTR: %s = %s", dimension, segment)
+}
+
+// ItemPValueTest возвращает название z-теста CTR рекомендации recommendationID
+func ItemPValueTest(recommendationID string) string {
+	return "CTR рекомендации " + recommendationID
+}
+
+// Find возвращает проверку по семейству, названию и тестовой группе или nil, если такой нет
+func (r *MultipleTestingReport) Find(family, test, treatment string) *PValueEntry {
+	if r == nil {
+		return nil
+	}
+	for i := range r.Tests {
+		t := &r.Tests[i]
+		if t.Family == family && t.Test == test && t.TreatmentGroup == treatment {
+			return t
+		}
+	}
+	return nil
+}
+
+// шаг временного ряда эксперимента
+const (
+	TimeGranularityDay  = "day"
+	TimeGranularityHour = "hour"
+)
+
+// TimeSeriesPoint представляет показатели группы за один интервал и накопленные к его концу.
+// Показы и оценки (все результаты с rating > 0, как в GetExperimentStats) относятся к интервалу по results.created_at,
+// клики — по results.clicked_at
+type TimeSeriesPoint struct {
+	Bucket                    time.Time `json:"bucket"`
+	Recommendations           int       `json:"recommendations"`
+	Clicks                    int       `json:"clicks"`
+	CTR                       float64   `json:"ctr"`
+	Ratings                   int       `json:"ratings"`
+	AvgRating                 float64   `json:"avg_rating"`
+	CumulativeRecommendations int       `json:"cumulative_recommendations"`
+	CumulativeClicks          int       `json:"cumulative_clicks"`
+	CumulativeCTR             float64   `json:"cumulative_ctr"`
+}
+
+// LiftPoint представляет накопленный прирост CTR тестовой группы к концу интервала
+type LiftPoint struct {
+	Bucket       time.Time `json:"bucket"`
+	RelativeLift float64   `json:"relative_lift"`
+	AbsoluteLift float64   `json:"absolute_lift"`
+	CILower      float64   `json:"ci_lower"` // поточечный интервал, не учитывает многократные просмотры
+	CIUpper      float64   `json:"ci_upper"`
+	Defined      bool      `json:"defined"` // false, пока в одной из групп нет показов
+}
+
+// ExperimentTimeSeries представляет динамику показателей групп эксперимента
+type ExperimentTimeSeries struct {
+	ExperimentID int                          `json:"experiment_id"`
+	Granularity  string                       `json:"granularity"`
+	Confidence   float64                      `json:"confidence"`
+	Buckets      []time.Time                  `json:"buckets"`
+	Groups       map[string][]TimeSeriesPoint `json:"groups"` // точки выровнены по Buckets
+	Lift         map[string][]LiftPoint       `json:"lift"`   // по тестовым группам
+}
+
+// измерения сегментного анализа
+const (
+	SegmentByTag     = "tag"     // теги эксперимента (experiments.tags)
+	SegmentByDevice  = "device"  // устройство пользователя
+	SegmentByCountry = "country" // страна пользователя
+	SegmentByCohort  = "cohort"  // когорта пользователя
+)
+
+// SegmentDimensions измерения, доступные для сегментного анализа
+var SegmentDimensions = []string{SegmentByDevice, SegmentByCountry, SegmentByCohort, SegmentByTag}
+
+// SegmentUnspecified название сегмента пользователей без значения измерения
+const SegmentUnspecified = "(не указано)"
+
+// SegmentPooledGroup группа в сегментах по всем экспериментам: группы с одинаковым названием
+// в разных экспериментах несопоставимы, поэтому показываются только итоги сегмента
+const SegmentPooledGroup = "все"
+
+// SegmentStats представляет статистику групп и сравнение CTR с контролем внутри одного сегмента
+type SegmentStats struct {
+	Segment     string                `json:"segment"`
+	Groups      map[string]GroupStats `json:"groups"`
+	Comparisons []SignificanceTest    `json:"comparisons,omitempty"`
+}
+
+// SegmentReport представляет разбиение статистики эксперимента по сегментам одного измерения
+type SegmentReport struct {
+	ExperimentID int            `json:"experiment_id"` // 0 — все эксперименты
+	Dimension    string         `json:"dimension"`
+	Confidence   float64        `json:"confidence"`
+	Segments     []SegmentStats `json:"segments"`
+}
+
+// ItemGroupStats представляет показатели одной рекомендации в группе
+type ItemGroupStats struct {
+	Impressions int     `json:"impressions"`
+	Clicks      int     `json:"clicks"`
+	CTR         float64 `json:"ctr"`
+	Ratings     int     `json:"ratings"`
+	AvgRating   float64 `json:"avg_rating"`
+}
+
+// ItemPerformance представляет показатели рекомендации (results.recommendation_id) по группам
+// и разность CTR тестовой группы с контролем
+type ItemPerformance struct {
+	RecommendationID string                    `json:"recommendation_id"`
+	Groups           map[string]ItemGroupStats `json:"groups"`
+	Impressions      int                       `json:"impressions"`
+	Clicks           int                       `json:"clicks"`
+	CTR              float64                   `json:"ctr"`
+	AbsoluteLift     float64                   `json:"absolute_lift"`
+	PValue           float64                   `json:"p_value"`
+	AdjustedPValue   float64                   `json:"adjusted_p_value"` // по Бенджамини–Хохбергу среди сравнимых рекомендаций
+	Comparable       bool                      `json:"comparable"`       // показана в обеих группах не реже порога
+}
+
+// ItemReport представляет разбор эффективности рекомендаций эксперимента
+type ItemReport struct {
+	ExperimentID   int               `json:"experiment_id"`
+	ControlGroup   string            `json:"control_group"`
+	TreatmentGroup string            `json:"treatment_group"`
+	MinImpressions int               `json:"min_impressions"`
+	Confidence     float64           `json:"confidence"`
+	Items          []ItemPerformance `json:"items"`   // по убыванию числа показов
+	Winners        []ItemPerformance `json:"winners"` // тестовая группа значимо лучше контроля после поправки
+	Losers         []ItemPerformance `json:"losers"`  // тестовая группа значимо хуже контроля после поправки
+}
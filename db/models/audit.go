@@ -0,0 +1,86 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// сущности журнала изменений экспериментов
+const (
+	ChangeEntityExperiment = "experiment"
+	ChangeEntityVariant    = "variant"
+)
+
+// ExperimentChange представляет запись журнала изменений эксперимента или его варианта
+// со снимками строки до (Before) и после (After) изменения; при создании Before пуст, при удалении — After
+type ExperimentChange struct {
+	ID           int64          `db:"id" json:"id"`
+	ExperimentID int            `db:"experiment_id" json:"experiment_id"`
+	Entity       string         `db:"entity" json:"entity"`
+	Operation    string         `db:"operation" json:"operation"` // INSERT, UPDATE или DELETE
+	ChangedBy    string         `db:"changed_by" json:"changed_by"`
+	ChangedAt    time.Time      `db:"changed_at" json:"changed_at"`
+	Before       map[string]any `db:"old_data" json:"old_data,omitempty"`
+	After        map[string]any `db:"new_data" json:"new_data,omitempty"`
+}
+
+// возврат имени таблицы в БД
+func (ExperimentChange) TableName() string {
+	return "experiment_changes"
+}
+
+// FieldChange представляет изменение одного поля между снимками
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+}
+
+// Diff возвращает поля, различающиеся в снимках до и после изменения, по алфавиту;
+// отсутствующее в снимке поле показывается как "—"
+func (c *ExperimentChange) Diff() []FieldChange {
+	fields := make(map[string]bool)
+	for k := range c.Before {
+		fields[k] = true
+	}
+	for k := range c.After {
+		fields[k] = true
+	}
+
+	var diff []FieldChange
+	for field := range fields {
+		before, hasBefore := c.Before[field]
+		after, hasAfter := c.After[field]
+		if hasBefore == hasAfter && reflect.DeepEqual(before, after) {
+			continue
+		}
+		diff = append(diff, FieldChange{Field: field, Before: formatSnapshotValue(before, hasBefore),
+			After: formatSnapshotValue(after, hasAfter)})
+	}
+	sort.Slice(diff, func(i, j int) bool { return diff[i].Field < diff[j].Field })
+	return diff
+}
+
+// formatSnapshotValue форматирует значение поля JSON-снимка
+func formatSnapshotValue(v any, present bool) string {
+	switch value := v.(type) {
+	case nil:
+		if !present {
+			return "—"
+		}
+		return "NULL"
+	case string:
+		return value
+	case float64, bool:
+		return fmt.Sprint(value)
+	default:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Sprint(value)
+		}
+		return string(data)
+	}
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExperimentChangeDiff(t *testing.T) {
+	tests := []struct {
+		name          string
+		before, after map[string]any
+		want          []FieldChange
+	}{
+		{"без изменений", map[string]any{"name": "x", "tags": []any{"a"}}, map[string]any{"name": "x", "tags": []any{"a"}}, nil},
+		{"создание", nil, map[string]any{"weight": 50.0, "name": "B"},
+			[]FieldChange{{"name", "—", "B"}, {"weight", "—", "50"}}},
+		{"удаление", map[string]any{"name": "B"}, nil, []FieldChange{{"name", "B", "—"}}},
+		{"изменения по алфавиту", map[string]any{"user_percent": 10.0, "is_active": false, "name": "x"},
+			map[string]any{"user_percent": 20.0, "is_active": true, "name": "x"},
+			[]FieldChange{{"is_active", "false", "true"}, {"user_percent", "10", "20"}}},
+		// NULL в снимке отличается от отсутствующего поля
+		{"NULL и отсутствие", map[string]any{"planned_start": nil}, map[string]any{"planned_end": nil},
+			[]FieldChange{{"planned_end", "—", "NULL"}, {"planned_start", "NULL", "—"}}},
+		{"составные значения в JSON", map[string]any{"tags": []any{"a"}, "params": map[string]any{"k": 1.0}},
+			map[string]any{"tags": []any{"a", "b"}, "params": map[string]any{"k": 2.0}},
+			[]FieldChange{{"params", `{"k":1}`, `{"k":2}`}, {"tags", `["a"]`, `["a","b"]`}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			change := &ExperimentChange{Before: tt.before, After: tt.after}
+			if got := change.Diff(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %v, ожидается %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -2,6 +2,8 @@ package models
 
 import (
 	"os"
+	"os/user"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -22,6 +24,15 @@ type Config struct {
 		MaxSize    int64  `yaml:"max_size"`    // макс. размер файла (байты)
 		MaxBackups int    `yaml:"max_backups"` // макс. количество бэкапов
 	} `yaml:"logging"`
+
+	Monitoring struct {
+		GuardrailInterval time.Duration `yaml:"guardrail_interval"` // период проверки ограничительных метрик (< 0 — отключено)
+		SchedulerInterval time.Duration `yaml:"scheduler_interval"` // период запуска и завершения экспериментов по расписанию (< 0 — отключено)
+	} `yaml:"monitoring"`
+
+	// имя оператора, от которого изменения из интерфейса записываются в журнал экспериментов
+	// (по умолчанию — пользователь ОС)
+	Operator string `yaml:"operator"`
 }
 
 func LoadConfig(path string) (*Config, error) {
@@ -49,5 +60,28 @@ func LoadConfig(path string) (*Config, error) {
 		config.Logging.Level = "info" // info по умолчанию
 	}
 
+	// установка значений по умолчанию для мониторинга
+	if config.Monitoring.GuardrailInterval == 0 {
+		config.Monitoring.GuardrailInterval = 5 * time.Minute
+	}
+	if config.Monitoring.SchedulerInterval == 0 {
+		config.Monitoring.SchedulerInterval = time.Minute
+	}
+
+	if config.Operator == "" {
+		config.Operator = osUserName()
+	}
+
 	return config, nil
 }
+
+// osUserName возвращает имя текущего пользователя ОС или пустую строку, если его не удалось определить
+func osUserName() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return os.Getenv("USERNAME")
+}
@@ -1,11 +1,23 @@
 package models
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
+	"math"
 	"slices"
 	"time"
 )
 
+// ControlGroup название контрольной группы эксперимента
+const ControlGroup = "A"
+
+// DefaultConfidence уровень доверия по умолчанию для статистических интервалов
+const DefaultConfidence = 0.95
+
+// DefaultItemMinImpressions минимальное число показов рекомендации в группе для ее сравнения по умолчанию
+const DefaultItemMinImpressions = 10
+
 // Experiment представляет сущность эксперимента A/B тестирования
 type Experiment struct {
 	ID          int       `db:"id" json:"id"`
@@ -16,11 +28,104 @@ type Experiment struct {
 	StartDate   time.Time `db:"start_date" json:"start_date"`
 	IsActive    bool      `db:"is_active" json:"is_active"`
 	Tags        []string  `db:"tags" json:"tags"`
+	Variants    []Variant `json:"variants,omitempty"` // варианты A/B/n; A и B дублируются в AlgorithmA и AlgorithmB
+
+	// слой эксперимента (0 — без слоя) и занятый диапазон трафика слоя [LayerStart, LayerEnd) в процентах;
+	// диапазон выделяется при создании эксперимента по UserPercent
+	LayerID    int     `db:"layer_id" json:"layer_id,omitempty"`
+	LayerStart float64 `db:"layer_range_start" json:"layer_range_start,omitempty"`
+	LayerEnd   float64 `db:"layer_range_end" json:"layer_range_end,omitempty"`
+
+	// статус жизненного цикла (StatusDraft ... StatusArchived); IsActive равен Status == StatusRunning
+	Status          string    `db:"status" json:"status"`
+	StatusChangedAt time.Time `db:"status_changed_at" json:"status_changed_at"`
+
+	// плановые даты: в PlannedStart запланированный эксперимент запускается автоматически,
+	// в PlannedEnd запущенный или приостановленный — завершается (nil — не задано)
+	PlannedStart *time.Time `db:"planned_start" json:"planned_start,omitempty"`
+	PlannedEnd   *time.Time `db:"planned_end" json:"planned_end,omitempty"`
+
+	// Version версия строки для оптимистичной блокировки; увеличивается при каждом изменении эксперимента
+	Version int `db:"version" json:"version"`
+}
+
+// MaxVariants максимальное число вариантов эксперимента
+const MaxVariants = 8
+
+// Variant представляет вариант эксперимента: группу пользователей и ее алгоритм рекомендаций
+type Variant struct {
+	ID           int    `db:"id" json:"id"`
+	ExperimentID int    `db:"experiment_id" json:"experiment_id"`
+	Name         string `db:"name" json:"name"` // название группы (users.group_name)
+	Algorithm    string `db:"algorithm" json:"algorithm"`
+	Position     int    `db:"position" json:"position"`
+
+	// Weight доля трафика варианта в процентах; веса вариантов эксперимента в сумме дают 100
+	Weight float64 `db:"weight" json:"weight"`
+
+	// Params параметры алгоритма варианта, проверяются по схеме параметров алгоритма
+	Params map[string]any `db:"params" json:"params,omitempty"`
+}
+
+// FormatParams возвращает параметры варианта в виде компактного JSON с упорядоченными ключами;
+// пустая строка, если параметры не заданы
+func FormatParams(params map[string]any) string {
+	if len(params) == 0 {
+		return ""
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return fmt.Sprint(params)
+	}
+	return string(data)
+}
+
+// VariantName возвращает название варианта по его позиции: A, B, C, ...
+func VariantName(position int) string {
+	return string(rune('A' + position))
+}
+
+// EqualWeights делит 100% трафика поровну между n вариантами с точностью до сотых;
+// остаток от округления достается первому (контрольному) варианту
+func EqualWeights(n int) []float64 {
+	if n <= 0 {
+		return nil
+	}
+	weights := make([]float64, n)
+	share := math.Floor(10000/float64(n)) / 100
+	for i := range weights {
+		weights[i] = share
+	}
+	weights[0] = math.Round((100-share*float64(n-1))*100) / 100
+	return weights
+}
+
+// PickVariant выбирает вариант пропорционально весам; u — число из [0, 1)
+func PickVariant(variants []Variant, u float64) (Variant, bool) {
+	var total float64
+	for _, v := range variants {
+		total += v.Weight
+	}
+	if len(variants) == 0 || total <= 0 {
+		return Variant{}, false
+	}
+	threshold := u * total
+	var cumulative float64
+	for _, v := range variants {
+		cumulative += v.Weight
+		if threshold < cumulative {
+			return v, true
+		}
+	}
+	return variants[len(variants)-1], true
 }
 
 // GroupStats представляет статистику для одной группы
 type GroupStats struct {
 	Group                string  `json:"group"`
+	Algorithm            string  `json:"algorithm,omitempty"`
+	Params               string  `json:"params,omitempty"` // параметры алгоритма группы в виде JSON
+	Users                int     `json:"users"`
 	TotalRecommendations int     `json:"total_recommendations"`
 	TotalClicks          int     `json:"total_clicks"`
 	AvgRating            float64 `json:"avg_rating"`
@@ -33,6 +138,24 @@ type ExperimentStats struct {
 	Groups       map[string]GroupStats `json:"groups"`
 	TotalStats   GroupStats            `json:"total_stats"`
 	Tags         []string              `json:"tags,omitempty"`
+	Confidence   float64               `json:"confidence"`            // уровень доверия для интервалов
+	Comparisons  []SignificanceTest    `json:"comparisons,omitempty"` // сравнение CTR тестовых групп с контрольной
+	Sequential   *SequentialStatus     `json:"sequential,omitempty"`  // состояние последовательного теста
+	SRM          *SRMCheck             `json:"srm,omitempty"`         // проверка соотношения размеров групп
+}
+
+// SignificanceTest представляет результат z-теста CTR тестовой группы против контрольной
+type SignificanceTest struct {
+	ControlGroup   string  `json:"control_group"`
+	TreatmentGroup string  `json:"treatment_group"`
+	AbsoluteLift   float64 `json:"absolute_lift"`  // разность CTR (тест - контроль)
+	RelativeLift   float64 `json:"relative_lift"`  // прирост CTR относительно контроля
+	StandardError  float64 `json:"standard_error"` // стандартная ошибка разности
+	ZScore         float64 `json:"z_score"`
+	PValue         float64 `json:"p_value"`
+	CILower        float64 `json:"ci_lower"` // нижняя граница интервала для разности
+	CIUpper        float64 `json:"ci_upper"` // верхняя граница интервала для разности
+	Significant    bool    `json:"significant"`
 }
 
 // ExperimentFilter представлет структуру для фильтрации
@@ -42,6 +165,9 @@ type ExperimentFilter struct {
 	IsActive      *bool // использование указателя для возможности передачи nil
 	StartDateFrom time.Time
 	StartDateTo   time.Time
+
+	// Status статус жизненного цикла, пустая строка — любой
+	Status string
 }
 
 // ExperimentResult представляет сводные данные эксперимента с JOIN
@@ -50,6 +176,7 @@ type ExperimentResult struct {
 	Name         string  `json:"name"`
 	AlgorithmA   string  `json:"algorithm_a"`
 	AlgorithmB   string  `json:"algorithm_b"`
+	Algorithms   string  `json:"algorithms"` // алгоритмы всех вариантов через запятую
 	TotalResults int     `json:"total_results"`
 	TotalClicks  int     `json:"total_clicks"`
 	AvgRating    float64 `json:"avg_rating"`
@@ -62,12 +189,50 @@ func (Experiment) TableName() string {
 
 // проверка корректности данных эксперимента
 func (e *Experiment) Validate() error {
-	validAlgorithms := []string{"collaborative", "content_based", "hybrid", "popularity_based"}
-	if !slices.Contains(validAlgorithms, e.AlgorithmA) {
-		return errors.New("неверный тип алгоритма A")
+	variants := e.VariantList()
+	if len(variants) < 2 {
+		return errors.New("в эксперименте должно быть минимум два варианта")
+	}
+	if len(variants) > MaxVariants {
+		return errors.New("слишком много вариантов (максимум 8)")
+	}
+	names := make(map[string]bool, len(variants))
+	algorithms := make(map[string]bool, len(variants))
+	var weights float64
+	for i, v := range variants {
+		if v.Name == "" || len(v.Name) > 10 {
+			return errors.New("название варианта должно содержать от 1 до 10 символов")
+		}
+		if i == 0 && v.Name != ControlGroup {
+			return errors.New("первый вариант должен быть контрольной группой " + ControlGroup)
+		}
+		if names[v.Name] {
+			return errors.New("варианты не могут называться одинаково")
+		}
+		if v.Algorithm == "" {
+			return errors.New("алгоритм " + v.Name + " не может быть пустым")
+		}
+		// наличие алгоритма в реестре проверяется в репозитории
+		if !algorithmNamePattern.MatchString(v.Algorithm) {
+			return errors.New("неверное имя алгоритма " + v.Name)
+		}
+		// один алгоритм допускается в нескольких вариантах, если различаются параметры
+		config := v.Algorithm + FormatParams(v.Params)
+		if algorithms[config] {
+			return errors.New("алгоритмы вариантов с одинаковыми параметрами не могут повторяться")
+		}
+		if v.Weight <= 0 || v.Weight > 100 {
+			return errors.New("вес варианта " + v.Name + " должен быть больше 0 и не больше 100")
+		}
+		if math.Abs(v.Weight*100-math.Round(v.Weight*100)) > 1e-6 {
+			return errors.New("вес варианта " + v.Name + " может иметь не более 2 знаков после запятой")
+		}
+		names[v.Name] = true
+		algorithms[config] = true
+		weights += v.Weight
 	}
-	if !slices.Contains(validAlgorithms, e.AlgorithmB) {
-		return errors.New("неверный тип алгоритма B")
+	if math.Abs(weights-100) > 1e-6 {
+		return errors.New("сумма весов вариантов должна быть равна 100")
 	}
 	if e.Name == "" {
 		return errors.New("название эксперимента не может быть пустым")
@@ -78,14 +243,11 @@ func (e *Experiment) Validate() error {
 	if e.UserPercent < 1.0 || e.UserPercent > 100.0 {
 		return errors.New("процент пользователей должен быть от 1.0 до 100.0")
 	}
-	if e.AlgorithmA == "" {
-		return errors.New("алгоритм A не может быть пустым")
-	}
-	if e.AlgorithmB == "" {
-		return errors.New("алгоритм B не может быть пустым")
+	if e.Status != "" && !IsValidStatus(e.Status) {
+		return errors.New("неизвестный статус эксперимента " + e.Status)
 	}
-	if e.AlgorithmA == e.AlgorithmB {
-		return errors.New("алгоритмы A и B не могут быть одинаковыми")
+	if err := ValidateSchedule(e.Status, e.PlannedStart, e.PlannedEnd); err != nil {
+		return err
 	}
 	if len(e.Tags) > 10 {
 		return errors.New("слишком много тегов (максимум 10)")
@@ -98,11 +260,34 @@ func (e *Experiment) Validate() error {
 	return nil
 }
 
+// SameVariants сравнивает варианты по названию, алгоритму, весу и параметрам
+func SameVariants(a, b []Variant) bool {
+	return slices.EqualFunc(a, b, func(x, y Variant) bool {
+		return x.Name == y.Name && x.Algorithm == y.Algorithm && x.Weight == y.Weight &&
+			FormatParams(x.Params) == FormatParams(y.Params)
+	})
+}
+
+// VariantList возвращает варианты эксперимента; если список вариантов не задан,
+// эксперимент считается классическим A/B 50/50 с алгоритмами AlgorithmA и AlgorithmB
+func (e *Experiment) VariantList() []Variant {
+	if len(e.Variants) > 0 {
+		return e.Variants
+	}
+	return []Variant{
+		{ExperimentID: e.ID, Name: VariantName(0), Algorithm: e.AlgorithmA, Position: 0, Weight: 50},
+		{ExperimentID: e.ID, Name: VariantName(1), Algorithm: e.AlgorithmB, Position: 1, Weight: 50},
+	}
+}
+
 // методы для инкапсулиции логики проверки
 
 // проверка, активен ли эксперимент
 func (e *Experiment) IsRunning() bool {
-	return e.IsActive
+	if e.Status == "" {
+		return e.IsActive
+	}
+	return e.Status == StatusRunning
 }
 
 // возвращение названия алгоритмов для отображения
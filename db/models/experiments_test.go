@@ -0,0 +1,269 @@
+package models
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// validExperiment возвращает корректный эксперимент из трех вариантов для проверок Validate
+func validExperiment() *Experiment {
+	return &Experiment{
+		Name:        "Главная страница",
+		UserPercent: 50,
+		Variants: []Variant{
+			{Name: "A", Algorithm: "popular", Weight: 50},
+			{Name: "B", Algorithm: "svd", Weight: 25},
+			{Name: "C", Algorithm: "svd", Weight: 25, Params: map[string]any{"factors": 64}},
+		},
+	}
+}
+
+func TestVariantName(t *testing.T) {
+	for position, want := range []string{"A", "B", "C", "H"} {
+		if position == 3 {
+			position = MaxVariants - 1
+		}
+		if got := VariantName(position); got != want {
+			t.Errorf("VariantName(%d) = %q, ожидается %q", position, got, want)
+		}
+	}
+}
+
+func TestVariantList(t *testing.T) {
+	// без списка вариантов эксперимент считается классическим A/B 50/50
+	e := &Experiment{ID: 3, AlgorithmA: "popular", AlgorithmB: "svd"}
+	variants := e.VariantList()
+	want := []Variant{
+		{ExperimentID: 3, Name: "A", Algorithm: "popular", Position: 0, Weight: 50},
+		{ExperimentID: 3, Name: "B", Algorithm: "svd", Position: 1, Weight: 50},
+	}
+	if len(variants) != len(want) {
+		t.Fatalf("вариантов %d, ожидается %d", len(variants), len(want))
+	}
+	for i := range want {
+		if variants[i].ExperimentID != want[i].ExperimentID || variants[i].Name != want[i].Name ||
+			variants[i].Algorithm != want[i].Algorithm || variants[i].Position != want[i].Position ||
+			variants[i].Weight != want[i].Weight {
+			t.Errorf("вариант %d: %+v, ожидается %+v", i, variants[i], want[i])
+		}
+	}
+
+	e = validExperiment()
+	if got := e.VariantList(); len(got) != 3 || &got[0] != &e.Variants[0] {
+		t.Error("заданный список вариантов должен возвращаться без изменений")
+	}
+}
+
+func TestEqualWeights(t *testing.T) {
+	tests := []struct {
+		n    int
+		want []float64
+	}{
+		{0, nil},
+		{1, []float64{100}},
+		{2, []float64{50, 50}},
+		{3, []float64{33.34, 33.33, 33.33}},
+		{7, []float64{14.32, 14.28, 14.28, 14.28, 14.28, 14.28, 14.28}},
+		{8, []float64{12.5, 12.5, 12.5, 12.5, 12.5, 12.5, 12.5, 12.5}},
+	}
+	for _, tt := range tests {
+		got := EqualWeights(tt.n)
+		if len(got) != len(tt.want) {
+			t.Fatalf("EqualWeights(%d): %d весов, ожидается %d", tt.n, len(got), len(tt.want))
+		}
+		var sum float64
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("EqualWeights(%d)[%d] = %g, ожидается %g", tt.n, i, got[i], tt.want[i])
+			}
+			sum += got[i]
+		}
+		if tt.n > 0 && math.Abs(sum-100) > 1e-9 {
+			t.Errorf("EqualWeights(%d): сумма %g, ожидается 100", tt.n, sum)
+		}
+	}
+}
+
+func TestPickVariant(t *testing.T) {
+	variants := func(weights ...float64) []Variant {
+		list := make([]Variant, len(weights))
+		for i, w := range weights {
+			list[i] = Variant{Name: VariantName(i), Weight: w}
+		}
+		return list
+	}
+	tests := []struct {
+		name     string
+		variants []Variant
+		u        float64
+		want     string
+	}{
+		{"начало интервала", variants(50, 50), 0, "A"},
+		{"граница попадает в следующий вариант", variants(50, 50), 0.5, "B"},
+		{"конец интервала", variants(50, 50), math.Nextafter(1, 0), "B"},
+		{"99/1: последняя доля первого", variants(99, 1), math.Nextafter(0.99, 0), "A"},
+		{"99/1: граница", variants(99, 1), 0.99, "B"},
+		{"99/1: конец интервала", variants(99, 1), math.Nextafter(1, 0), "B"},
+		{"нулевой вес в начале", variants(0, 60, 40), 0, "B"},
+		{"нулевой вес в середине", variants(60, 0, 40), 0.6, "C"},
+		{"нулевой вес в конце", variants(60, 40, 0), math.Nextafter(1, 0), "B"},
+		// веса нормируются на сумму
+		{"сумма не 100", variants(1, 3), 0.3, "B"},
+	}
+	for _, tt := range tests {
+		got, ok := PickVariant(tt.variants, tt.u)
+		if !ok || got.Name != tt.want {
+			t.Errorf("%s: выбран %q (%v), ожидается %q", tt.name, got.Name, ok, tt.want)
+		}
+	}
+
+	// вариант с нулевым весом не выбирается ни при каком u
+	for i := range 1000 {
+		if v, _ := PickVariant(variants(50, 0, 50), float64(i)/1000); v.Name == "B" {
+			t.Fatalf("u = %g: выбран вариант с нулевым весом", float64(i)/1000)
+		}
+	}
+
+	for _, list := range [][]Variant{nil, variants(0, 0)} {
+		if _, ok := PickVariant(list, 0.5); ok {
+			t.Errorf("веса %v: ожидается отсутствие варианта", list)
+		}
+	}
+}
+
+func TestExperimentValidate(t *testing.T) {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 14)
+	tests := []struct {
+		name    string
+		modify  func(e *Experiment)
+		wantErr string // подстрока ошибки, пусто — эксперимент корректен
+	}{
+		{"корректный эксперимент", func(e *Experiment) {}, ""},
+		{"классический A/B 50/50", func(e *Experiment) {
+			e.Variants, e.AlgorithmA, e.AlgorithmB = nil, "popular", "svd"
+		}, ""},
+		{"A/B без алгоритма", func(e *Experiment) {
+			e.Variants, e.AlgorithmA = nil, "popular"
+		}, "алгоритм B не может быть пустым"},
+		{"один вариант", func(e *Experiment) {
+			e.Variants = []Variant{{Name: "A", Algorithm: "popular", Weight: 100}}
+		}, "минимум два варианта"},
+		{"больше восьми вариантов", func(e *Experiment) {
+			e.Variants = nil
+			for i := range MaxVariants + 1 {
+				e.Variants = append(e.Variants, Variant{Name: VariantName(i), Algorithm: "svd", Weight: 10})
+			}
+		}, "максимум 8"},
+		{"восемь вариантов", func(e *Experiment) {
+			e.Variants = nil
+			for i := range MaxVariants {
+				e.Variants = append(e.Variants, Variant{Name: VariantName(i), Algorithm: "svd", Weight: 12.5,
+					Params: map[string]any{"k": i}})
+			}
+		}, ""},
+		{"контроль не A", func(e *Experiment) {
+			e.Variants[0].Name, e.Variants[1].Name = "B", "A"
+		}, "первый вариант должен быть контрольной группой A"},
+		{"пустое название варианта", func(e *Experiment) { e.Variants[1].Name = "" }, "от 1 до 10 символов"},
+		{"длинное название варианта", func(e *Experiment) { e.Variants[1].Name = "VARIANT_B_1" }, "от 1 до 10 символов"},
+		{"одинаковые названия", func(e *Experiment) { e.Variants[2].Name = "B" }, "называться одинаково"},
+		{"неверное имя алгоритма", func(e *Experiment) { e.Variants[1].Algorithm = "SVD++" }, "неверное имя алгоритма B"},
+		{"одинаковые алгоритм и параметры", func(e *Experiment) { e.Variants[2].Params = nil }, "с одинаковыми параметрами"},
+		{"одинаковые параметры в разном порядке", func(e *Experiment) {
+			e.Variants[1].Params = map[string]any{"a": 1, "b": 2}
+			e.Variants[2].Params = map[string]any{"b": 2, "a": 1}
+		}, "с одинаковыми параметрами"},
+		{"нулевой вес", func(e *Experiment) {
+			e.Variants[1].Weight, e.Variants[2].Weight = 0, 50
+		}, "вес варианта B должен быть больше 0"},
+		{"вес больше 100", func(e *Experiment) { e.Variants[0].Weight = 150 }, "не больше 100"},
+		{"три знака после запятой", func(e *Experiment) {
+			e.Variants[1].Weight, e.Variants[2].Weight = 24.995, 25.005
+		}, "не более 2 знаков"},
+		{"сумма весов меньше 100", func(e *Experiment) { e.Variants[2].Weight = 24.99 }, "сумма весов"},
+		{"сумма весов больше 100", func(e *Experiment) { e.Variants[0].Weight = 50.01 }, "сумма весов"},
+		{"веса 99.99/0.01", func(e *Experiment) {
+			e.Variants = e.Variants[:2]
+			e.Variants[0].Weight, e.Variants[1].Weight = 99.99, 0.01
+		}, ""},
+		{"пустое название", func(e *Experiment) { e.Name = "" }, "название эксперимента не может быть пустым"},
+		{"длинное название", func(e *Experiment) { e.Name = strings.Repeat("a", 256) }, "слишком длинное"},
+		{"процент меньше 1", func(e *Experiment) { e.UserPercent = 0.5 }, "процент пользователей"},
+		{"процент больше 100", func(e *Experiment) { e.UserPercent = 101 }, "процент пользователей"},
+		{"неизвестный статус", func(e *Experiment) { e.Status = "active" }, "неизвестный статус"},
+		{"запланирован без даты", func(e *Experiment) { e.Status = StatusScheduled }, "плановую дату запуска"},
+		{"окончание раньше начала", func(e *Experiment) {
+			e.Status, e.PlannedStart, e.PlannedEnd = StatusScheduled, &end, &start
+		}, "позже"},
+		{"много тегов", func(e *Experiment) { e.Tags = strings.Split("a,b,c,d,e,f,g,h,i,j,k", ",") }, "тегов"},
+		{"длинный тег", func(e *Experiment) { e.Tags = []string{strings.Repeat("t", 51)} }, "тег слишком длинный"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := validExperiment()
+			tt.modify(e)
+			err := e.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("неожиданная ошибка: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ошибка %v, ожидается содержащая %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFormatParams(t *testing.T) {
+	tests := []struct {
+		params map[string]any
+		want   string
+	}{
+		{nil, ""},
+		{map[string]any{}, ""},
+		// ключи упорядочены, поэтому одинаковые параметры дают одинаковую строку
+		{map[string]any{"k": 10, "alpha": 0.5, "mode": "fast"}, `{"alpha":0.5,"k":10,"mode":"fast"}`},
+		{map[string]any{"nested": map[string]any{"b": true, "a": []any{1, "x"}}}, `{"nested":{"a":[1,"x"],"b":true}}`},
+	}
+	for _, tt := range tests {
+		if got := FormatParams(tt.params); got != tt.want {
+			t.Errorf("FormatParams(%v) = %s, ожидается %s", tt.params, got, tt.want)
+		}
+	}
+}
+
+func TestSameVariants(t *testing.T) {
+	base := validExperiment().Variants
+	tests := []struct {
+		name   string
+		modify func(v []Variant) []Variant
+		same   bool
+	}{
+		{"без изменений", func(v []Variant) []Variant { return v }, true},
+		// служебные поля не сравниваются
+		{"другие ID и позиции", func(v []Variant) []Variant {
+			v[0].ID, v[1].Position, v[2].ExperimentID = 10, 5, 7
+			return v
+		}, true},
+		{"параметры той же структуры", func(v []Variant) []Variant {
+			v[2].Params = map[string]any{"factors": 64}
+			return v
+		}, true},
+		{"другой алгоритм", func(v []Variant) []Variant { v[1].Algorithm = "knn"; return v }, false},
+		{"другой вес", func(v []Variant) []Variant { v[1].Weight, v[2].Weight = 20, 30; return v }, false},
+		{"другие параметры", func(v []Variant) []Variant { v[2].Params = map[string]any{"factors": 32}; return v }, false},
+		{"другое название", func(v []Variant) []Variant { v[2].Name = "D"; return v }, false},
+		{"меньше вариантов", func(v []Variant) []Variant { return v[:2] }, false},
+	}
+	for _, tt := range tests {
+		updated := tt.modify(validExperiment().Variants)
+		if got := SameVariants(base, updated); got != tt.same {
+			t.Errorf("%s: SameVariants = %v, ожидается %v", tt.name, got, tt.same)
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+// DefaultGuardrailAlpha уровень значимости проверки ограничительной метрики по умолчанию
+const DefaultGuardrailAlpha = 0.05
+
+// Guardrail представляет ограничительную метрику эксперимента: допустимое ухудшение
+// метрики реестра в тестовой группе относительно контроля
+type Guardrail struct {
+	ID             int       `db:"id" json:"id"`
+	ExperimentID   int       `db:"experiment_id" json:"experiment_id"`
+	MetricID       int       `db:"metric_id" json:"metric_id"`
+	MetricName     string    `db:"-" json:"metric_name"`                   // заполняется из реестра метрик
+	MaxDegradation float64   `db:"max_degradation" json:"max_degradation"` // в единицах метрики
+	Alpha          float64   `db:"alpha" json:"alpha"`
+	IsEnabled      bool      `db:"is_enabled" json:"is_enabled"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+}
+
+// возврат имени таблицы в БД
+func (Guardrail) TableName() string {
+	return "guardrails"
+}
+
+// проверка корректности ограничительной метрики
+func (g *Guardrail) Validate() error {
+	if g.ExperimentID <= 0 {
+		return errors.New("айди эксперимента должен быть положительным")
+	}
+	if g.MetricID <= 0 {
+		return errors.New("не выбрана метрика")
+	}
+	if g.MaxDegradation < 0 {
+		return errors.New("допустимое ухудшение не может быть отрицательным")
+	}
+	if g.Alpha <= 0 || g.Alpha >= 1 {
+		return errors.New("уровень значимости должен быть в интервале (0, 1)")
+	}
+	return nil
+}
+
+// GuardrailEvaluation представляет одну проверку ограничительной метрики для тестовой группы
+type GuardrailEvaluation struct {
+	ID                int       `db:"id" json:"id"`
+	GuardrailID       *int      `db:"guardrail_id" json:"guardrail_id"` // nil, если ограничение удалено
+	ExperimentID      int       `db:"experiment_id" json:"experiment_id"`
+	MetricName        string    `db:"metric_name" json:"metric_name"`
+	TreatmentGroup    string    `db:"treatment_group" json:"treatment_group"`
+	EvaluatedAt       time.Time `db:"evaluated_at" json:"evaluated_at"`
+	MaxDegradation    float64   `db:"max_degradation" json:"max_degradation"`
+	ControlValue      float64   `db:"control_value" json:"control_value"`
+	TreatmentValue    float64   `db:"treatment_value" json:"treatment_value"`
+	Degradation       float64   `db:"degradation" json:"degradation"` // ухудшение метрики, > 0 — хуже контроля
+	StandardError     float64   `db:"standard_error" json:"standard_error"`
+	PValue            float64   `db:"p_value" json:"p_value"` // одностороннее: H0 — ухудшение не больше допустимого
+	Alpha             float64   `db:"alpha" json:"alpha"`
+	Breached          bool      `db:"breached" json:"breached"`
+	ExperimentStopped bool      `db:"experiment_stopped" json:"experiment_stopped"`
+}
+
+// возврат имени таблицы в БД
+func (GuardrailEvaluation) TableName() string {
+	return "guardrail_evaluations"
+}
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Layer представляет слой (пространство имен) экспериментов: эксперименты одного слоя
+// получают непересекающиеся диапазоны трафика, эксперименты разных слоев независимы
+type Layer struct {
+	ID          int       `db:"id" json:"id"`
+	Name        string    `db:"name" json:"name"`
+	Description string    `db:"description" json:"description"`
+	CreatedAt   time.Time `db:"created_at" json:"created_at"`
+}
+
+// возврат имени таблицы в БД
+func (Layer) TableName() string {
+	return "layers"
+}
+
+// проверка корректности слоя
+func (l *Layer) Validate() error {
+	name := strings.TrimSpace(l.Name)
+	if name == "" {
+		return errors.New("название слоя не может быть пустым")
+	}
+	if len(name) > 100 {
+		return errors.New("название слоя слишком длинное (максимум 100 символов)")
+	}
+	if len(l.Description) > 1000 {
+		return errors.New("описание слоя слишком длинное (максимум 1000 символов)")
+	}
+	return nil
+}
+
+// LayerRange представляет диапазон трафика слоя [Start, End) в процентах, занятый экспериментом
+type LayerRange struct {
+	ExperimentID   int     `json:"experiment_id"`
+	ExperimentName string  `json:"experiment_name"`
+	Status         string  `json:"status"`
+	Start          float64 `json:"start"`
+	End            float64 `json:"end"`
+}
+
+// LayerUsage представляет занятость трафика слоя экспериментами
+type LayerUsage struct {
+	Layer       Layer        `json:"layer"`
+	Ranges      []LayerRange `json:"ranges"` // по возрастанию начала
+	FreePercent float64      `json:"free_percent"`
+}
+
+// FindFreeRange возвращает начало первого свободного промежутка длиной width
+// среди занятых диапазонов слоя (0–100%); диапазоны архивных экспериментов считаются свободными
+func FindFreeRange(ranges []LayerRange, width float64) (float64, bool) {
+	if width <= 0 || width > 100 {
+		return 0, false
+	}
+	var sorted []LayerRange
+	for _, r := range ranges {
+		if r.Status != StatusArchived {
+			sorted = append(sorted, r)
+		}
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+
+	var start float64
+	for _, r := range sorted {
+		if r.Start-start >= width-1e-9 {
+			return start, true
+		}
+		if r.End > start {
+			start = r.End
+		}
+	}
+	if 100-start >= width-1e-9 {
+		return start, true
+	}
+	return 0, false
+}
@@ -0,0 +1,36 @@
+package models
+
+import "testing"
+
+func TestFindFreeRange(t *testing.T) {
+	tests := []struct {
+		name   string
+		ranges []LayerRange
+		width  float64
+		start  float64
+		ok     bool
+	}{
+		{"пустой слой", nil, 30, 0, true},
+		{"весь слой", nil, 100, 0, true},
+		{"после занятого", []LayerRange{{Start: 0, End: 40}}, 30, 40, true},
+		// диапазоны приходят в произвольном порядке; подходит первый промежуток
+		{"промежуток между диапазонами", []LayerRange{{Start: 60, End: 100}, {Start: 0, End: 20}}, 30, 20, true},
+		{"промежуток мал", []LayerRange{{Start: 0, End: 20}, {Start: 40, End: 90}}, 30, 0, false},
+		{"промежуток ровно по ширине", []LayerRange{{Start: 0, End: 20}, {Start: 50, End: 100}}, 30, 20, true},
+		{"граница с погрешностью", []LayerRange{{Start: 0, End: 33.33}, {Start: 66.67, End: 100}}, 33.34, 33.33, true},
+		{"слой заполнен", []LayerRange{{Start: 0, End: 50}, {Start: 50, End: 100}}, 1, 0, false},
+		{"свободно не одним куском", []LayerRange{{Start: 20, End: 50}, {Start: 70, End: 100}}, 30, 0, false},
+		{"диапазон архивного свободен", []LayerRange{{Start: 0, End: 50, Status: StatusArchived}, {Start: 50, End: 100, Status: StatusRunning}}, 50, 0, true},
+		{"диапазон остановленного занят", []LayerRange{{Start: 0, End: 50, Status: StatusStopped}, {Start: 50, End: 100, Status: StatusRunning}}, 50, 0, false},
+		{"нулевая ширина", nil, 0, 0, false},
+		{"ширина больше слоя", nil, 101, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, ok := FindFreeRange(tt.ranges, tt.width)
+			if ok != tt.ok || (ok && start != tt.start) {
+				t.Errorf("FindFreeRange = %g, %v, ожидается %g, %v", start, ok, tt.start, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// статусы жизненного цикла эксперимента
+const (
+	StatusDraft     = "draft"
+	StatusScheduled = "scheduled"
+	StatusRunning   = "running"
+	StatusPaused    = "paused"
+	StatusStopped   = "stopped"
+	StatusConcluded = "concluded"
+	StatusArchived  = "archived"
+)
+
+// ExperimentStatuses статусы эксперимента в порядке жизненного цикла
+var ExperimentStatuses = []string{
+	StatusDraft, StatusScheduled, StatusRunning, StatusPaused, StatusStopped, StatusConcluded, StatusArchived,
+}
+
+// InitialStatuses статусы, с которыми можно создать эксперимент
+var InitialStatuses = []string{StatusDraft, StatusScheduled, StatusRunning}
+
+// StatusTitles названия статусов для отображения
+var StatusTitles = map[string]string{
+	StatusDraft:     "Черновик",
+	StatusScheduled: "Запланирован",
+	StatusRunning:   "Запущен",
+	StatusPaused:    "На паузе",
+	StatusStopped:   "Остановлен",
+	StatusConcluded: "Завершен",
+	StatusArchived:  "В архиве",
+}
+
+// statusTransitions допустимые переходы между статусами: остановка — досрочное прекращение,
+// завершение — подведение итогов, архив — конечное состояние
+var statusTransitions = map[string][]string{
+	StatusDraft:     {StatusScheduled, StatusRunning, StatusArchived},
+	StatusScheduled: {StatusDraft, StatusRunning, StatusArchived},
+	StatusRunning:   {StatusPaused, StatusStopped, StatusConcluded},
+	StatusPaused:    {StatusRunning, StatusStopped, StatusConcluded},
+	StatusStopped:   {StatusConcluded, StatusArchived},
+	StatusConcluded: {StatusArchived},
+	StatusArchived:  {},
+}
+
+// StatusChange представляет смену статуса эксперимента
+type StatusChange struct {
+	ID           int       `db:"id" json:"id"`
+	ExperimentID int       `db:"experiment_id" json:"experiment_id"`
+	FromStatus   string    `db:"from_status" json:"from_status,omitempty"` // пусто для начального статуса
+	ToStatus     string    `db:"to_status" json:"to_status"`
+	Reason       string    `db:"reason" json:"reason"`
+	ChangedAt    time.Time `db:"changed_at" json:"changed_at"`
+}
+
+// возврат имени таблицы в БД
+func (StatusChange) TableName() string {
+	return "experiment_status_history"
+}
+
+// StatusTitle возвращает название статуса для отображения
+func StatusTitle(status string) string {
+	if title, ok := StatusTitles[status]; ok {
+		return title
+	}
+	return status
+}
+
+// IsValidStatus проверяет, что статус входит в жизненный цикл эксперимента
+func IsValidStatus(status string) bool {
+	return slices.Contains(ExperimentStatuses, status)
+}
+
+// NextStatuses возвращает статусы, в которые эксперимент может перейти из status
+func NextStatuses(status string) []string {
+	return statusTransitions[status]
+}
+
+// ValidateTransition проверяет допустимость перехода эксперимента из статуса from в статус to
+func ValidateTransition(from, to, reason string) error {
+	if !IsValidStatus(to) {
+		return fmt.Errorf("неизвестный статус эксперимента %q", to)
+	}
+	if from == to {
+		return fmt.Errorf("эксперимент уже в статусе «%s»", StatusTitle(to))
+	}
+	if !slices.Contains(statusTransitions[from], to) {
+		return fmt.Errorf("переход из статуса «%s» в «%s» недопустим", StatusTitle(from), StatusTitle(to))
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return errors.New("укажите причину смены статуса")
+	}
+	if len(reason) > 1000 {
+		return errors.New("причина смены статуса слишком длинная (максимум 1000 символов)")
+	}
+	return nil
+}
+
+// ValidateSchedule проверяет плановые даты эксперимента в статусе status:
+// запланированному эксперименту нужна дата запуска, завершение должно быть позже запуска
+func ValidateSchedule(status string, plannedStart, plannedEnd *time.Time) error {
+	if status == StatusScheduled && plannedStart == nil {
+		return errors.New("для запланированного эксперимента укажите плановую дату запуска")
+	}
+	if plannedStart != nil && plannedEnd != nil && !plannedEnd.After(*plannedStart) {
+		return errors.New("плановое завершение должно быть позже планового запуска")
+	}
+	return nil
+}
+
+// ValidateUpdate проверяет, что изменения эксперимента допустимы в его текущем статусе.
+// Черновик и запланированный эксперимент без пользователей меняются полностью; у запущенного
+// и приостановленного варианты не меняются, а процент пользователей можно только увеличить
+// (кроме экспериментов слоя); у остановленного, завершенного и архивного меняются только название и теги.
+// Статус, расписание и слой редактированием не меняются
+func ValidateUpdate(current, updated *Experiment, hasUsers bool) error {
+	if (current.Status == StatusDraft || current.Status == StatusScheduled) && !hasUsers {
+		return nil
+	}
+
+	if !SameVariants(current.VariantList(), updated.VariantList()) {
+		if hasUsers {
+			return errors.New("в эксперименте уже есть пользователи: варианты, алгоритмы, веса и параметры не меняются")
+		}
+		return fmt.Errorf("у эксперимента в статусе «%s» варианты не меняются", StatusTitle(current.Status))
+	}
+	if updated.UserPercent == current.UserPercent {
+		return nil
+	}
+	switch {
+	case current.Status != StatusRunning && current.Status != StatusPaused:
+		return fmt.Errorf("у эксперимента в статусе «%s» процент пользователей не меняется", StatusTitle(current.Status))
+	case current.LayerID != 0:
+		return errors.New("у запущенного эксперимента слоя процент пользователей не меняется: диапазон слоя уже используется")
+	case updated.UserPercent < current.UserPercent:
+		return errors.New("у запущенного эксперимента процент пользователей можно только увеличить")
+	}
+	return nil
+}
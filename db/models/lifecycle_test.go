@@ -0,0 +1,114 @@
+package models
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateTransition(t *testing.T) {
+	// допустимые переходы перечислены явно, чтобы тест не повторял таблицу statusTransitions
+	allowed := map[[2]string]bool{
+		{StatusDraft, StatusScheduled}:    true,
+		{StatusDraft, StatusRunning}:      true,
+		{StatusDraft, StatusArchived}:     true,
+		{StatusScheduled, StatusDraft}:    true,
+		{StatusScheduled, StatusRunning}:  true,
+		{StatusScheduled, StatusArchived}: true,
+		{StatusRunning, StatusPaused}:     true,
+		{StatusRunning, StatusStopped}:    true,
+		{StatusRunning, StatusConcluded}:  true,
+		{StatusPaused, StatusRunning}:     true,
+		{StatusPaused, StatusStopped}:     true,
+		{StatusPaused, StatusConcluded}:   true,
+		{StatusStopped, StatusConcluded}:  true,
+		{StatusStopped, StatusArchived}:   true,
+		{StatusConcluded, StatusArchived}: true,
+	}
+	for _, from := range ExperimentStatuses {
+		for _, to := range ExperimentStatuses {
+			err := ValidateTransition(from, to, "плановая смена статуса")
+			if want := allowed[[2]string{from, to}]; want != (err == nil) {
+				t.Errorf("%s → %s: ошибка %v, ожидается допустимость %v", from, to, err, want)
+			}
+		}
+	}
+
+	tests := []struct {
+		name, from, to, reason string
+	}{
+		{"неизвестный статус", StatusDraft, "active", "причина"},
+		{"неизвестный исходный статус", "active", StatusRunning, "причина"},
+		{"без причины", StatusRunning, StatusPaused, "   "},
+		{"длинная причина", StatusRunning, StatusPaused, strings.Repeat("п", 501)},
+	}
+	for _, tt := range tests {
+		if err := ValidateTransition(tt.from, tt.to, tt.reason); err == nil {
+			t.Errorf("%s: ожидается ошибка", tt.name)
+		}
+	}
+	if err := ValidateTransition(StatusRunning, StatusPaused, strings.Repeat("p", 1000)); err != nil {
+		t.Errorf("причина из 1000 символов: неожиданная ошибка: %v", err)
+	}
+}
+
+func TestValidateSchedule(t *testing.T) {
+	start := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	tests := []struct {
+		name                     string
+		status                   string
+		plannedStart, plannedEnd *time.Time
+		wantErr                  bool
+	}{
+		{"черновик без дат", StatusDraft, nil, nil, false},
+		{"запланирован с датой запуска", StatusScheduled, &start, nil, false},
+		{"запланирован без даты запуска", StatusScheduled, nil, &end, true},
+		{"запущен с датой завершения", StatusRunning, nil, &end, false},
+		{"завершение позже запуска", StatusScheduled, &start, &end, false},
+		{"завершение раньше запуска", StatusDraft, &end, &start, true},
+		{"завершение в момент запуска", StatusDraft, &start, &start, true},
+	}
+	for _, tt := range tests {
+		if err := ValidateSchedule(tt.status, tt.plannedStart, tt.plannedEnd); (err != nil) != tt.wantErr {
+			t.Errorf("%s: ошибка %v, ожидается ошибка: %v", tt.name, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateUpdate(t *testing.T) {
+	experiment := func(status string, percent float64, layerID int, algorithmB string) *Experiment {
+		return &Experiment{Status: status, UserPercent: percent, LayerID: layerID,
+			AlgorithmA: "popular", AlgorithmB: algorithmB}
+	}
+	tests := []struct {
+		name             string
+		current, updated *Experiment
+		hasUsers         bool
+		wantErr          bool
+	}{
+		{"черновик меняется полностью", experiment(StatusDraft, 10, 0, "svd"), experiment(StatusDraft, 5, 0, "knn"), false, false},
+		{"запланированный без пользователей", experiment(StatusScheduled, 10, 0, "svd"), experiment(StatusScheduled, 5, 0, "knn"), false, false},
+		{"черновик с пользователями: варианты", experiment(StatusDraft, 10, 0, "svd"), experiment(StatusDraft, 10, 0, "knn"), true, true},
+		{"черновик с пользователями: название", experiment(StatusDraft, 10, 0, "svd"), experiment(StatusDraft, 10, 0, "svd"), true, false},
+		{"запущенный: варианты", experiment(StatusRunning, 10, 0, "svd"), experiment(StatusRunning, 10, 0, "knn"), true, true},
+		{"запущенный: рост процента", experiment(StatusRunning, 10, 0, "svd"), experiment(StatusRunning, 20, 0, "svd"), true, false},
+		{"приостановленный: рост процента", experiment(StatusPaused, 10, 0, "svd"), experiment(StatusPaused, 20, 0, "svd"), true, false},
+		{"запущенный: снижение процента", experiment(StatusRunning, 10, 0, "svd"), experiment(StatusRunning, 5, 0, "svd"), true, true},
+		{"запущенный в слое: рост процента", experiment(StatusRunning, 10, 3, "svd"), experiment(StatusRunning, 20, 3, "svd"), true, true},
+		{"остановленный: процент", experiment(StatusStopped, 10, 0, "svd"), experiment(StatusStopped, 20, 0, "svd"), true, true},
+		{"остановленный: название", experiment(StatusStopped, 10, 0, "svd"), experiment(StatusStopped, 10, 0, "svd"), true, false},
+		{"архивный: варианты без пользователей", experiment(StatusArchived, 10, 0, "svd"), experiment(StatusArchived, 10, 0, "knn"), false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateUpdate(tt.current, tt.updated, tt.hasUsers)
+			if tt.wantErr && err == nil {
+				t.Fatal("ожидается ошибка")
+			}
+			if !tt.wantErr && err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package models
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// типы метрик реестра
+const (
+	MetricTypeProportion = "proportion" // доля: сумма числителя / сумма знаменателя, z-тест
+	MetricTypeMean       = "mean"       // среднее значения на пользователя, t-тест Уэлча
+	MetricTypeRatio      = "ratio"      // отношение сумм по пользователям, дельта-метод
+)
+
+// MetricTypes типы метрик, доступные в реестре
+var MetricTypes = []string{MetricTypeProportion, MetricTypeMean, MetricTypeRatio}
+
+// допустимое системное имя метрики
+var metricNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// Metric представляет определение метрики в реестре. Числитель и знаменатель —
+// агрегатные SQL-выражения, вычисляемые для каждого пользователя по его результатам
+// (псевдонимы: u — users, r — results)
+type Metric struct {
+	ID             int       `db:"id" json:"id"`
+	Name           string    `db:"name" json:"name"`
+	Title          string    `db:"title" json:"title"`
+	Type           string    `db:"metric_type" json:"metric_type"`
+	NumeratorSQL   string    `db:"numerator_sql" json:"numerator_sql"`
+	DenominatorSQL string    `db:"denominator_sql" json:"denominator_sql"` // для mean может быть пустым
+	HigherIsBetter bool      `db:"higher_is_better" json:"higher_is_better"`
+	CreatedAt      time.Time `db:"created_at" json:"created_at"`
+}
+
+// возврат имени таблицы в БД
+func (Metric) TableName() string {
+	return "metrics"
+}
+
+// проверка корректности определения метрики
+func (m *Metric) Validate() error {
+	if !metricNamePattern.MatchString(m.Name) || len(m.Name) > 100 {
+		return errors.New("имя метрики должно состоять из строчных латинских букв, цифр и '_' и начинаться с буквы")
+	}
+	if strings.TrimSpace(m.Title) == "" {
+		return errors.New("название метрики не может быть пустым")
+	}
+	switch m.Type {
+	case MetricTypeProportion, MetricTypeMean, MetricTypeRatio:
+	default:
+		return errors.New("тип метрики должен быть proportion, mean или ratio")
+	}
+	if strings.TrimSpace(m.NumeratorSQL) == "" {
+		return errors.New("выражение числителя не может быть пустым")
+	}
+	if m.Type != MetricTypeMean && strings.TrimSpace(m.DenominatorSQL) == "" {
+		return errors.New("для метрик proportion и ratio нужно выражение знаменателя")
+	}
+	for _, expr := range []string{m.NumeratorSQL, m.DenominatorSQL} {
+		if strings.Contains(expr, ";") || strings.Contains(expr, "--") || strings.Contains(expr, "/*") {
+			return errors.New("выражение метрики не может содержать ';' и комментарии")
+		}
+	}
+	return nil
+}
+
+// MetricComparison представляет сравнение метрики тестовой группы с контролем
+type MetricComparison struct {
+	ControlGroup   string  `json:"control_group"`
+	TreatmentGroup string  `json:"treatment_group"`
+	AbsoluteLift   float64 `json:"absolute_lift"`
+	RelativeLift   float64 `json:"relative_lift"`
+	StandardError  float64 `json:"standard_error"`
+	PValue         float64 `json:"p_value"`
+	CILower        float64 `json:"ci_lower"`
+	CIUpper        float64 `json:"ci_upper"`
+	Significant    bool    `json:"significant"`
+	Improvement    bool    `json:"improvement"` // значимое изменение в сторону улучшения
+}
+
+// MetricGroupValue представляет значение метрики в группе
+type MetricGroupValue struct {
+	Group       string  `json:"group"`
+	Users       int     `json:"users"` // пользователей, по которым определена метрика
+	Value       float64 `json:"value"`
+	Numerator   float64 `json:"numerator"`
+	Denominator float64 `json:"denominator"`
+}
+
+// MetricResult представляет значения и сравнения одной метрики реестра
+type MetricResult struct {
+	Metric      Metric             `json:"metric"`
+	Groups      []MetricGroupValue `json:"groups"`
+	Comparisons []MetricComparison `json:"comparisons,omitempty"`
+	Error       string             `json:"error,omitempty"` // ошибка вычисления метрики
+}
+
+// MetricsReport представляет вычисление всех метрик реестра для эксперимента
+type MetricsReport struct {
+	ExperimentID int            `json:"experiment_id"`
+	Confidence   float64        `json:"confidence"`
+	Metrics      []MetricResult `json:"metrics"`
+}
@@ -12,6 +12,7 @@ type Result struct {
 	Clicked          bool       `db:"clicked" json:"clicked"`
 	ClickedAt        *time.Time `db:"clicked_at" json:"clicked_at"`
 	Rating           int        `db:"rating" json:"rating"`
+	CreatedAt        time.Time  `db:"created_at" json:"created_at"` // время показа рекомендации
 }
 
 // возврат имени таблицы в БД
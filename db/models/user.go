@@ -1,12 +1,21 @@
 package models
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 type User struct {
-	ID           int    `db:"id" json:"id"`
-	ExperimentId int    `db:"experiment_id" json:"experiment_id"`
-	UserId       string `db:"user_id" json:"user_id"`
-	GroupName    string `db:"group_name" json:"group_name"`
+	ID           int       `db:"id" json:"id"`
+	ExperimentId int       `db:"experiment_id" json:"experiment_id"`
+	UserId       string    `db:"user_id" json:"user_id"`
+	GroupName    string    `db:"group_name" json:"group_name"`
+	AssignedAt   time.Time `db:"assigned_at" json:"assigned_at"`
+
+	// атрибуты пользователя для сегментного анализа, пустая строка — не указано
+	Device  string `db:"device" json:"device,omitempty"`
+	Country string `db:"country" json:"country,omitempty"`
+	Cohort  string `db:"cohort" json:"cohort,omitempty"`
 }
 
 // возврат имени таблицы в БД
@@ -25,8 +34,23 @@ func (u *User) Validate() error {
 	if len(u.UserId) > 255 {
 		return errors.New("айди не может слишком длинным")
 	}
-	if u.GroupName != "A" && u.GroupName != "B" {
-		return errors.New("группа должна быть 'A' или 'B'")
+	if len(u.Device) > 100 || len(u.Country) > 100 || len(u.Cohort) > 100 {
+		return errors.New("устройство, страна и когорта не могут быть длиннее 100 символов")
+	}
+	if u.GroupName == "" {
+		return errors.New("группа не может быть пустой")
+	}
+	if len(u.GroupName) > 10 {
+		return errors.New("название группы слишком длинное (максимум 10 символов)")
 	}
 	return nil
 }
+
+// Assignment представляет результат распределения пользователя в эксперимент
+type Assignment struct {
+	ExperimentID int    `json:"experiment_id"`
+	UserID       string `json:"user_id"`
+	Eligible     bool   `json:"eligible"`             // попал в долю трафика experiments.user_percent
+	GroupName    string `json:"group_name,omitempty"` // пусто, если пользователь не попал в эксперимент
+	Created      bool   `json:"created"`              // назначение сохранено этим вызовом
+}
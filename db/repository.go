@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"testing-platform/db/models"
 	"testing-platform/pkg/logger"
@@ -69,9 +70,20 @@ func (r *Repository) FixMigrations(ctx context.Context) error {
 		return fmt.Errorf("не удалось создать миграцию: %w", err)
 	}
 
-	// Пытаемся починить "грязное" состояние
-	if err := m.Force(3); err != nil {
-		logger.Warn("Не удалось принудительно установить версию 3: %v", err)
+	// Пытаемся починить "грязное" состояние: отметка переводится на предыдущую версию,
+	// чтобы прерванная миграция применилась заново
+	version, dirty, err := m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		logger.Warn("Не удалось получить версию миграций: %v", err)
+	}
+	if dirty {
+		previous := int(version) - 1
+		if previous < 1 {
+			previous = -1 // миграции еще не применялись
+		}
+		if err := m.Force(previous); err != nil {
+			logger.Warn("Не удалось принудительно установить версию %d: %v", previous, err)
+		}
 	}
 
 	// Применяем миграции
@@ -113,28 +125,71 @@ func (r *Repository) CreateExperiment(ctx context.Context, exp *models.Experimen
 	if err := exp.Validate(); err != nil {
 		return err
 	}
+	if exp.Status != "" && !slices.Contains(models.InitialStatuses, exp.Status) {
+		return errors.New("эксперимент можно создать только черновиком, запланированным или запущенным")
+	}
 	// начало транзакции только для операций с бд
 	tx, err := r.pool.Begin(ctx)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback(ctx)
+	if err := setActor(ctx, tx); err != nil {
+		return err
+	}
 
 	logger.Info("Выполнение DML: создание эксперимента '%s'", exp.Name)
 
-	sql := `INSERT INTO experiments (name, algorithm_a, algorithm_b, user_percent, is_active, tags) 
-             VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, start_date`
+	// алгоритмы первых двух вариантов дублируются в algorithm_a и algorithm_b
+	variants := append([]models.Variant(nil), exp.VariantList()...)
+	exp.AlgorithmA, exp.AlgorithmB = variants[0].Algorithm, variants[1].Algorithm
+	if err := checkAlgorithms(ctx, tx, variants); err != nil {
+		return err
+	}
+
+	// эксперимент слоя получает свободный диапазон трафика слоя шириной user_percent
+	var layerID *int
+	var layerStart, layerEnd *float64
+	if exp.LayerID != 0 {
+		start, err := allocateLayerRange(ctx, tx, exp.LayerID, exp.UserPercent, 0)
+		if err != nil {
+			return err
+		}
+		exp.LayerStart, exp.LayerEnd = start, start+exp.UserPercent
+		layerID, layerStart, layerEnd = &exp.LayerID, &exp.LayerStart, &exp.LayerEnd
+	}
+
+	// без явного статуса эксперимент создается запущенным или черновиком по флагу активности
+	if exp.Status == "" {
+		exp.Status = models.StatusDraft
+		if exp.IsActive {
+			exp.Status = models.StatusRunning
+		}
+	}
+	exp.IsActive = exp.Status == models.StatusRunning
+
+	sql := `INSERT INTO experiments (name, algorithm_a, algorithm_b, user_percent, is_active, tags,
+	                                 layer_id, layer_range_start, layer_range_end, status, planned_start, planned_end) 
+             VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12) RETURNING id, start_date, status_changed_at, version`
 
-	err = tx.QueryRow(ctx, sql, exp.Name, exp.AlgorithmA, exp.AlgorithmB, exp.UserPercent, exp.IsActive, exp.Tags).Scan(&exp.ID, &exp.StartDate)
+	err = tx.QueryRow(ctx, sql, exp.Name, exp.AlgorithmA, exp.AlgorithmB, exp.UserPercent, exp.IsActive, exp.Tags,
+		layerID, layerStart, layerEnd, exp.Status, exp.PlannedStart, exp.PlannedEnd).Scan(&exp.ID, &exp.StartDate, &exp.StatusChangedAt, &exp.Version)
 
 	if err != nil {
 		logger.Error("Ошибка при создании эксперимента: %v", err)
 		return fmt.Errorf("не удалось создать эксперимент: %w", err)
 	}
+	if err := insertVariants(ctx, tx, exp.ID, variants); err != nil {
+		return err
+	}
+	if err := insertStatusChange(ctx, tx, exp.ID, "", exp.Status, "создание эксперимента"); err != nil {
+		return err
+	}
 	// если все успешно, то деламе коммит транзакции
 	if err := tx.Commit(ctx); err != nil {
 		return err
 	}
+	exp.Variants = variants
 
 	logger.Info("Эксперимент '%s' успешно создан с ID %d", exp.Name, exp.ID)
 	return nil
@@ -152,9 +207,32 @@ func (r *Repository) AddUserToExperiment(ctx context.Context, user *models.User)
 	defer tx.Rollback(ctx)
 
 	logger.Info("Добавление пользователя %s в эксперимент %d (группа %s)", user.UserId, user.ExperimentId, user.GroupName)
-	sql := `INSERT INTO users (experiment_id, user_id, group_name) VALUES ($1, $2, $3)`
 
-	_, err = tx.Exec(ctx, sql, user.ExperimentId, user.UserId, user.GroupName)
+	// группа должна быть одним из вариантов эксперимента
+	var isVariant bool
+	err = tx.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM variants WHERE experiment_id = $1 AND name = $2)`,
+		user.ExperimentId, user.GroupName).Scan(&isVariant)
+	if err != nil {
+		logger.Error("Ошибка при проверке группы пользователя: %v", err)
+		return fmt.Errorf("не удалось проверить группу пользователя: %w", err)
+	}
+	if !isVariant {
+		return fmt.Errorf("в эксперименте %d нет группы %q", user.ExperimentId, user.GroupName)
+	}
+
+	// эксперименты одного слоя не пересекаются по пользователям
+	other, err := layerConflict(ctx, tx, user.ExperimentId, user.UserId)
+	if err != nil {
+		return err
+	}
+	if other != 0 {
+		return fmt.Errorf("пользователь %s уже участвует в эксперименте %d того же слоя", user.UserId, other)
+	}
+
+	sql := `INSERT INTO users (experiment_id, user_id, group_name, device, country, cohort)
+	         VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''))`
+
+	_, err = tx.Exec(ctx, sql, user.ExperimentId, user.UserId, user.GroupName, user.Device, user.Country, user.Cohort)
 	if err != nil {
 		logger.Error("Ошибка при добавлении пользователя в эксперимент: %v", err)
 		return fmt.Errorf("не удалось добавить пользователя в эксперимент: %w", err)
@@ -185,9 +263,9 @@ func (r *Repository) AddResult(ctx context.Context, res *models.Result) error {
 
 	if res.Clicked {
 		if res.ClickedAt != nil {
-			// если время клика указано явно
-			sql = `INSERT INTO results (user_id, recommendation_id, clicked, clicked_at, rating) 
-                   VALUES ($1, $2, $3, $4, $5)`
+			// если время клика указано явно, показ считается произошедшим в то же время
+			sql = `INSERT INTO results (user_id, recommendation_id, clicked, clicked_at, rating, created_at) 
+                   VALUES ($1, $2, $3, $4, $5, $4)`
 			args = []any{res.UserId, res.RecommendationId, res.Clicked, res.ClickedAt, res.Rating}
 		} else {
 			// если время клика не указано - используем текущее время
@@ -222,7 +300,9 @@ func (r *Repository) Pool() *pgxpool.Pool {
 func (r *Repository) GetExperiments(ctx context.Context, filter models.ExperimentFilter) ([]models.Experiment, error) {
 	logger.Info("Запрос списка экспериментов с фильтром: %+v", filter)
 	// базовый SQL запрос без условий фильтрации
-	baseQuery := `SELECT id, name, algorithm_a, algorithm_b, user_percent, start_date, is_active, tags 
+	baseQuery := `SELECT id, name, algorithm_a, algorithm_b, user_percent, start_date, is_active, tags,
+                        COALESCE(layer_id, 0), COALESCE(layer_range_start, 0), COALESCE(layer_range_end, 0),
+                        status, status_changed_at, planned_start, planned_end, version
                  FROM experiments WHERE 1=1`
 	// слайс для хранения значений параметров запроса (защита от SQL-инъекций)
 	var args []any
@@ -242,6 +322,11 @@ func (r *Repository) GetExperiments(ctx context.Context, filter models.Experimen
 		conditions = append(conditions, fmt.Sprintf("is_active = $%d", len(args)+1))
 		args = append(args, *filter.IsActive)
 	}
+	// условие фильтрации по статусу жизненного цикла, если указан
+	if filter.Status != "" {
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)+1))
+		args = append(args, filter.Status)
+	}
 	// добавление условия фильтрации по начальной дате, если указана / IsZero() проверяет, что дата не нулевая (не time.Time{})
 	if !filter.StartDateFrom.IsZero() {
 		conditions = append(conditions, fmt.Sprintf("start_date >= $%d", len(args)+1))
@@ -271,23 +356,49 @@ func (r *Repository) GetExperiments(ctx context.Context, filter models.Experimen
 	for rows.Next() {
 		var exp models.Experiment
 		err := rows.Scan(&exp.ID, &exp.Name, &exp.AlgorithmA, &exp.AlgorithmB,
-			&exp.UserPercent, &exp.StartDate, &exp.IsActive, &exp.Tags)
+			&exp.UserPercent, &exp.StartDate, &exp.IsActive, &exp.Tags,
+			&exp.LayerID, &exp.LayerStart, &exp.LayerEnd, &exp.Status, &exp.StatusChangedAt,
+			&exp.PlannedStart, &exp.PlannedEnd, &exp.Version)
 		if err != nil {
 			logger.Error("Ошибка при сканировании строки эксперимента: %v", err)
 			continue
 		}
 		experiments = append(experiments, exp)
 	}
+	if err := r.attachVariants(ctx, experiments); err != nil {
+		return nil, err
+	}
 
 	logger.Info("Получено %d экспериментов", len(experiments))
 	return experiments, nil
 }
 
+// возвращение эксперимента по ID
+func (r *Repository) GetExperiment(ctx context.Context, experimentID int) (*models.Experiment, error) {
+	sql := `SELECT id, name, algorithm_a, algorithm_b, user_percent, start_date, is_active, tags,
+	                COALESCE(layer_id, 0), COALESCE(layer_range_start, 0), COALESCE(layer_range_end, 0),
+	                status, status_changed_at, planned_start, planned_end, version
+	         FROM experiments WHERE id = $1`
+
+	var exp models.Experiment
+	err := r.pool.QueryRow(ctx, sql, experimentID).Scan(&exp.ID, &exp.Name, &exp.AlgorithmA, &exp.AlgorithmB,
+		&exp.UserPercent, &exp.StartDate, &exp.IsActive, &exp.Tags, &exp.LayerID, &exp.LayerStart, &exp.LayerEnd,
+		&exp.Status, &exp.StatusChangedAt, &exp.PlannedStart, &exp.PlannedEnd, &exp.Version)
+	if err != nil {
+		logger.Error("Ошибка при получении эксперимента %d: %v", experimentID, err)
+		return nil, fmt.Errorf("не удалось получить эксперимент %d: %w", experimentID, err)
+	}
+	if exp.Variants, err = r.GetVariants(ctx, experimentID); err != nil {
+		return nil, err
+	}
+	return &exp, nil
+}
+
 // возвращение результатов для конкретного эксперимента
 func (r *Repository) GetExperimentResults(ctx context.Context, experimentID int) ([]models.Result, error) {
 	logger.Info("Запрос результатов эксперимента %d", experimentID)
 
-	sql := `SELECT r.id, r.user_id, r.recommendation_id, r.clicked, r.clicked_at, r.rating
+	sql := `SELECT r.id, r.user_id, r.recommendation_id, r.clicked, r.clicked_at, r.rating, r.created_at
 	         FROM results r
 	         JOIN users u ON r.user_id = u.id
 	         WHERE u.experiment_id = $1`
@@ -303,7 +414,7 @@ func (r *Repository) GetExperimentResults(ctx context.Context, experimentID int)
 	for rows.Next() {
 		var res models.Result
 		err := rows.Scan(&res.ID, &res.UserId, &res.RecommendationId,
-			&res.Clicked, &res.ClickedAt, &res.Rating)
+			&res.Clicked, &res.ClickedAt, &res.Rating, &res.CreatedAt)
 		if err != nil {
 			logger.Error("Ошибка при сканировании строки результата: %v", err)
 			continue
@@ -315,31 +426,29 @@ func (r *Repository) GetExperimentResults(ctx context.Context, experimentID int)
 	return results, nil
 }
 
-// обновление статуса эксперимента
+// обновление статуса эксперимента: включение запускает эксперимент, выключение ставит его на паузу.
+// Переход проверяется по жизненному циклу, см. TransitionExperiment
 func (r *Repository) UpdateExperimentStatus(ctx context.Context, experimentID int, isActive bool) error {
-	status := "активирован"
+	status, reason := models.StatusRunning, "эксперимент активирован"
 	if !isActive {
-		status = "деактивирован"
-	}
-
-	logger.Info("Обновление статуса эксперимента %d: %s", experimentID, status)
-
-	sql := `UPDATE experiments SET is_active = $1 WHERE id = $2`
-
-	_, err := r.pool.Exec(ctx, sql, isActive, experimentID)
-	if err != nil {
-		logger.Error("Ошибка при обновлении статуса эксперимента: %v", err)
-		return fmt.Errorf("не удалось обновить статус эксперимента: %w", err)
+		status, reason = models.StatusPaused, "эксперимент деактивирован"
 	}
+	return r.TransitionExperiment(ctx, experimentID, status, reason)
+}
 
-	logger.Info("Статус эксперимента %d успешно обновлен", experimentID)
-	return nil
+// возвращение статистики по эксперименту с уровнем доверия по умолчанию
+func (r *Repository) GetExperimentStats(ctx context.Context, experimentID int) (*models.ExperimentStats, error) {
+	return r.GetExperimentStatsWithConfidence(ctx, experimentID, models.DefaultConfidence)
 }
 
 // возвращение статистики по эксперименту
-// считает статистику по группам пользователей: количество рекомендаций, кликов, средний рейтинг и CTR (метрика кликабельности)
-func (r *Repository) GetExperimentStats(ctx context.Context, experimentID int) (*models.ExperimentStats, error) {
-	logger.Info("Запрос статистики для эксперимента %d", experimentID)
+// считает статистику по группам пользователей: количество рекомендаций, кликов, средний рейтинг и CTR (метрика кликабельности),
+// а также z-тест CTR каждой тестовой группы против контрольной с заданным уровнем доверия
+func (r *Repository) GetExperimentStatsWithConfidence(ctx context.Context, experimentID int, confidence float64) (*models.ExperimentStats, error) {
+	logger.Info("Запрос статистики для эксперимента %d (уровень доверия %.2f)", experimentID, confidence)
+	if confidence <= 0 || confidence >= 1 {
+		return nil, errors.New("уровень доверия должен быть в интервале (0, 1)")
+	}
 
 	// получение тегов эксперимента
 	var tags []string
@@ -348,10 +457,11 @@ func (r *Repository) GetExperimentStats(ctx context.Context, experimentID int) (
 		logger.Error("Ошибка при получении тегов эксперимента: %v", err)
 		return nil, fmt.Errorf("не удалось получить теги эксперимента: %w", err)
 	}
-	// SQL запрос для агрегации статистики по группам (группировка по группам A/B/общее количество рекомендаций/кол-во кликов/средний рейтинг)
+	// SQL запрос для агрегации статистики по группам (группировка по вариантам/общее количество рекомендаций/кол-во кликов/средний рейтинг)
 	sql := `
 		SELECT 
 			u.group_name,
+			COUNT(DISTINCT u.id) as total_users,
 			COUNT(r.id) as total_recommendations,
 			SUM(CASE WHEN r.clicked THEN 1 ELSE 0 END) as total_clicks,
 			AVG(CASE WHEN r.rating > 0 THEN r.rating::float ELSE NULL END) as avg_rating
@@ -372,18 +482,19 @@ func (r *Repository) GetExperimentStats(ctx context.Context, experimentID int) (
 		ExperimentID: experimentID,
 		Groups:       make(map[string]models.GroupStats),
 		Tags:         tags,
+		Confidence:   confidence,
 	}
 
-	var totalRec, totalClicks int
+	var totalUsers, totalRec, totalClicks int
 	var totalRating, totalCTR float64
 	var groupCount int
 	// итерация по результатам запроса (по каждой группе)
 	for rows.Next() {
 		var group string
-		var groupRec, groupClicks int
+		var groupUsers, groupRec, groupClicks int
 		var avgRating *float64
 
-		err := rows.Scan(&group, &groupRec, &groupClicks, &avgRating)
+		err := rows.Scan(&group, &groupUsers, &groupRec, &groupClicks, &avgRating)
 		if err != nil {
 			logger.Error("Ошибка при сканировании строки статистики: %v", err)
 			continue
@@ -391,6 +502,7 @@ func (r *Repository) GetExperimentStats(ctx context.Context, experimentID int) (
 
 		groupStats := models.GroupStats{
 			Group:                group,
+			Users:                groupUsers,
 			TotalRecommendations: groupRec,
 			TotalClicks:          groupClicks,
 		}
@@ -412,15 +524,30 @@ func (r *Repository) GetExperimentStats(ctx context.Context, experimentID int) (
 		stats.Groups[group] = groupStats
 
 		// сумма общей статистики
+		totalUsers += groupUsers
 		totalRec += groupRec
 		totalClicks += groupClicks
 		groupCount++
 	}
 
+	// алгоритмы вариантов для отображения рядом с группами
+	if variants, err := r.GetVariants(ctx, experimentID); err != nil {
+		logger.Warn("Варианты эксперимента %d недоступны: %v", experimentID, err)
+	} else {
+		for _, v := range variants {
+			if g, ok := stats.Groups[v.Name]; ok {
+				g.Algorithm = v.Algorithm
+				g.Params = models.FormatParams(v.Params)
+				stats.Groups[v.Name] = g
+			}
+		}
+	}
+
 	// рассчет общей статистики
 	if groupCount > 0 {
 		stats.TotalStats = models.GroupStats{
 			Group:                "total",
+			Users:                totalUsers,
 			TotalRecommendations: totalRec,
 			TotalClicks:          totalClicks,
 			AvgRating:            totalRating / float64(groupCount),
@@ -428,6 +555,23 @@ func (r *Repository) GetExperimentStats(ctx context.Context, experimentID int) (
 		}
 	}
 
+	// проверка статистической значимости различий CTR
+	stats.Comparisons = compareGroupsCTR(stats.Groups, confidence)
+
+	// проверка соотношения размеров групп
+	if srm, err := r.CheckSampleRatio(ctx, experimentID); err != nil {
+		logger.Warn("Проверка соотношения групп эксперимента %d не выполнена: %v", experimentID, err)
+	} else {
+		stats.SRM = srm
+	}
+
+	// текущее решение последовательного теста по сохраненным просмотрам (их фиксирует RecordSequentialLook)
+	if sequential, err := r.GetSequentialStatus(ctx, experimentID); err != nil {
+		logger.Warn("Состояние последовательного теста эксперимента %d недоступно: %v", experimentID, err)
+	} else {
+		stats.Sequential = sequential
+	}
+
 	logger.Info("Статистика для эксперимента %d успешно получена", experimentID)
 	return stats, nil
 }
@@ -442,6 +586,10 @@ func (r *Repository) GetExperimentResultsWithDetails(ctx context.Context) ([]mod
                 e.name, 
                 e.algorithm_a, 
                 e.algorithm_b, 
+                COALESCE((SELECT string_agg(v.algorithm::text ||
+                                     CASE WHEN v.params <> '{}' THEN ' ' || v.params::text ELSE '' END,
+                                     ', ' ORDER BY v.position)
+                          FROM variants v WHERE v.experiment_id = e.id), '') as algorithms,
                 COUNT(r.id) as total_results,
                 COALESCE(SUM(CASE WHEN r.clicked THEN 1 ELSE 0 END), 0) as total_clicks,
                 COALESCE(AVG(CASE WHEN r.rating > 0 THEN r.rating::float ELSE NULL END), 0) as avg_rating
@@ -464,7 +612,7 @@ func (r *Repository) GetExperimentResultsWithDetails(ctx context.Context) ([]mod
 	for rows.Next() {
 		var res models.ExperimentResult
 		var avgRating *float64
-		err := rows.Scan(&res.ID, &res.Name, &res.AlgorithmA, &res.AlgorithmB,
+		err := rows.Scan(&res.ID, &res.Name, &res.AlgorithmA, &res.AlgorithmB, &res.Algorithms,
 			&res.TotalResults, &res.TotalClicks, &avgRating)
 		if err != nil {
 			logger.Error("Ошибка при сканировании строки: %v", err)
@@ -0,0 +1,156 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing-platform/db/models"
+	"testing-platform/pkg/logger"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// GetAlgorithms возвращает алгоритмы реестра по имени; устаревшие — только при includeDeprecated
+func (r *Repository) GetAlgorithms(ctx context.Context, includeDeprecated bool) ([]models.Algorithm, error) {
+	sql := `SELECT name, description, owner, version, deprecated, created_at, params_schema
+	         FROM algorithms
+	         WHERE $1 OR NOT deprecated
+	         ORDER BY name`
+
+	rows, err := r.pool.Query(ctx, sql, includeDeprecated)
+	if err != nil {
+		logger.Error("Ошибка при запросе алгоритмов: %v", err)
+		return nil, fmt.Errorf("не удалось получить алгоритмы: %w", err)
+	}
+	defer rows.Close()
+
+	var algorithms []models.Algorithm
+	for rows.Next() {
+		var a models.Algorithm
+		if err := rows.Scan(&a.Name, &a.Description, &a.Owner, &a.Version, &a.Deprecated, &a.CreatedAt, &a.ParamsSchema); err != nil {
+			logger.Error("Ошибка при сканировании алгоритма: %v", err)
+			continue
+		}
+		algorithms = append(algorithms, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка обработки алгоритмов: %w", err)
+	}
+	return algorithms, nil
+}
+
+// CreateAlgorithm добавляет алгоритм в реестр
+func (r *Repository) CreateAlgorithm(ctx context.Context, a *models.Algorithm) error {
+	a.Version = strings.TrimSpace(a.Version)
+	if err := a.Validate(); err != nil {
+		return err
+	}
+
+	if a.ParamsSchema == nil {
+		a.ParamsSchema = map[string]any{}
+	}
+
+	logger.Info("Выполнение DML: регистрация алгоритма '%s' версии %s", a.Name, a.Version)
+	sql := `INSERT INTO algorithms (name, description, owner, version, deprecated, params_schema)
+	         VALUES ($1, $2, $3, $4, $5, $6) RETURNING created_at`
+
+	err := r.pool.QueryRow(ctx, sql, a.Name, a.Description, a.Owner, a.Version, a.Deprecated,
+		a.ParamsSchema).Scan(&a.CreatedAt)
+	if err != nil {
+		logger.Error("Ошибка при регистрации алгоритма: %v", err)
+		return fmt.Errorf("не удалось зарегистрировать алгоритм: %w", err)
+	}
+	return nil
+}
+
+// UpdateAlgorithm изменяет описание, владельца, версию, признак устаревания и схему параметров алгоритма;
+// имя не меняется. Схема применяется к параметрам новых экспериментов, сохраненные параметры не перепроверяются
+func (r *Repository) UpdateAlgorithm(ctx context.Context, a *models.Algorithm) error {
+	a.Version = strings.TrimSpace(a.Version)
+	if err := a.Validate(); err != nil {
+		return err
+	}
+	if a.ParamsSchema == nil {
+		a.ParamsSchema = map[string]any{}
+	}
+
+	logger.Info("Выполнение DML: изменение алгоритма '%s'", a.Name)
+	sql := `UPDATE algorithms SET description = $1, owner = $2, version = $3, deprecated = $4, params_schema = $5
+	         WHERE name = $6`
+
+	tag, err := r.pool.Exec(ctx, sql, a.Description, a.Owner, a.Version, a.Deprecated, a.ParamsSchema, a.Name)
+	if err != nil {
+		logger.Error("Ошибка при изменении алгоритма: %v", err)
+		return fmt.Errorf("не удалось изменить алгоритм: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("алгоритм %s не найден", a.Name)
+	}
+	return nil
+}
+
+// DeleteAlgorithm удаляет алгоритм, который не назначен ни одному варианту
+func (r *Repository) DeleteAlgorithm(ctx context.Context, name string) error {
+	logger.Info("Выполнение DML: удаление алгоритма '%s'", name)
+
+	var used int
+	err := r.pool.QueryRow(ctx, `SELECT COUNT(DISTINCT experiment_id) FROM variants WHERE algorithm = $1`, name).Scan(&used)
+	if err != nil {
+		return fmt.Errorf("не удалось проверить использование алгоритма: %w", err)
+	}
+	if used > 0 {
+		return fmt.Errorf("алгоритм %s используется в экспериментах (%d), пометьте его устаревшим вместо удаления", name, used)
+	}
+
+	tag, err := r.pool.Exec(ctx, `DELETE FROM algorithms WHERE name = $1`, name)
+	if err != nil {
+		logger.Error("Ошибка при удалении алгоритма: %v", err)
+		return fmt.Errorf("не удалось удалить алгоритм: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("алгоритм %s не найден", name)
+	}
+	return nil
+}
+
+// checkAlgorithms проверяет, что алгоритмы вариантов зарегистрированы в реестре и не устарели,
+// а параметры вариантов соответствуют схемам параметров алгоритмов
+func checkAlgorithms(ctx context.Context, tx pgx.Tx, variants []models.Variant) error {
+	names := make([]string, len(variants))
+	for i, v := range variants {
+		names[i] = v.Algorithm
+	}
+
+	rows, err := tx.Query(ctx, `SELECT name, deprecated, params_schema FROM algorithms WHERE name = ANY($1)`, names)
+	if err != nil {
+		logger.Error("Ошибка при проверке алгоритмов: %v", err)
+		return fmt.Errorf("не удалось проверить алгоритмы вариантов: %w", err)
+	}
+	algorithms := make(map[string]models.Algorithm)
+	for rows.Next() {
+		var a models.Algorithm
+		if err := rows.Scan(&a.Name, &a.Deprecated, &a.ParamsSchema); err != nil {
+			rows.Close()
+			return fmt.Errorf("ошибка чтения алгоритма: %w", err)
+		}
+		algorithms[a.Name] = a
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("ошибка обработки алгоритмов: %w", err)
+	}
+
+	for _, v := range variants {
+		a, ok := algorithms[v.Algorithm]
+		if !ok {
+			return fmt.Errorf("алгоритм %s варианта %s не зарегистрирован в реестре", v.Algorithm, v.Name)
+		}
+		if a.Deprecated {
+			return fmt.Errorf("алгоритм %s варианта %s устарел и не назначается новым экспериментам", v.Algorithm, v.Name)
+		}
+		if err := a.ValidateParams(v.Params); err != nil {
+			return fmt.Errorf("вариант %s: %w", v.Name, err)
+		}
+	}
+	return nil
+}
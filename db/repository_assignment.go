@@ -0,0 +1,132 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing-platform/db/models"
+	"testing-platform/pkg/bucketing"
+	"testing-platform/pkg/logger"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// AssignUser распределяет пользователя в эксперимент: хеш идентификатора с солью эксперимента
+// определяет попадание в долю трафика experiments.user_percent и вариант (пропорционально весам).
+// Для эксперимента в слое попадание определяется хешем по соли слоя и диапазоном эксперимента.
+// Назначение сохраняется один раз; повторные вызовы возвращают сохраненную группу
+func (r *Repository) AssignUser(ctx context.Context, experimentID int, userID string) (*models.Assignment, error) {
+	return r.AssignUserWithAttributes(ctx, &models.User{ExperimentId: experimentID, UserId: userID})
+}
+
+// AssignUserWithAttributes распределяет пользователя как AssignUser и при первом назначении
+// сохраняет его атрибуты для сегментного анализа; user.GroupName не учитывается
+func (r *Repository) AssignUserWithAttributes(ctx context.Context, user *models.User) (*models.Assignment, error) {
+	if user.ExperimentId <= 0 {
+		return nil, errors.New("айди эксперимента должен быть положительным")
+	}
+	// группа еще не известна, проверяются остальные поля
+	check := *user
+	check.GroupName = models.ControlGroup
+	if err := check.Validate(); err != nil {
+		return nil, err
+	}
+
+	assignment := &models.Assignment{ExperimentID: user.ExperimentId, UserID: user.UserId}
+	group, err := r.getAssignedGroup(ctx, user.ExperimentId, user.UserId)
+	if err != nil {
+		return nil, err
+	}
+	if group != "" {
+		assignment.Eligible, assignment.GroupName = true, group
+		return assignment, nil
+	}
+
+	var salt, layerSalt, status string
+	var percent, layerStart, layerEnd float64
+	err = r.pool.QueryRow(ctx, `SELECT e.salt, e.user_percent::float8, e.status, COALESCE(l.salt, ''),
+	                                   COALESCE(e.layer_range_start, 0)::float8, COALESCE(e.layer_range_end, 0)::float8
+	                            FROM experiments e
+	                            LEFT JOIN layers l ON l.id = e.layer_id
+	                            WHERE e.id = $1`,
+		user.ExperimentId).Scan(&salt, &percent, &status, &layerSalt, &layerStart, &layerEnd)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("эксперимент %d не найден", user.ExperimentId)
+	}
+	if err != nil {
+		logger.Error("Ошибка при получении параметров распределения эксперимента %d: %v", user.ExperimentId, err)
+		return nil, fmt.Errorf("не удалось получить параметры эксперимента: %w", err)
+	}
+	if status != models.StatusRunning {
+		return nil, fmt.Errorf("эксперимент %d в статусе «%s»: новые пользователи распределяются только в запущенный эксперимент",
+			user.ExperimentId, models.StatusTitle(status))
+	}
+
+	// в слое трафик делится хешем по соли слоя: пользователь попадает только в эксперимент,
+	// чей диапазон содержит его позицию; вне слоя доля трафика отбирается по соли эксперимента
+	if layerSalt != "" {
+		if !bucketing.InRange(layerSalt, user.UserId, layerStart, layerEnd) {
+			logger.Info("Пользователь %s вне диапазона [%.2f; %.2f) слоя эксперимента %d",
+				user.UserId, layerStart, layerEnd, user.ExperimentId)
+			return assignment, nil
+		}
+		other, err := layerConflict(ctx, r.pool, user.ExperimentId, user.UserId)
+		if err != nil {
+			return nil, err
+		}
+		if other != 0 {
+			logger.Warn("Пользователь %s уже участвует в эксперименте %d того же слоя", user.UserId, other)
+			return assignment, nil
+		}
+	} else if !bucketing.Eligible(salt, user.UserId, percent) {
+		logger.Info("Пользователь %s не попал в долю трафика эксперимента %d (%.2f%%)", user.UserId, user.ExperimentId, percent)
+		return assignment, nil
+	}
+	assignment.Eligible = true
+
+	variants, err := r.GetVariants(ctx, user.ExperimentId)
+	if err != nil {
+		return nil, err
+	}
+	variant, ok := models.PickVariant(variants, bucketing.Unit(salt, bucketing.PurposeVariant, user.UserId))
+	if !ok {
+		return nil, fmt.Errorf("у эксперимента %d нет вариантов", user.ExperimentId)
+	}
+
+	sql := `INSERT INTO users (experiment_id, user_id, group_name, device, country, cohort)
+	         VALUES ($1, $2, $3, NULLIF($4, ''), NULLIF($5, ''), NULLIF($6, ''))
+	         ON CONFLICT (experiment_id, user_id) DO NOTHING
+	         RETURNING group_name`
+
+	err = r.pool.QueryRow(ctx, sql, user.ExperimentId, user.UserId, variant.Name,
+		user.Device, user.Country, user.Cohort).Scan(&assignment.GroupName)
+	switch {
+	case errors.Is(err, pgx.ErrNoRows):
+		// пользователя одновременно назначил другой вызов — возвращается его результат
+		if assignment.GroupName, err = r.getAssignedGroup(ctx, user.ExperimentId, user.UserId); err != nil {
+			return nil, err
+		}
+	case err != nil:
+		logger.Error("Ошибка при сохранении назначения пользователя %s: %v", user.UserId, err)
+		return nil, fmt.Errorf("не удалось сохранить назначение пользователя: %w", err)
+	default:
+		assignment.Created = true
+		logger.Info("Пользователь %s назначен в группу %s эксперимента %d", user.UserId, assignment.GroupName, user.ExperimentId)
+	}
+	return assignment, nil
+}
+
+// getAssignedGroup возвращает сохраненную группу пользователя или пустую строку
+func (r *Repository) getAssignedGroup(ctx context.Context, experimentID int, userID string) (string, error) {
+	var group string
+	err := r.pool.QueryRow(ctx, `SELECT group_name FROM users WHERE experiment_id = $1 AND user_id = $2`,
+		experimentID, userID).Scan(&group)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		logger.Error("Ошибка при получении назначения пользователя %s: %v", userID, err)
+		return "", fmt.Errorf("не удалось получить назначение пользователя: %w", err)
+	}
+	return group, nil
+}
@@ -0,0 +1,191 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"testing-platform/db/models"
+	"testing-platform/pkg/logger"
+	"testing-platform/pkg/stats"
+)
+
+// UserMetric метрика, вычисляемая по набору пользователей эксперимента
+type UserMetric struct {
+	Name    string
+	Title   string
+	Compute func(users []models.UserObservation) float64
+}
+
+// UserMetrics метрики, доступные для бутстрэп-анализа
+var UserMetrics = []UserMetric{
+	{Name: "ctr", Title: "CTR", Compute: userCTR},
+	{Name: "clicks_per_user", Title: "Клики на пользователя", Compute: clicksPerUser},
+	{Name: "avg_rating", Title: "Средняя оценка", Compute: averageRating},
+	{Name: "median_rating", Title: "Медианная оценка", Compute: medianRating},
+}
+
+// LookupUserMetric возвращает метрику по имени
+func LookupUserMetric(name string) (UserMetric, bool) {
+	for _, m := range UserMetrics {
+		if m.Name == name {
+			return m, true
+		}
+	}
+	return UserMetric{}, false
+}
+
+func userCTR(users []models.UserObservation) float64 {
+	var clicks, recommendations int
+	for _, u := range users {
+		clicks += u.Clicks
+		recommendations += u.Recommendations
+	}
+	if recommendations == 0 {
+		return math.NaN()
+	}
+	return float64(clicks) / float64(recommendations)
+}
+
+func clicksPerUser(users []models.UserObservation) float64 {
+	if len(users) == 0 {
+		return math.NaN()
+	}
+	var clicks int
+	for _, u := range users {
+		clicks += u.Clicks
+	}
+	return float64(clicks) / float64(len(users))
+}
+
+func averageRating(users []models.UserObservation) float64 {
+	ratings := collectRatings(users)
+	if len(ratings) == 0 {
+		return math.NaN()
+	}
+	return stats.Summarize(ratings).Mean
+}
+
+func medianRating(users []models.UserObservation) float64 {
+	ratings := collectRatings(users)
+	if len(ratings) == 0 {
+		return math.NaN()
+	}
+	return stats.Median(ratings)
+}
+
+func collectRatings(users []models.UserObservation) []float64 {
+	var ratings []float64
+	for _, u := range users {
+		for _, r := range u.Ratings {
+			ratings = append(ratings, float64(r))
+		}
+	}
+	return ratings
+}
+
+// GetUserObservations возвращает наблюдения по каждому пользователю эксперимента, сгруппированные по группам
+func (r *Repository) GetUserObservations(ctx context.Context, experimentID int) (map[string][]models.UserObservation, error) {
+	sql := `SELECT
+	            u.id,
+	            u.group_name,
+	            COUNT(r.id),
+	            COUNT(r.id) FILTER (WHERE r.clicked),
+	            COALESCE(array_agg(r.rating) FILTER (WHERE r.rating > 0), '{}')
+	         FROM users u
+	         LEFT JOIN results r ON u.id = r.user_id
+	         WHERE u.experiment_id = $1
+	         GROUP BY u.id, u.group_name
+	         ORDER BY u.id`
+
+	rows, err := r.pool.Query(ctx, sql, experimentID)
+	if err != nil {
+		logger.Error("Ошибка при запросе наблюдений пользователей: %v", err)
+		return nil, fmt.Errorf("не удалось получить наблюдения пользователей: %w", err)
+	}
+	defer rows.Close()
+
+	users := make(map[string][]models.UserObservation)
+	for rows.Next() {
+		var u models.UserObservation
+		var ratings []int32
+		if err := rows.Scan(&u.UserID, &u.Group, &u.Recommendations, &u.Clicks, &ratings); err != nil {
+			logger.Error("Ошибка при сканировании наблюдений пользователя: %v", err)
+			continue
+		}
+		for _, rating := range ratings {
+			u.Ratings = append(u.Ratings, int(rating))
+		}
+		users[u.Group] = append(users[u.Group], u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка обработки наблюдений пользователей: %w", err)
+	}
+	return users, nil
+}
+
+// BootstrapExperiment строит бутстрэп-интервалы разности метрики каждой тестовой группы и контроля,
+// повторно выбирая пользователей (users.id). Расчет выполняется параллельно и прерывается отменой ctx;
+// progress (может быть nil) получает общее число выполненных итераций по всем сравнениям
+func (r *Repository) BootstrapExperiment(ctx context.Context, experimentID int, metric UserMetric,
+	opts stats.BootstrapOptions, progress func(done, total int)) (*models.BootstrapStats, error) {
+	logger.Info("Бутстрэп метрики %s для эксперимента %d (%d итераций, метод %s)",
+		metric.Name, experimentID, opts.Iterations, opts.Method)
+
+	users, err := r.GetUserObservations(ctx, experimentID)
+	if err != nil {
+		return nil, err
+	}
+	control, ok := users[models.ControlGroup]
+	if !ok {
+		return nil, fmt.Errorf("в эксперименте нет пользователей контрольной группы %s", models.ControlGroup)
+	}
+	if opts.Method == "" {
+		opts.Method = stats.BootstrapPercentile
+	}
+
+	result := &models.BootstrapStats{
+		ExperimentID: experimentID,
+		Metric:       metric.Name,
+		Method:       string(opts.Method),
+		Iterations:   opts.Iterations,
+		Confidence:   opts.Confidence,
+		Users:        make(map[string]int),
+	}
+	for group, list := range users {
+		result.Users[group] = len(list)
+	}
+
+	treatments := sortedTreatmentGroups(users)
+	total := opts.Iterations * len(treatments)
+	for i, name := range treatments {
+		var report func(done, _ int)
+		if progress != nil {
+			offset := i * opts.Iterations
+			report = func(done, _ int) { progress(offset+done, total) }
+		}
+
+		res, err := stats.BootstrapDifference(ctx, control, users[name], metric.Compute, opts, report)
+		if err != nil {
+			if ctx.Err() != nil {
+				logger.Info("Бутстрэп эксперимента %d отменен", experimentID)
+				return nil, ctx.Err()
+			}
+			logger.Warn("Бутстрэп для групп %s и %s не выполнен: %v", models.ControlGroup, name, err)
+			continue
+		}
+		result.Comparisons = append(result.Comparisons, models.BootstrapComparison{
+			ControlGroup:   models.ControlGroup,
+			TreatmentGroup: name,
+			ControlValue:   res.Control,
+			TreatmentValue: res.Treatment,
+			Difference:     res.Difference,
+			StandardError:  res.StandardError,
+			CILower:        res.CILower,
+			CIUpper:        res.CIUpper,
+			Iterations:     res.Iterations,
+		})
+	}
+
+	logger.Info("Бутстрэп метрики %s для эксперимента %d завершен", metric.Name, experimentID)
+	return result, nil
+}
@@ -0,0 +1,61 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"testing-platform/db/models"
+	"testing-platform/pkg/logger"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// actorKey ключ контекста с автором изменений
+type actorKey struct{}
+
+// WithActor возвращает контекст, изменения экспериментов в котором записываются в журнал
+// от имени actor (оператора интерфейса или фонового планировщика). Без автора в журнал пишется роль БД
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// setActor передает автора изменений из контекста триггеру журнала на время транзакции
+func setActor(ctx context.Context, tx pgx.Tx) error {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	if actor == "" {
+		return nil
+	}
+	if _, err := tx.Exec(ctx, `SELECT set_config('testing_platform.actor', $1, true)`, actor); err != nil {
+		return fmt.Errorf("не удалось установить автора изменений: %w", err)
+	}
+	return nil
+}
+
+// GetExperimentChanges возвращает журнал изменений эксперимента и его вариантов, новые записи первыми
+func (r *Repository) GetExperimentChanges(ctx context.Context, experimentID int) ([]models.ExperimentChange, error) {
+	sql := `SELECT id, experiment_id, entity, operation, changed_by, changed_at, old_data, new_data
+	         FROM experiment_changes
+	         WHERE experiment_id = $1
+	         ORDER BY changed_at DESC, id DESC`
+
+	rows, err := r.pool.Query(ctx, sql, experimentID)
+	if err != nil {
+		logger.Error("Ошибка при запросе журнала изменений эксперимента: %v", err)
+		return nil, fmt.Errorf("не удалось получить журнал изменений эксперимента: %w", err)
+	}
+	defer rows.Close()
+
+	var changes []models.ExperimentChange
+	for rows.Next() {
+		var c models.ExperimentChange
+		if err := rows.Scan(&c.ID, &c.ExperimentID, &c.Entity, &c.Operation, &c.ChangedBy, &c.ChangedAt,
+			&c.Before, &c.After); err != nil {
+			logger.Error("Ошибка при сканировании записи журнала изменений: %v", err)
+			continue
+		}
+		changes = append(changes, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка обработки журнала изменений: %w", err)
+	}
+	return changes, nil
+}
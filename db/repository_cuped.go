@@ -0,0 +1,174 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing-platform/db/models"
+	"testing-platform/pkg/logger"
+	"testing-platform/pkg/stats"
+)
+
+// cupedObservation CTR пользователя в эксперименте и в предпериоде
+type cupedObservation struct {
+	group      string
+	ctr        float64
+	preCTR     float64
+	hasHistory bool
+}
+
+// getCUPEDObservations возвращает CTR каждого пользователя эксперимента и его CTR в предпериоде —
+// по результатам, полученным до начала текущего эксперимента, во всех других экспериментах,
+// где участвовал тот же users.user_id. Результаты параллельных экспериментов после начала текущего
+// в ковариату не попадают: они могут зависеть от воздействия текущего эксперимента
+func (r *Repository) getCUPEDObservations(ctx context.Context, experimentID int) ([]cupedObservation, error) {
+	sql := `WITH current_users AS (
+	            SELECT u.user_id, u.group_name,
+	                   COUNT(r.id) AS recommendations,
+	                   COUNT(r.id) FILTER (WHERE r.clicked) AS clicks
+	            FROM users u
+	            LEFT JOIN results r ON u.id = r.user_id
+	            WHERE u.experiment_id = $1
+	            GROUP BY u.id, u.user_id, u.group_name
+	         ),
+	         pre_period AS (
+	            SELECT u.user_id,
+	                   COUNT(r.id) AS recommendations,
+	                   COUNT(r.id) FILTER (WHERE r.clicked) AS clicks
+	            FROM users u
+	            JOIN experiments e ON e.id = u.experiment_id
+	            JOIN results r ON u.id = r.user_id
+	            WHERE u.experiment_id <> $1
+	              AND e.start_date < (SELECT start_date FROM experiments WHERE id = $1)
+	              AND r.created_at < (SELECT start_date FROM experiments WHERE id = $1)
+	            GROUP BY u.user_id
+	         )
+	         SELECT c.group_name, c.recommendations, c.clicks,
+	                COALESCE(p.recommendations, 0), COALESCE(p.clicks, 0)
+	         FROM current_users c
+	         LEFT JOIN pre_period p ON p.user_id = c.user_id
+	         WHERE c.recommendations > 0`
+
+	rows, err := r.pool.Query(ctx, sql, experimentID)
+	if err != nil {
+		logger.Error("Ошибка при запросе данных для CUPED: %v", err)
+		return nil, fmt.Errorf("не удалось получить данные для CUPED: %w", err)
+	}
+	defer rows.Close()
+
+	var observations []cupedObservation
+	for rows.Next() {
+		var o cupedObservation
+		var recommendations, clicks, preRecommendations, preClicks int
+		if err := rows.Scan(&o.group, &recommendations, &clicks, &preRecommendations, &preClicks); err != nil {
+			logger.Error("Ошибка при сканировании данных для CUPED: %v", err)
+			continue
+		}
+		o.ctr = float64(clicks) / float64(recommendations)
+		if preRecommendations > 0 {
+			o.preCTR = float64(preClicks) / float64(preRecommendations)
+			o.hasHistory = true
+		}
+		observations = append(observations, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка обработки данных для CUPED: %w", err)
+	}
+	return observations, nil
+}
+
+// GetCUPEDStats сравнивает CTR пользователей тестовых групп с контролем до и после CUPED-корректировки.
+// Ковариата — CTR пользователя в предыдущих экспериментах; пользователям без истории
+// подставляется средний CTR предпериода, что не смещает оценку эффекта
+func (r *Repository) GetCUPEDStats(ctx context.Context, experimentID int, confidence float64) (*models.CUPEDStats, error) {
+	logger.Info("CUPED-анализ эксперимента %d", experimentID)
+
+	if confidence <= 0 || confidence >= 1 {
+		return nil, errors.New("уровень доверия должен быть в интервале (0, 1)")
+	}
+
+	observations, err := r.getCUPEDObservations(ctx, experimentID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.CUPEDStats{
+		ExperimentID: experimentID,
+		Confidence:   confidence,
+		Users:        len(observations),
+	}
+
+	var preSum float64
+	for _, o := range observations {
+		if o.hasHistory {
+			result.UsersWithHistory++
+			preSum += o.preCTR
+		}
+	}
+	if result.UsersWithHistory == 0 {
+		return nil, errors.New("у пользователей эксперимента нет данных предпериода в более ранних экспериментах")
+	}
+	result.Coverage = float64(result.UsersWithHistory) / float64(result.Users)
+	preMean := preSum / float64(result.UsersWithHistory)
+
+	metric := make([]float64, len(observations))
+	covariate := make([]float64, len(observations))
+	for i, o := range observations {
+		metric[i] = o.ctr
+		covariate[i] = preMean
+		if o.hasHistory {
+			covariate[i] = o.preCTR
+		}
+	}
+
+	adjustment, adjusted, err := stats.CUPED(metric, covariate)
+	if err != nil {
+		return nil, fmt.Errorf("не удалось выполнить CUPED-корректировку: %w", err)
+	}
+	result.Theta = adjustment.Theta
+	result.Correlation = adjustment.Correlation
+	result.VarianceReduction = adjustment.VarianceReduction
+
+	raw := make(map[string][]float64)
+	cuped := make(map[string][]float64)
+	for i, o := range observations {
+		raw[o.group] = append(raw[o.group], metric[i])
+		cuped[o.group] = append(cuped[o.group], adjusted[i])
+	}
+
+	control := raw[models.ControlGroup]
+	for _, name := range sortedTreatmentGroups(raw) {
+		rawTest, err := stats.WelchTTest(control, raw[name], confidence)
+		if err != nil {
+			logger.Warn("CUPED-сравнение групп %s и %s не выполнено: %v", models.ControlGroup, name, err)
+			continue
+		}
+		adjustedTest, err := stats.WelchTTest(cuped[models.ControlGroup], cuped[name], confidence)
+		if err != nil {
+			logger.Warn("CUPED-сравнение групп %s и %s не выполнено: %v", models.ControlGroup, name, err)
+			continue
+		}
+
+		comparison := models.CUPEDComparison{
+			ControlGroup:   models.ControlGroup,
+			TreatmentGroup: name,
+			ControlMean:    stats.Summarize(control).Mean,
+			TreatmentMean:  stats.Summarize(raw[name]).Mean,
+			Raw:            welchResult(rawTest),
+			Adjusted:       welchResult(adjustedTest),
+		}
+		if comparison.ControlMean > 0 {
+			comparison.RelativeLiftRaw = rawTest.MeanDiff / comparison.ControlMean
+			comparison.RelativeLiftAdjusted = adjustedTest.MeanDiff / comparison.ControlMean
+		}
+		if rawTest.StandardError > 0 {
+			ratio := adjustedTest.StandardError / rawTest.StandardError
+			comparison.VarianceReduction = 1 - ratio*ratio
+		}
+		result.Comparisons = append(result.Comparisons, comparison)
+	}
+
+	logger.Info("CUPED-анализ эксперимента %d выполнен: theta = %.4f, снижение дисперсии %.1f%%",
+		experimentID, result.Theta, result.VarianceReduction*100)
+	return result, nil
+}
@@ -0,0 +1,372 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"testing-platform/db/models"
+	"testing-platform/pkg/logger"
+	"testing-platform/pkg/stats"
+	"time"
+)
+
+// стандартное отклонение смешивающего распределения всегда валидного теста ограничения:
+// не меньше допустимого ухудшения и доли значения метрики в контроле
+const (
+	guardrailMixingShare = 0.1
+	guardrailMinMixingSD = 0.001
+)
+
+const guardrailEvaluationColumns = `id, guardrail_id, experiment_id, metric_name, treatment_group, evaluated_at,
+	max_degradation, control_value, treatment_value, degradation, standard_error, p_value, alpha,
+	breached, experiment_stopped`
+
+// CreateGuardrail добавляет ограничительную метрику к эксперименту
+func (r *Repository) CreateGuardrail(ctx context.Context, g *models.Guardrail) error {
+	if err := g.Validate(); err != nil {
+		return err
+	}
+
+	logger.Info("Выполнение DML: ограничение метрики %d для эксперимента %d (допустимое ухудшение %.4f)",
+		g.MetricID, g.ExperimentID, g.MaxDegradation)
+	sql := `INSERT INTO guardrails (experiment_id, metric_id, max_degradation, alpha, is_enabled)
+	         VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`
+
+	err := r.pool.QueryRow(ctx, sql, g.ExperimentID, g.MetricID, g.MaxDegradation, g.Alpha, g.IsEnabled).
+		Scan(&g.ID, &g.CreatedAt)
+	if err != nil {
+		logger.Error("Ошибка при создании ограничительной метрики: %v", err)
+		return fmt.Errorf("не удалось создать ограничительную метрику: %w", err)
+	}
+	return nil
+}
+
+// DeleteGuardrail удаляет ограничительную метрику; история ее проверок сохраняется
+func (r *Repository) DeleteGuardrail(ctx context.Context, guardrailID int) error {
+	logger.Info("Выполнение DML: удаление ограничительной метрики %d", guardrailID)
+
+	tag, err := r.pool.Exec(ctx, `DELETE FROM guardrails WHERE id = $1`, guardrailID)
+	if err != nil {
+		logger.Error("Ошибка при удалении ограничительной метрики: %v", err)
+		return fmt.Errorf("не удалось удалить ограничительную метрику: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("ограничительная метрика с ID %d не найдена", guardrailID)
+	}
+	return nil
+}
+
+// guardrailDefinition ограничительная метрика вместе с определением метрики реестра
+type guardrailDefinition struct {
+	guardrail models.Guardrail
+	metric    models.Metric
+}
+
+// getGuardrailDefinitions возвращает ограничительные метрики эксперимента (experimentID 0 — всех экспериментов)
+func (r *Repository) getGuardrailDefinitions(ctx context.Context, experimentID int, enabledOnly bool) ([]guardrailDefinition, error) {
+	sql := `SELECT g.id, g.experiment_id, g.metric_id, g.max_degradation, g.alpha, g.is_enabled, g.created_at,
+	                m.id, m.name, m.title, m.metric_type, m.numerator_sql, m.denominator_sql, m.higher_is_better, m.created_at
+	         FROM guardrails g
+	         JOIN metrics m ON m.id = g.metric_id
+	         WHERE ($1 = 0 OR g.experiment_id = $1) AND (NOT $2 OR g.is_enabled)
+	         ORDER BY g.experiment_id, g.id`
+
+	rows, err := r.pool.Query(ctx, sql, experimentID, enabledOnly)
+	if err != nil {
+		logger.Error("Ошибка при запросе ограничительных метрик: %v", err)
+		return nil, fmt.Errorf("не удалось получить ограничительные метрики: %w", err)
+	}
+	defer rows.Close()
+
+	var definitions []guardrailDefinition
+	for rows.Next() {
+		var d guardrailDefinition
+		g, m := &d.guardrail, &d.metric
+		if err := rows.Scan(&g.ID, &g.ExperimentID, &g.MetricID, &g.MaxDegradation, &g.Alpha, &g.IsEnabled, &g.CreatedAt,
+			&m.ID, &m.Name, &m.Title, &m.Type, &m.NumeratorSQL, &m.DenominatorSQL, &m.HigherIsBetter, &m.CreatedAt); err != nil {
+			logger.Error("Ошибка при сканировании ограничительной метрики: %v", err)
+			continue
+		}
+		g.MetricName = m.Name
+		definitions = append(definitions, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка обработки ограничительных метрик: %w", err)
+	}
+	return definitions, nil
+}
+
+// GetGuardrails возвращает ограничительные метрики эксперимента
+func (r *Repository) GetGuardrails(ctx context.Context, experimentID int) ([]models.Guardrail, error) {
+	definitions, err := r.getGuardrailDefinitions(ctx, experimentID, false)
+	if err != nil {
+		return nil, err
+	}
+	guardrails := make([]models.Guardrail, 0, len(definitions))
+	for _, d := range definitions {
+		guardrails = append(guardrails, d.guardrail)
+	}
+	return guardrails, nil
+}
+
+// GetGuardrailEvaluations возвращает историю проверок ограничительных метрик эксперимента, новые первыми
+func (r *Repository) GetGuardrailEvaluations(ctx context.Context, experimentID int) ([]models.GuardrailEvaluation, error) {
+	sql := `SELECT ` + guardrailEvaluationColumns + `
+	         FROM guardrail_evaluations
+	         WHERE experiment_id = $1
+	         ORDER BY evaluated_at DESC, id DESC`
+
+	rows, err := r.pool.Query(ctx, sql, experimentID)
+	if err != nil {
+		logger.Error("Ошибка при запросе истории ограничительных метрик: %v", err)
+		return nil, fmt.Errorf("не удалось получить историю ограничительных метрик: %w", err)
+	}
+	defer rows.Close()
+
+	var evaluations []models.GuardrailEvaluation
+	for rows.Next() {
+		var e models.GuardrailEvaluation
+		if err := rows.Scan(&e.ID, &e.GuardrailID, &e.ExperimentID, &e.MetricName, &e.TreatmentGroup, &e.EvaluatedAt,
+			&e.MaxDegradation, &e.ControlValue, &e.TreatmentValue, &e.Degradation, &e.StandardError, &e.PValue, &e.Alpha,
+			&e.Breached, &e.ExperimentStopped); err != nil {
+			logger.Error("Ошибка при сканировании проверки ограничительной метрики: %v", err)
+			continue
+		}
+		evaluations = append(evaluations, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка обработки истории ограничительных метрик: %w", err)
+	}
+	return evaluations, nil
+}
+
+// evaluateGuardrail проверяет ограничительную метрику для каждой тестовой группы.
+// Нарушение фиксируется, если ухудшение метрики значимо (односторонний тест) больше допустимого.
+// Монитор проверяет метрику каждые несколько минут, поэтому p-значение всегда валидное
+// (stats.MarginExceedanceSequentialPValue) и накапливается как минимум по проверкам с тем же
+// допустимым ухудшением: вероятность ложной остановки не превышает alpha при любом числе проверок
+func (r *Repository) evaluateGuardrail(ctx context.Context, d guardrailDefinition) ([]models.GuardrailEvaluation, error) {
+	g := d.guardrail
+	result, err := r.evaluateMetric(ctx, d.metric, g.ExperimentID, 1-g.Alpha)
+	if err != nil {
+		return nil, err
+	}
+	prevPValues, err := r.previousGuardrailPValues(ctx, g)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]float64, len(result.Groups))
+	for _, v := range result.Groups {
+		values[v.Group] = v.Value
+	}
+
+	var evaluations []models.GuardrailEvaluation
+	for _, c := range result.Comparisons {
+		degradation := c.AbsoluteLift
+		if d.metric.HigherIsBetter {
+			degradation = -degradation
+		}
+		prevPValue, ok := prevPValues[c.TreatmentGroup]
+		if !ok {
+			prevPValue = 1
+		}
+		mixingSD := max(g.MaxDegradation, guardrailMixingShare*math.Abs(values[c.ControlGroup]), guardrailMinMixingSD)
+
+		guardrailID := g.ID
+		e := models.GuardrailEvaluation{
+			GuardrailID:    &guardrailID,
+			ExperimentID:   g.ExperimentID,
+			MetricName:     d.metric.Name,
+			TreatmentGroup: c.TreatmentGroup,
+			MaxDegradation: g.MaxDegradation,
+			ControlValue:   values[c.ControlGroup],
+			TreatmentValue: values[c.TreatmentGroup],
+			Degradation:    degradation,
+			StandardError:  c.StandardError,
+			PValue: stats.MarginExceedanceSequentialPValue(degradation, g.MaxDegradation, c.StandardError,
+				mixingSD, prevPValue),
+			Alpha: g.Alpha,
+		}
+		e.Breached = e.PValue < g.Alpha
+		evaluations = append(evaluations, e)
+	}
+	return evaluations, nil
+}
+
+// previousGuardrailPValues возвращает всегда валидные p-значения последней проверки ограничения
+// по тестовым группам; проверки с другим допустимым ухудшением не учитываются
+func (r *Repository) previousGuardrailPValues(ctx context.Context, g models.Guardrail) (map[string]float64, error) {
+	sql := `SELECT DISTINCT ON (treatment_group) treatment_group, p_value
+	         FROM guardrail_evaluations
+	         WHERE guardrail_id = $1 AND always_valid AND max_degradation = $2
+	         ORDER BY treatment_group, evaluated_at DESC, id DESC`
+
+	rows, err := r.pool.Query(ctx, sql, g.ID, g.MaxDegradation)
+	if err != nil {
+		logger.Error("Ошибка при запросе предыдущих проверок ограничения %d: %v", g.ID, err)
+		return nil, fmt.Errorf("не удалось получить предыдущие проверки ограничения: %w", err)
+	}
+	defer rows.Close()
+
+	pValues := make(map[string]float64)
+	for rows.Next() {
+		var group string
+		var pValue float64
+		if err := rows.Scan(&group, &pValue); err != nil {
+			logger.Error("Ошибка при сканировании проверки ограничения: %v", err)
+			continue
+		}
+		pValues[group] = pValue
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка обработки предыдущих проверок ограничения: %w", err)
+	}
+	return pValues, nil
+}
+
+// EvaluateGuardrails проверяет включенные ограничительные метрики эксперимента и сохраняет
+// результаты в историю. При значимом нарушении активный эксперимент автоматически выключается
+func (r *Repository) EvaluateGuardrails(ctx context.Context, experimentID int) ([]models.GuardrailEvaluation, error) {
+	logger.Info("Проверка ограничительных метрик эксперимента %d", experimentID)
+
+	definitions, err := r.getGuardrailDefinitions(ctx, experimentID, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(definitions) == 0 {
+		return nil, nil
+	}
+
+	exp, err := r.GetExperiment(ctx, experimentID)
+	if err != nil {
+		return nil, err
+	}
+
+	var evaluations []models.GuardrailEvaluation
+	var breaches []string
+	for _, d := range definitions {
+		list, err := r.evaluateGuardrail(ctx, d)
+		if err != nil {
+			logger.Warn("Ограничительная метрика '%s' эксперимента %d не проверена: %v", d.metric.Name, experimentID, err)
+			continue
+		}
+		for _, e := range list {
+			if e.Breached {
+				breaches = append(breaches, fmt.Sprintf("'%s' в группе %s: ухудшение %.4f при допустимом %.4f (p = %.4f)",
+					e.MetricName, e.TreatmentGroup, e.Degradation, e.MaxDegradation, e.PValue))
+			}
+		}
+		evaluations = append(evaluations, list...)
+	}
+
+	for _, breach := range breaches {
+		logger.Warn("Нарушение ограничительной метрики эксперимента %d: %s", experimentID, breach)
+	}
+	if len(breaches) > 0 && exp.IsRunning() {
+		if err := r.TransitionExperiment(ctx, experimentID, models.StatusStopped,
+			"нарушение ограничительных метрик: "+strings.Join(breaches, "; ")); err != nil {
+			logger.Error("Не удалось остановить эксперимент %d после нарушения ограничений: %v", experimentID, err)
+		} else {
+			logger.Warn("Эксперимент %d автоматически остановлен из-за нарушения ограничительных метрик", experimentID)
+			for i := range evaluations {
+				evaluations[i].ExperimentStopped = evaluations[i].Breached
+			}
+		}
+	}
+
+	if err := r.saveGuardrailEvaluations(ctx, evaluations); err != nil {
+		return evaluations, err
+	}
+	return evaluations, nil
+}
+
+// saveGuardrailEvaluations сохраняет результаты проверок в историю
+func (r *Repository) saveGuardrailEvaluations(ctx context.Context, evaluations []models.GuardrailEvaluation) error {
+	if len(evaluations) == 0 {
+		return nil
+	}
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	sql := `INSERT INTO guardrail_evaluations (guardrail_id, experiment_id, metric_name, treatment_group,
+	            max_degradation, control_value, treatment_value, degradation, standard_error, p_value, alpha,
+	            breached, experiment_stopped, always_valid)
+	         VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, true)
+	         RETURNING id, evaluated_at`
+
+	for i := range evaluations {
+		e := &evaluations[i]
+		err := tx.QueryRow(ctx, sql, e.GuardrailID, e.ExperimentID, e.MetricName, e.TreatmentGroup,
+			e.MaxDegradation, e.ControlValue, e.TreatmentValue, e.Degradation, e.StandardError, e.PValue, e.Alpha,
+			e.Breached, e.ExperimentStopped).Scan(&e.ID, &e.EvaluatedAt)
+		if err != nil {
+			logger.Error("Ошибка при сохранении проверки ограничительной метрики: %v", err)
+			return fmt.Errorf("не удалось сохранить проверку ограничительной метрики: %w", err)
+		}
+	}
+	return tx.Commit(ctx)
+}
+
+// CheckAllGuardrails проверяет ограничительные метрики всех активных экспериментов
+func (r *Repository) CheckAllGuardrails(ctx context.Context) error {
+	sql := `SELECT DISTINCT g.experiment_id
+	         FROM guardrails g
+	         JOIN experiments e ON e.id = g.experiment_id
+	         WHERE g.is_enabled AND e.is_active
+	         ORDER BY g.experiment_id`
+
+	rows, err := r.pool.Query(ctx, sql)
+	if err != nil {
+		logger.Error("Ошибка при запросе экспериментов с ограничительными метриками: %v", err)
+		return fmt.Errorf("не удалось получить эксперименты с ограничительными метриками: %w", err)
+	}
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			logger.Error("Ошибка при сканировании ID эксперимента: %v", err)
+			continue
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("ошибка обработки экспериментов с ограничительными метриками: %w", err)
+	}
+
+	for _, id := range ids {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if _, err := r.EvaluateGuardrails(ctx, id); err != nil {
+			logger.Error("Ошибка проверки ограничительных метрик эксперимента %d: %v", id, err)
+		}
+	}
+	return nil
+}
+
+// RunGuardrailMonitor периодически проверяет ограничительные метрики активных экспериментов
+// до отмены ctx. Предназначен для запуска в отдельной горутине
+func (r *Repository) RunGuardrailMonitor(ctx context.Context, interval time.Duration) {
+	logger.Info("Запуск мониторинга ограничительных метрик с интервалом %s", interval)
+	ctx = WithActor(ctx, "мониторинг ограничительных метрик")
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := r.CheckAllGuardrails(ctx); err != nil && ctx.Err() == nil {
+			logger.Error("Ошибка мониторинга ограничительных метрик: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			logger.Info("Мониторинг ограничительных метрик остановлен")
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"testing-platform/db/models"
+	"testing-platform/pkg/logger"
+	"testing-platform/pkg/stats"
+)
+
+// число рекомендаций в списках лидеров и аутсайдеров
+const itemLeadersLimit = 10
+
+// GetItemPerformance возвращает показы, клики, CTR и среднюю оценку каждой рекомендации эксперимента
+// по группам. Рекомендации, показанные в контроле и тестовой группе treatment не менее minImpressions раз,
+// сравниваются z-тестом CTR с уровнем доверия confidence; p-значения этих сравнений исправляются
+// поправкой Бенджамини–Хохберга, и в списки лучших и худших для тестового алгоритма попадают только
+// рекомендации, значимые после поправки. Пустой treatment означает первую по алфавиту тестовую группу
+func (r *Repository) GetItemPerformance(ctx context.Context, experimentID int, treatment string, minImpressions int, confidence float64) (*models.ItemReport, error) {
+	logger.Info("Запрос эффективности рекомендаций эксперимента %d", experimentID)
+	if minImpressions < 1 {
+		return nil, errors.New("минимальное число показов должно быть положительным")
+	}
+	if confidence <= 0 || confidence >= 1 {
+		return nil, errors.New("уровень доверия должен быть в интервале (0, 1)")
+	}
+
+	sql := `SELECT r.recommendation_id, u.group_name,
+	                COUNT(*),
+	                COUNT(*) FILTER (WHERE r.clicked),
+	                COUNT(*) FILTER (WHERE r.rating > 0),
+	                COALESCE(AVG(r.rating) FILTER (WHERE r.rating > 0), 0)
+	         FROM results r
+	         JOIN users u ON u.id = r.user_id
+	         WHERE u.experiment_id = $1
+	         GROUP BY 1, 2`
+
+	rows, err := r.pool.Query(ctx, sql, experimentID)
+	if err != nil {
+		logger.Error("Ошибка при запросе эффективности рекомендаций: %v", err)
+		return nil, fmt.Errorf("не удалось получить эффективность рекомендаций: %w", err)
+	}
+	defer rows.Close()
+
+	items := make(map[string]*models.ItemPerformance)
+	groups := make(map[string]bool)
+	for rows.Next() {
+		var id, group string
+		var g models.ItemGroupStats
+		if err := rows.Scan(&id, &group, &g.Impressions, &g.Clicks, &g.Ratings, &g.AvgRating); err != nil {
+			logger.Error("Ошибка при сканировании эффективности рекомендации: %v", err)
+			continue
+		}
+		if g.Impressions > 0 {
+			g.CTR = float64(g.Clicks) / float64(g.Impressions)
+		}
+
+		item, ok := items[id]
+		if !ok {
+			item = &models.ItemPerformance{RecommendationID: id, Groups: make(map[string]models.ItemGroupStats)}
+			items[id] = item
+		}
+		item.Groups[group] = g
+		item.Impressions += g.Impressions
+		item.Clicks += g.Clicks
+		groups[group] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка обработки эффективности рекомендаций: %w", err)
+	}
+
+	report := &models.ItemReport{
+		ExperimentID:   experimentID,
+		ControlGroup:   models.ControlGroup,
+		MinImpressions: minImpressions,
+		Confidence:     confidence,
+	}
+	if treatment == models.ControlGroup {
+		return nil, errors.New("тестовая группа не может совпадать с контрольной")
+	}
+	report.TreatmentGroup = treatment
+	if treatments := sortedTreatmentGroups(groups); treatment == "" && len(treatments) > 0 {
+		report.TreatmentGroup = treatments[0]
+	}
+
+	for _, item := range items {
+		if item.Impressions > 0 {
+			item.CTR = float64(item.Clicks) / float64(item.Impressions)
+		}
+		control, okC := item.Groups[report.ControlGroup]
+		treatment, okT := item.Groups[report.TreatmentGroup]
+		if okC && okT && control.Impressions >= minImpressions && treatment.Impressions >= minImpressions {
+			test, err := stats.TwoProportionZTest(control.Clicks, control.Impressions,
+				treatment.Clicks, treatment.Impressions, confidence)
+			if err == nil {
+				item.AbsoluteLift = test.AbsoluteLift
+				item.PValue = test.PValue
+				item.Comparable = true
+			}
+		}
+		report.Items = append(report.Items, *item)
+	}
+	sort.Slice(report.Items, func(i, j int) bool {
+		if report.Items[i].Impressions != report.Items[j].Impressions {
+			return report.Items[i].Impressions > report.Items[j].Impressions
+		}
+		return report.Items[i].RecommendationID < report.Items[j].RecommendationID
+	})
+
+	comparable := adjustItemPValues(report.Items)
+	report.Winners, report.Losers = selectItemLeaders(comparable, 1-confidence)
+
+	logger.Info("Эксперимент %d: %d рекомендаций, сравнимых %d, значимых после поправки: лучше %d, хуже %d",
+		experimentID, len(report.Items), len(comparable), len(report.Winners), len(report.Losers))
+	return report, nil
+}
+
+// adjustItemPValues заполняет AdjustedPValue сравнимых рекомендаций поправкой Бенджамини–Хохберга
+// (семейство — все сравнимые рекомендации отчета) и возвращает их копии
+func adjustItemPValues(items []models.ItemPerformance) []models.ItemPerformance {
+	var indexes []int
+	var pValues []float64
+	for i, item := range items {
+		if item.Comparable {
+			indexes = append(indexes, i)
+			pValues = append(pValues, item.PValue)
+		}
+	}
+	comparable := make([]models.ItemPerformance, 0, len(indexes))
+	for k, q := range stats.BenjaminiHochberg(pValues) {
+		items[indexes[k]].AdjustedPValue = q
+		comparable = append(comparable, items[indexes[k]])
+	}
+	return comparable
+}
+
+// selectItemLeaders выбирает до itemLeadersLimit рекомендаций, у которых тестовая группа значимо
+// (исправленное p-значение меньше alpha) лучше и хуже контроля, по убыванию модуля разности CTR.
+// Рекомендации с малым числом показов дают большие случайные разности, но не проходят поправку
+func selectItemLeaders(comparable []models.ItemPerformance, alpha float64) (winners, losers []models.ItemPerformance) {
+	var significant []models.ItemPerformance
+	for _, item := range comparable {
+		if item.Comparable && item.AdjustedPValue < alpha {
+			significant = append(significant, item)
+		}
+	}
+	sort.SliceStable(significant, func(i, j int) bool { return significant[i].AbsoluteLift > significant[j].AbsoluteLift })
+	for _, item := range significant {
+		if item.AbsoluteLift <= 0 || len(winners) == itemLeadersLimit {
+			break
+		}
+		winners = append(winners, item)
+	}
+	for i := len(significant) - 1; i >= 0; i-- {
+		if significant[i].AbsoluteLift >= 0 || len(losers) == itemLeadersLimit {
+			break
+		}
+		losers = append(losers, significant[i])
+	}
+	return winners, losers
+}
@@ -0,0 +1,43 @@
+package db
+
+import (
+	"math"
+	"testing"
+	"testing-platform/db/models"
+)
+
+func TestItemLeaders(t *testing.T) {
+	items := []models.ItemPerformance{
+		{RecommendationID: "a", AbsoluteLift: 0.05, PValue: 0.001, Comparable: true},
+		// большая разность на малом числе показов: значима без поправки, но не после нее
+		{RecommendationID: "b", AbsoluteLift: 0.30, PValue: 0.04, Comparable: true},
+		{RecommendationID: "c", AbsoluteLift: -0.02, PValue: 0.002, Comparable: true},
+		{RecommendationID: "d", AbsoluteLift: 0.01, PValue: 0.5, Comparable: true},
+		{RecommendationID: "e"},
+	}
+
+	comparable := adjustItemPValues(items)
+	if len(comparable) != 4 {
+		t.Fatalf("сравнимых рекомендаций %d, ожидается 4", len(comparable))
+	}
+	// эталон: p.adjust(c(0.001, 0.04, 0.002, 0.5), "BH") в R
+	for i, want := range []float64{0.004, 0.04 * 4 / 3, 0.004, 0.5, 0} {
+		if math.Abs(items[i].AdjustedPValue-want) > 1e-12 {
+			t.Errorf("%s: AdjustedPValue = %g, ожидается %g", items[i].RecommendationID, items[i].AdjustedPValue, want)
+		}
+	}
+
+	winners, losers := selectItemLeaders(comparable, 0.05)
+	if len(winners) != 1 || winners[0].RecommendationID != "a" {
+		t.Errorf("лучшие %v, ожидается только a", winners)
+	}
+	if len(losers) != 1 || losers[0].RecommendationID != "c" {
+		t.Errorf("худшие %v, ожидается только c", losers)
+	}
+
+	// без поправки в лучшие попала бы и b — первой по разности
+	winners, _ = selectItemLeaders(comparable, 0.1)
+	if len(winners) != 2 || winners[0].RecommendationID != "b" || winners[1].RecommendationID != "a" {
+		t.Errorf("лучшие при alpha = 0.1: %v, ожидается b, a", winners)
+	}
+}
@@ -0,0 +1,190 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing-platform/db/models"
+	"testing-platform/pkg/logger"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// queryRower общий интерфейс пула и транзакции для запросов одной строки
+type queryRower interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// CreateLayer создает слой экспериментов
+func (r *Repository) CreateLayer(ctx context.Context, l *models.Layer) error {
+	if err := l.Validate(); err != nil {
+		return err
+	}
+	l.Name = strings.TrimSpace(l.Name)
+
+	logger.Info("Выполнение DML: создание слоя '%s'", l.Name)
+	sql := `INSERT INTO layers (name, description) VALUES ($1, $2) RETURNING id, created_at`
+	if err := r.pool.QueryRow(ctx, sql, l.Name, l.Description).Scan(&l.ID, &l.CreatedAt); err != nil {
+		logger.Error("Ошибка при создании слоя: %v", err)
+		return fmt.Errorf("не удалось создать слой: %w", err)
+	}
+	return nil
+}
+
+// DeleteLayer удаляет слой, в котором нет экспериментов
+func (r *Repository) DeleteLayer(ctx context.Context, layerID int) error {
+	logger.Info("Выполнение DML: удаление слоя %d", layerID)
+
+	var experiments int
+	if err := r.pool.QueryRow(ctx, `SELECT COUNT(*) FROM experiments WHERE layer_id = $1`, layerID).Scan(&experiments); err != nil {
+		return fmt.Errorf("не удалось проверить эксперименты слоя: %w", err)
+	}
+	if experiments > 0 {
+		return fmt.Errorf("в слое %d есть эксперименты (%d), удалить его нельзя", layerID, experiments)
+	}
+
+	tag, err := r.pool.Exec(ctx, `DELETE FROM layers WHERE id = $1`, layerID)
+	if err != nil {
+		logger.Error("Ошибка при удалении слоя: %v", err)
+		return fmt.Errorf("не удалось удалить слой: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("слой с ID %d не найден", layerID)
+	}
+	return nil
+}
+
+// GetLayers возвращает слои экспериментов по названию
+func (r *Repository) GetLayers(ctx context.Context) ([]models.Layer, error) {
+	rows, err := r.pool.Query(ctx, `SELECT id, name, description, created_at FROM layers ORDER BY name`)
+	if err != nil {
+		logger.Error("Ошибка при запросе слоев: %v", err)
+		return nil, fmt.Errorf("не удалось получить слои: %w", err)
+	}
+	defer rows.Close()
+
+	var layers []models.Layer
+	for rows.Next() {
+		var l models.Layer
+		if err := rows.Scan(&l.ID, &l.Name, &l.Description, &l.CreatedAt); err != nil {
+			logger.Error("Ошибка при сканировании слоя: %v", err)
+			continue
+		}
+		layers = append(layers, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка обработки слоев: %w", err)
+	}
+	return layers, nil
+}
+
+// GetLayerUsage возвращает диапазоны трафика, занятые экспериментами каждого слоя, и свободную долю;
+// архивные эксперименты освобождают свои диапазоны
+func (r *Repository) GetLayerUsage(ctx context.Context) ([]models.LayerUsage, error) {
+	layers, err := r.GetLayers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.pool.Query(ctx, `SELECT layer_id, id, name, status, layer_range_start::float8, layer_range_end::float8
+	                                 FROM experiments WHERE layer_id IS NOT NULL AND status <> 'archived'
+	                                 ORDER BY layer_id, layer_range_start`)
+	if err != nil {
+		logger.Error("Ошибка при запросе диапазонов слоев: %v", err)
+		return nil, fmt.Errorf("не удалось получить диапазоны слоев: %w", err)
+	}
+	defer rows.Close()
+
+	ranges := make(map[int][]models.LayerRange)
+	for rows.Next() {
+		var layerID int
+		var lr models.LayerRange
+		if err := rows.Scan(&layerID, &lr.ExperimentID, &lr.ExperimentName, &lr.Status, &lr.Start, &lr.End); err != nil {
+			logger.Error("Ошибка при сканировании диапазона слоя: %v", err)
+			continue
+		}
+		ranges[layerID] = append(ranges[layerID], lr)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка обработки диапазонов слоев: %w", err)
+	}
+
+	usage := make([]models.LayerUsage, 0, len(layers))
+	for _, l := range layers {
+		u := models.LayerUsage{Layer: l, Ranges: ranges[l.ID], FreePercent: 100}
+		for _, lr := range u.Ranges {
+			u.FreePercent -= lr.End - lr.Start
+		}
+		usage = append(usage, u)
+	}
+	return usage, nil
+}
+
+// allocateLayerRange находит первый свободный диапазон шириной width процентов в слое
+// (диапазоны архивных экспериментов свободны); диапазон эксперимента excludeExperimentID не учитывается,
+// чтобы его можно было выделить заново при изменении процента пользователей.
+// Строка слоя блокируется до конца транзакции, поэтому параллельные создания экспериментов
+// в одном слое не получают пересекающиеся диапазоны
+func allocateLayerRange(ctx context.Context, tx pgx.Tx, layerID int, width float64, excludeExperimentID int) (float64, error) {
+	var id int
+	err := tx.QueryRow(ctx, `SELECT id FROM layers WHERE id = $1 FOR UPDATE`, layerID).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, fmt.Errorf("слой с ID %d не найден", layerID)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("не удалось заблокировать слой %d: %w", layerID, err)
+	}
+
+	rows, err := tx.Query(ctx, `SELECT status, layer_range_start::float8, layer_range_end::float8
+	                            FROM experiments WHERE layer_id = $1 AND id <> $2`,
+		layerID, excludeExperimentID)
+	if err != nil {
+		return 0, fmt.Errorf("не удалось получить диапазоны слоя %d: %w", layerID, err)
+	}
+	var ranges []models.LayerRange
+	for rows.Next() {
+		var lr models.LayerRange
+		if err := rows.Scan(&lr.Status, &lr.Start, &lr.End); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("ошибка чтения диапазона слоя %d: %w", layerID, err)
+		}
+		ranges = append(ranges, lr)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("ошибка обработки диапазонов слоя %d: %w", layerID, err)
+	}
+
+	start, ok := models.FindFreeRange(ranges, width)
+	if !ok {
+		var used float64
+		for _, lr := range ranges {
+			if lr.Status != models.StatusArchived {
+				used += lr.End - lr.Start
+			}
+		}
+		return 0, fmt.Errorf("в слое %d нет свободного диапазона на %.2f%% трафика (свободно %.2f%%, возможно, не одним куском)",
+			layerID, width, 100-used)
+	}
+	logger.Info("Слой %d: выделен диапазон [%.2f; %.2f)", layerID, start, start+width)
+	return start, nil
+}
+
+// layerConflict возвращает ID другого эксперимента того же слоя, в котором уже участвует пользователь,
+// или 0, если такого нет (или эксперимент не входит в слой); архивные эксперименты не учитываются
+func layerConflict(ctx context.Context, q queryRower, experimentID int, userID string) (int, error) {
+	sql := `SELECT COALESCE(MIN(e.id), 0)
+	         FROM users u
+	         JOIN experiments e ON e.id = u.experiment_id
+	         JOIN experiments target ON target.id = $1
+	         WHERE u.user_id = $2 AND e.id <> target.id AND e.layer_id = target.layer_id
+	           AND e.status <> 'archived'`
+
+	var other int
+	if err := q.QueryRow(ctx, sql, experimentID, userID).Scan(&other); err != nil {
+		logger.Error("Ошибка при проверке слоя пользователя %s: %v", userID, err)
+		return 0, fmt.Errorf("не удалось проверить участие пользователя в экспериментах слоя: %w", err)
+	}
+	return other, nil
+}
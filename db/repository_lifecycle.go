@@ -0,0 +1,115 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing-platform/db/models"
+	"testing-platform/pkg/logger"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// TransitionExperiment переводит эксперимент в статус status с указанием причины.
+// Переход проверяется по жизненному циклу (models.ValidateTransition), is_active синхронизируется
+// со статусом, смена записывается в experiment_status_history. При первом запуске черновика
+// или запланированного эксперимента start_date становится временем запуска
+func (r *Repository) TransitionExperiment(ctx context.Context, experimentID int, status, reason string) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	if err := setActor(ctx, tx); err != nil {
+		return err
+	}
+
+	// строка эксперимента блокируется, чтобы параллельные переходы не обошли проверку
+	var current string
+	var plannedStart, plannedEnd *time.Time
+	err = tx.QueryRow(ctx, `SELECT status, planned_start, planned_end FROM experiments WHERE id = $1 FOR UPDATE`,
+		experimentID).Scan(&current, &plannedStart, &plannedEnd)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("эксперимент с ID %d не найден", experimentID)
+	}
+	if err != nil {
+		logger.Error("Ошибка при получении статуса эксперимента %d: %v", experimentID, err)
+		return fmt.Errorf("не удалось получить статус эксперимента: %w", err)
+	}
+	if err := models.ValidateTransition(current, status, reason); err != nil {
+		return err
+	}
+	if err := models.ValidateSchedule(status, plannedStart, plannedEnd); err != nil {
+		return err
+	}
+	reason = strings.TrimSpace(reason)
+
+	logger.Info("Смена статуса эксперимента %d: %s -> %s (%s)", experimentID, current, status, reason)
+
+	sql := `UPDATE experiments
+	         SET status = $1,
+	             is_active = ($1 = 'running'),
+	             status_changed_at = CURRENT_TIMESTAMP,
+	             start_date = CASE WHEN $1 = 'running' AND $2 IN ('draft', 'scheduled')
+	                               THEN CURRENT_TIMESTAMP ELSE start_date END
+	         WHERE id = $3`
+	if _, err := tx.Exec(ctx, sql, status, current, experimentID); err != nil {
+		logger.Error("Ошибка при обновлении статуса эксперимента: %v", err)
+		return fmt.Errorf("не удалось обновить статус эксперимента: %w", err)
+	}
+	if err := insertStatusChange(ctx, tx, experimentID, current, status, reason); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	logger.Info("Статус эксперимента %d успешно обновлен", experimentID)
+	return nil
+}
+
+// insertStatusChange записывает смену статуса эксперимента в историю; from пуст для начального статуса
+func insertStatusChange(ctx context.Context, tx pgx.Tx, experimentID int, from, to, reason string) error {
+	var fromStatus *string
+	if from != "" {
+		fromStatus = &from
+	}
+	_, err := tx.Exec(ctx, `INSERT INTO experiment_status_history (experiment_id, from_status, to_status, reason)
+	                        VALUES ($1, $2, $3, $4)`, experimentID, fromStatus, to, reason)
+	if err != nil {
+		logger.Error("Ошибка при записи истории статусов эксперимента %d: %v", experimentID, err)
+		return fmt.Errorf("не удалось записать смену статуса: %w", err)
+	}
+	return nil
+}
+
+// GetStatusHistory возвращает смены статуса эксперимента в хронологическом порядке
+func (r *Repository) GetStatusHistory(ctx context.Context, experimentID int) ([]models.StatusChange, error) {
+	sql := `SELECT id, experiment_id, COALESCE(from_status, ''), to_status, reason, changed_at
+	         FROM experiment_status_history
+	         WHERE experiment_id = $1
+	         ORDER BY changed_at, id`
+
+	rows, err := r.pool.Query(ctx, sql, experimentID)
+	if err != nil {
+		logger.Error("Ошибка при запросе истории статусов: %v", err)
+		return nil, fmt.Errorf("не удалось получить историю статусов: %w", err)
+	}
+	defer rows.Close()
+
+	var history []models.StatusChange
+	for rows.Next() {
+		var c models.StatusChange
+		if err := rows.Scan(&c.ID, &c.ExperimentID, &c.FromStatus, &c.ToStatus, &c.Reason, &c.ChangedAt); err != nil {
+			logger.Error("Ошибка при сканировании смены статуса: %v", err)
+			continue
+		}
+		history = append(history, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка обработки истории статусов: %w", err)
+	}
+	return history, nil
+}
@@ -0,0 +1,314 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"testing-platform/db/models"
+	"testing-platform/pkg/logger"
+	"testing-platform/pkg/stats"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// метрики вычисляются в транзакции только для чтения: выражения реестра не могут изменить данные
+var metricTxOptions = pgx.TxOptions{AccessMode: pgx.ReadOnly}
+
+const metricColumns = `id, name, title, metric_type, numerator_sql, denominator_sql, higher_is_better, created_at`
+
+// metricUserQuery строит запрос значений числителя и знаменателя метрики для каждого пользователя эксперимента
+func metricUserQuery(m models.Metric) string {
+	denominator := m.DenominatorSQL
+	if denominator == "" {
+		denominator = "1"
+	}
+	return fmt.Sprintf(`SELECT u.group_name, (%s)::double precision, (%s)::double precision
+	         FROM users u
+	         LEFT JOIN results r ON u.id = r.user_id
+	         WHERE u.experiment_id = $1
+	         GROUP BY u.id, u.group_name`, m.NumeratorSQL, denominator)
+}
+
+// GetMetrics возвращает все метрики реестра
+func (r *Repository) GetMetrics(ctx context.Context) ([]models.Metric, error) {
+	sql := `SELECT ` + metricColumns + ` FROM metrics ORDER BY id`
+
+	rows, err := r.pool.Query(ctx, sql)
+	if err != nil {
+		logger.Error("Ошибка при запросе метрик: %v", err)
+		return nil, fmt.Errorf("не удалось получить метрики: %w", err)
+	}
+	defer rows.Close()
+
+	var metrics []models.Metric
+	for rows.Next() {
+		var m models.Metric
+		if err := rows.Scan(&m.ID, &m.Name, &m.Title, &m.Type, &m.NumeratorSQL, &m.DenominatorSQL,
+			&m.HigherIsBetter, &m.CreatedAt); err != nil {
+			logger.Error("Ошибка при сканировании метрики: %v", err)
+			continue
+		}
+		metrics = append(metrics, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка обработки метрик: %w", err)
+	}
+	return metrics, nil
+}
+
+// checkMetricExpressions проверяет, что выражения метрики выполняются без ошибок
+func (r *Repository) checkMetricExpressions(ctx context.Context, m *models.Metric) error {
+	tx, err := r.pool.BeginTx(ctx, metricTxOptions)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, metricUserQuery(*m)+` LIMIT 0`, 0)
+	if err != nil {
+		return fmt.Errorf("некорректное выражение метрики: %w", err)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("некорректное выражение метрики: %w", err)
+	}
+	return nil
+}
+
+// CreateMetric добавляет метрику в реестр
+func (r *Repository) CreateMetric(ctx context.Context, m *models.Metric) error {
+	if err := m.Validate(); err != nil {
+		return err
+	}
+	if err := r.checkMetricExpressions(ctx, m); err != nil {
+		return err
+	}
+
+	logger.Info("Выполнение DML: создание метрики '%s'", m.Name)
+	sql := `INSERT INTO metrics (name, title, metric_type, numerator_sql, denominator_sql, higher_is_better)
+	         VALUES ($1, $2, $3, $4, $5, $6) RETURNING id, created_at`
+
+	err := r.pool.QueryRow(ctx, sql, m.Name, m.Title, m.Type, m.NumeratorSQL, m.DenominatorSQL, m.HigherIsBetter).
+		Scan(&m.ID, &m.CreatedAt)
+	if err != nil {
+		logger.Error("Ошибка при создании метрики: %v", err)
+		return fmt.Errorf("не удалось создать метрику: %w", err)
+	}
+
+	logger.Info("Метрика '%s' успешно создана с ID %d", m.Name, m.ID)
+	return nil
+}
+
+// UpdateMetric изменяет определение метрики реестра
+func (r *Repository) UpdateMetric(ctx context.Context, m *models.Metric) error {
+	if err := m.Validate(); err != nil {
+		return err
+	}
+	if err := r.checkMetricExpressions(ctx, m); err != nil {
+		return err
+	}
+
+	logger.Info("Выполнение DML: изменение метрики %d", m.ID)
+	sql := `UPDATE metrics
+	         SET name = $1, title = $2, metric_type = $3, numerator_sql = $4, denominator_sql = $5, higher_is_better = $6
+	         WHERE id = $7`
+
+	tag, err := r.pool.Exec(ctx, sql, m.Name, m.Title, m.Type, m.NumeratorSQL, m.DenominatorSQL, m.HigherIsBetter, m.ID)
+	if err != nil {
+		logger.Error("Ошибка при изменении метрики: %v", err)
+		return fmt.Errorf("не удалось изменить метрику: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("метрика с ID %d не найдена", m.ID)
+	}
+
+	logger.Info("Метрика %d успешно изменена", m.ID)
+	return nil
+}
+
+// DeleteMetric удаляет метрику из реестра
+func (r *Repository) DeleteMetric(ctx context.Context, metricID int) error {
+	logger.Info("Выполнение DML: удаление метрики %d", metricID)
+
+	tag, err := r.pool.Exec(ctx, `DELETE FROM metrics WHERE id = $1`, metricID)
+	if err != nil {
+		logger.Error("Ошибка при удалении метрики: %v", err)
+		return fmt.Errorf("не удалось удалить метрику: %w", err)
+	}
+	if tag.RowsAffected() == 0 {
+		return fmt.Errorf("метрика с ID %d не найдена", metricID)
+	}
+	return nil
+}
+
+// EvaluateMetrics вычисляет каждую метрику реестра по группам эксперимента и сравнивает
+// тестовые группы с контролем. Ошибка одной метрики не прерывает вычисление остальных
+func (r *Repository) EvaluateMetrics(ctx context.Context, experimentID int, confidence float64) (*models.MetricsReport, error) {
+	logger.Info("Вычисление метрик реестра для эксперимента %d", experimentID)
+	if confidence <= 0 || confidence >= 1 {
+		return nil, errors.New("уровень доверия должен быть в интервале (0, 1)")
+	}
+
+	metrics, err := r.GetMetrics(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.MetricsReport{ExperimentID: experimentID, Confidence: confidence}
+	for _, m := range metrics {
+		result, err := r.evaluateMetric(ctx, m, experimentID, confidence)
+		if err != nil {
+			logger.Warn("Метрика '%s' не вычислена для эксперимента %d: %v", m.Name, experimentID, err)
+			result = models.MetricResult{Metric: m, Error: err.Error()}
+		}
+		report.Metrics = append(report.Metrics, result)
+	}
+	return report, nil
+}
+
+// metricObservations числители и знаменатели метрики по пользователям группы
+type metricObservations struct {
+	numerators   []float64
+	denominators []float64
+}
+
+// evaluateMetric вычисляет одну метрику реестра для эксперимента
+func (r *Repository) evaluateMetric(ctx context.Context, m models.Metric, experimentID int, confidence float64) (models.MetricResult, error) {
+	tx, err := r.pool.BeginTx(ctx, metricTxOptions)
+	if err != nil {
+		return models.MetricResult{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	rows, err := tx.Query(ctx, metricUserQuery(m), experimentID)
+	if err != nil {
+		return models.MetricResult{}, fmt.Errorf("ошибка выполнения выражения метрики: %w", err)
+	}
+	defer rows.Close()
+
+	groups := make(map[string]*metricObservations)
+	for rows.Next() {
+		var group string
+		var numerator, denominator *float64
+		if err := rows.Scan(&group, &numerator, &denominator); err != nil {
+			logger.Error("Ошибка при сканировании значения метрики: %v", err)
+			continue
+		}
+		// пользователи, для которых метрика не определена, не учитываются
+		if numerator == nil || denominator == nil {
+			continue
+		}
+		if m.Type != models.MetricTypeRatio && *denominator <= 0 {
+			continue
+		}
+		obs, ok := groups[group]
+		if !ok {
+			obs = &metricObservations{}
+			groups[group] = obs
+		}
+		obs.numerators = append(obs.numerators, *numerator)
+		obs.denominators = append(obs.denominators, *denominator)
+	}
+	if err := rows.Err(); err != nil {
+		return models.MetricResult{}, fmt.Errorf("ошибка выполнения выражения метрики: %w", err)
+	}
+
+	result := models.MetricResult{Metric: m}
+	for _, name := range append([]string{models.ControlGroup}, sortedTreatmentGroups(groups)...) {
+		obs, ok := groups[name]
+		if !ok {
+			continue
+		}
+		value := models.MetricGroupValue{Group: name, Users: len(obs.numerators)}
+		for i := range obs.numerators {
+			value.Numerator += obs.numerators[i]
+			value.Denominator += obs.denominators[i]
+		}
+		if m.Type == models.MetricTypeMean {
+			value.Value = stats.Summarize(perUserValues(obs)).Mean
+		} else if value.Denominator > 0 {
+			value.Value = value.Numerator / value.Denominator
+		}
+		result.Groups = append(result.Groups, value)
+	}
+
+	control, ok := groups[models.ControlGroup]
+	if !ok {
+		return result, nil
+	}
+	for _, name := range sortedTreatmentGroups(groups) {
+		comparison, err := compareMetric(m, control, groups[name], confidence)
+		if err != nil {
+			logger.Warn("Метрика '%s': не удалось сравнить группы %s и %s: %v", m.Name, models.ControlGroup, name, err)
+			continue
+		}
+		comparison.TreatmentGroup = name
+		result.Comparisons = append(result.Comparisons, comparison)
+	}
+	return result, nil
+}
+
+// perUserValues возвращает значения метрики типа mean для каждого пользователя
+func perUserValues(obs *metricObservations) []float64 {
+	values := make([]float64, len(obs.numerators))
+	for i := range obs.numerators {
+		values[i] = obs.numerators[i] / obs.denominators[i]
+	}
+	return values
+}
+
+// compareMetric сравнивает метрику тестовой группы с контролем тестом, соответствующим типу метрики
+func compareMetric(m models.Metric, control, treatment *metricObservations, confidence float64) (models.MetricComparison, error) {
+	c := models.MetricComparison{ControlGroup: models.ControlGroup}
+	var controlValue float64
+
+	switch m.Type {
+	case models.MetricTypeProportion:
+		successesA, trialsA := sumRounded(control.numerators), sumRounded(control.denominators)
+		successesB, trialsB := sumRounded(treatment.numerators), sumRounded(treatment.denominators)
+		test, err := stats.TwoProportionZTest(successesA, trialsA, successesB, trialsB, confidence)
+		if err != nil {
+			return c, err
+		}
+		controlValue = test.ControlRate
+		c.AbsoluteLift, c.StandardError, c.PValue = test.AbsoluteLift, test.StandardError, test.PValue
+		c.CILower, c.CIUpper = test.CILower, test.CIUpper
+	case models.MetricTypeMean:
+		controlValues := perUserValues(control)
+		test, err := stats.WelchTTest(controlValues, perUserValues(treatment), confidence)
+		if err != nil {
+			return c, err
+		}
+		controlValue = stats.Summarize(controlValues).Mean
+		c.AbsoluteLift, c.StandardError, c.PValue = test.MeanDiff, test.StandardError, test.PValue
+		c.CILower, c.CIUpper = test.CILower, test.CIUpper
+	case models.MetricTypeRatio:
+		test, err := stats.RatioDeltaTest(control.numerators, control.denominators,
+			treatment.numerators, treatment.denominators, confidence)
+		if err != nil {
+			return c, err
+		}
+		controlValue = test.ControlRatio
+		c.AbsoluteLift, c.StandardError, c.PValue = test.Difference, test.StandardError, test.PValue
+		c.CILower, c.CIUpper = test.CILower, test.CIUpper
+	default:
+		return c, fmt.Errorf("неизвестный тип метрики %q", m.Type)
+	}
+
+	if controlValue != 0 {
+		c.RelativeLift = c.AbsoluteLift / controlValue
+	}
+	c.Significant = c.PValue < 1-confidence
+	c.Improvement = c.Significant && (c.AbsoluteLift > 0) == m.HigherIsBetter
+	return c, nil
+}
+
+// sumRounded возвращает сумму значений, округленную до целого
+func sumRounded(values []float64) int {
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return int(math.Round(sum))
+}
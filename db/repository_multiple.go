@@ -0,0 +1,177 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing-platform/db/models"
+	"testing-platform/pkg/logger"
+	"testing-platform/pkg/stats"
+)
+
+// GetMultipleTestingReport собирает все p-значения, которые платформа рассчитывает для эксперимента
+// (z-тесты CTR, тесты оценок и хи-квадрат их распределения, CTR после CUPED, метрики реестра,
+// CTR в сегментах пользователей), и применяет к ним
+// поправки Бонферрони и Бенджамини–Хохберга. z-тесты CTR отдельных рекомендаций образуют
+// собственное семейство report.Items со своими поправками. Источник, который не удалось рассчитать, пропускается.
+// Каждая новая проверка гипотез об эффекте эксперимента должна добавлять сюда свои p-значения:
+// окна статистики показывают исправленные значения рядом с исходными по этому отчету
+func (r *Repository) GetMultipleTestingReport(ctx context.Context, experimentID int, alpha float64) (*models.MultipleTestingReport, error) {
+	logger.Info("Сбор p-значений эксперимента %d для поправки на множественные сравнения", experimentID)
+	if alpha <= 0 || alpha >= 1 {
+		return nil, errors.New("уровень значимости должен быть в интервале (0, 1)")
+	}
+	confidence := 1 - alpha
+
+	var tests []models.PValueEntry
+	add := func(family, test, treatment string, pValue float64) {
+		tests = append(tests, models.PValueEntry{
+			Family:         family,
+			Test:           test,
+			ControlGroup:   models.ControlGroup,
+			TreatmentGroup: treatment,
+			PValue:         pValue,
+		})
+	}
+
+	if ctr, err := r.GetExperimentStatsWithConfidence(ctx, experimentID, confidence); err != nil {
+		logger.Warn("p-значения CTR эксперимента %d не собраны: %v", experimentID, err)
+	} else {
+		for _, c := range ctr.Comparisons {
+			add(models.PValueFamilyCTR, models.PValueTestCTR, c.TreatmentGroup, c.PValue)
+		}
+	}
+
+	if ratings, err := r.GetRatingStats(ctx, experimentID, confidence); err != nil {
+		logger.Warn("p-значения оценок эксперимента %d не собраны: %v", experimentID, err)
+	} else {
+		for _, c := range ratings.Comparisons {
+			if c.Welch != nil {
+				add(models.PValueFamilyRatings, models.PValueTestWelch, c.TreatmentGroup, c.Welch.PValue)
+			}
+			if c.MannWhitney != nil {
+				add(models.PValueFamilyRatings, models.PValueTestMannWhitney, c.TreatmentGroup, c.MannWhitney.PValue)
+			}
+		}
+	}
+
+	// однородность распределения оценок проверяется сразу по всем группам
+	if dist, err := r.GetRatingDistribution(ctx, experimentID); err != nil {
+		logger.Warn("p-значение распределения оценок эксперимента %d не собрано: %v", experimentID, err)
+	} else if dist.ChiSquare != nil {
+		add(models.PValueFamilyRatings, models.PValueTestRatingChiSquare, models.PValueAllGroups, dist.ChiSquare.PValue)
+	}
+
+	if cuped, err := r.GetCUPEDStats(ctx, experimentID, confidence); err != nil {
+		logger.Warn("p-значения CUPED эксперимента %d не собраны: %v", experimentID, err)
+	} else {
+		for _, c := range cuped.Comparisons {
+			if c.Adjusted != nil {
+				add(models.PValueFamilyCUPED, models.PValueTestCUPED, c.TreatmentGroup, c.Adjusted.PValue)
+			}
+		}
+	}
+
+	if metrics, err := r.EvaluateMetrics(ctx, experimentID, confidence); err != nil {
+		logger.Warn("p-значения метрик реестра эксперимента %d не собраны: %v", experimentID, err)
+	} else {
+		for _, m := range metrics.Metrics {
+			for _, c := range m.Comparisons {
+				add(models.PValueFamilyMetrics, m.Metric.Title, c.TreatmentGroup, c.PValue)
+			}
+		}
+	}
+
+	// сегменты по атрибутам пользователей; теги у всех пользователей эксперимента общие
+	// и повторяют общий z-тест, а пользователи без значения атрибута не образуют сегмент
+	for _, dimension := range []string{models.SegmentByDevice, models.SegmentByCountry, models.SegmentByCohort} {
+		segments, err := r.GetSegmentStats(ctx, experimentID, dimension, confidence)
+		if err != nil {
+			logger.Warn("p-значения сегментов %s эксперимента %d не собраны: %v", dimension, experimentID, err)
+			continue
+		}
+		for _, segment := range segments.Segments {
+			if segment.Segment == models.SegmentUnspecified {
+				continue
+			}
+			for _, c := range segment.Comparisons {
+				add(models.PValueFamilySegments, models.SegmentPValueTest(dimension, segment.Segment), c.TreatmentGroup, c.PValue)
+			}
+		}
+	}
+
+	report := adjustPValues(tests, alpha)
+	report.ExperimentID = experimentID
+	report.Items = adjustPValues(r.collectItemPValues(ctx, experimentID, confidence), alpha)
+	report.Items.ExperimentID = experimentID
+	logger.Info("Эксперимент %d: %d проверок, значимых без поправки %d, по Бонферрони %d, по Бенджамини–Хохбергу %d; "+
+		"рекомендаций %d, значимых по Бенджамини–Хохбергу %d",
+		experimentID, len(report.Tests), report.SignificantRaw, report.SignificantBonferroni, report.SignificantBH,
+		len(report.Items.Tests), report.Items.SignificantBH)
+	return report, nil
+}
+
+// collectItemPValues собирает p-значения z-тестов CTR рекомендаций, сравнимых при пороге показов
+// по умолчанию, для каждой тестовой группы эксперимента
+func (r *Repository) collectItemPValues(ctx context.Context, experimentID int, confidence float64) []models.PValueEntry {
+	variants, err := r.GetVariants(ctx, experimentID)
+	if err != nil {
+		logger.Warn("p-значения рекомендаций эксперимента %d не собраны: %v", experimentID, err)
+		return nil
+	}
+	var tests []models.PValueEntry
+	for _, v := range variants {
+		if v.Name == models.ControlGroup {
+			continue
+		}
+		items, err := r.GetItemPerformance(ctx, experimentID, v.Name, models.DefaultItemMinImpressions, confidence)
+		if err != nil {
+			logger.Warn("p-значения рекомендаций группы %s эксперимента %d не собраны: %v", v.Name, experimentID, err)
+			continue
+		}
+		for _, item := range items.Items {
+			if !item.Comparable {
+				continue
+			}
+			tests = append(tests, models.PValueEntry{
+				Family:         models.PValueFamilyItems,
+				Test:           models.ItemPValueTest(item.RecommendationID),
+				ControlGroup:   models.ControlGroup,
+				TreatmentGroup: v.Name,
+				PValue:         item.PValue,
+			})
+		}
+	}
+	return tests
+}
+
+// adjustPValues применяет поправки на множественные сравнения ко всему семейству проверок
+func adjustPValues(tests []models.PValueEntry, alpha float64) *models.MultipleTestingReport {
+	pValues := make([]float64, len(tests))
+	for i, t := range tests {
+		pValues[i] = t.PValue
+	}
+	bonferroni := stats.Bonferroni(pValues)
+	bh := stats.BenjaminiHochberg(pValues)
+
+	report := &models.MultipleTestingReport{Alpha: alpha, Tests: tests}
+	for i := range tests {
+		t := &tests[i]
+		t.BonferroniPValue = bonferroni[i]
+		t.BHAdjustedPValue = bh[i]
+		t.Significant = t.PValue < alpha
+		t.SignificantBonferroni = t.BonferroniPValue < alpha
+		t.SignificantBH = t.BHAdjustedPValue < alpha
+		if t.Significant {
+			report.SignificantRaw++
+		}
+		if t.SignificantBonferroni {
+			report.SignificantBonferroni++
+		}
+		if t.SignificantBH {
+			report.SignificantBH++
+		}
+	}
+	report.FamilyWiseErrorRate = 1 - math.Pow(1-alpha, float64(len(tests)))
+	return report
+}
@@ -0,0 +1,133 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"testing-platform/db/models"
+	"testing-platform/pkg/logger"
+	"testing-platform/pkg/stats"
+)
+
+// assignmentHistory темп назначения пользователей в одном из прошлых экспериментов
+type assignmentHistory struct {
+	userPercent     float64
+	users           int
+	days            float64
+	recommendations int
+}
+
+// PlanExperiment рассчитывает необходимое число пользователей на группу и оценивает длительность
+// эксперимента по историческому темпу назначения пользователей в похожих экспериментах
+// (с теми же алгоритмами или общими тегами, при их отсутствии — по всем экспериментам)
+func (r *Repository) PlanExperiment(ctx context.Context, input models.SampleSizeInput) (*models.SampleSizePlan, error) {
+	logger.Info("Планирование размера выборки: %+v", input)
+
+	if input.Groups == 0 {
+		input.Groups = 2
+	}
+	if input.Groups < 2 {
+		return nil, errors.New("в эксперименте должно быть минимум две группы")
+	}
+
+	perGroup, err := stats.SampleSizeTwoProportions(input.BaselineCTR, input.MinDetectableEffect, input.Alpha, input.Power)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &models.SampleSizePlan{
+		Input:                   input,
+		RecommendationsPerGroup: perGroup,
+		RecommendationsPerUser:  1,
+	}
+
+	history, err := r.getSimilarAssignmentHistory(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	var users, recommendations int
+	var usersPerPercentDay float64
+	for _, h := range history {
+		users += h.users
+		recommendations += h.recommendations
+		usersPerPercentDay += float64(h.users) / h.days / h.userPercent
+	}
+	plan.SimilarExperiments = len(history)
+	if users > 0 && recommendations > 0 {
+		plan.RecommendationsPerUser = float64(recommendations) / float64(users)
+	}
+	if len(history) > 0 {
+		plan.DailyUsersPerPercent = usersPerPercentDay / float64(len(history))
+	}
+
+	plan.UsersPerGroup = int(math.Ceil(float64(perGroup) / plan.RecommendationsPerUser))
+	plan.TotalUsers = plan.UsersPerGroup * input.Groups
+	if input.UserPercent > 0 && plan.DailyUsersPerPercent > 0 {
+		plan.DailyUsers = plan.DailyUsersPerPercent * input.UserPercent
+		plan.EstimatedDays = float64(plan.TotalUsers) / plan.DailyUsers
+	}
+
+	logger.Info("План эксперимента: %d пользователей на группу, похожих экспериментов %d", plan.UsersPerGroup, plan.SimilarExperiments)
+	return plan, nil
+}
+
+// getSimilarAssignmentHistory возвращает темп назначения пользователей в похожих экспериментах
+func (r *Repository) getSimilarAssignmentHistory(ctx context.Context, input models.SampleSizeInput) ([]assignmentHistory, error) {
+	tags := input.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	similar := `(e.algorithm_a::text = $1 AND e.algorithm_b::text = $2)
+	          OR (e.algorithm_a::text = $2 AND e.algorithm_b::text = $1)
+	          OR e.tags && $3::text[]`
+	history, err := r.queryAssignmentHistory(ctx, "WHERE "+similar, input.AlgorithmA, input.AlgorithmB, tags)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) > 0 {
+		return history, nil
+	}
+
+	logger.Info("Похожие эксперименты не найдены, темп оценивается по всем экспериментам")
+	return r.queryAssignmentHistory(ctx, "")
+}
+
+// queryAssignmentHistory выполняет запрос темпа назначения с условием where
+func (r *Repository) queryAssignmentHistory(ctx context.Context, where string, args ...any) ([]assignmentHistory, error) {
+	sql := `SELECT
+	            e.user_percent::float8,
+	            COUNT(DISTINCT u.id),
+	            GREATEST(EXTRACT(EPOCH FROM MAX(u.assigned_at) - MIN(u.assigned_at))::float8 / 86400, 1),
+	            COUNT(r.id)
+	         FROM experiments e
+	         JOIN users u ON u.experiment_id = e.id
+	         LEFT JOIN results r ON r.user_id = u.id
+	         ` + where + `
+	         GROUP BY e.id, e.user_percent`
+
+	rows, err := r.pool.Query(ctx, sql, args...)
+	if err != nil {
+		logger.Error("Ошибка при запросе истории назначения пользователей: %v", err)
+		return nil, fmt.Errorf("не удалось получить историю назначения пользователей: %w", err)
+	}
+	defer rows.Close()
+
+	var history []assignmentHistory
+	for rows.Next() {
+		var h assignmentHistory
+		if err := rows.Scan(&h.userPercent, &h.users, &h.days, &h.recommendations); err != nil {
+			logger.Error("Ошибка при сканировании истории назначения: %v", err)
+			continue
+		}
+		if h.userPercent > 0 {
+			history = append(history, h)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка обработки истории назначения: %w", err)
+	}
+	return history, nil
+}
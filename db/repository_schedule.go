@@ -0,0 +1,173 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing-platform/db/models"
+	"testing-platform/pkg/logger"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// SetExperimentSchedule задает плановые даты запуска и завершения эксперимента (nil — не задано).
+// У запущенного или приостановленного эксперимента меняется только плановое завершение,
+// расписание остановленного, завершенного и архивного эксперимента не меняется
+func (r *Repository) SetExperimentSchedule(ctx context.Context, experimentID int, plannedStart, plannedEnd *time.Time) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	if err := setActor(ctx, tx); err != nil {
+		return err
+	}
+
+	var status string
+	var currentStart *time.Time
+	err = tx.QueryRow(ctx, `SELECT status, planned_start FROM experiments WHERE id = $1 FOR UPDATE`,
+		experimentID).Scan(&status, &currentStart)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("эксперимент с ID %d не найден", experimentID)
+	}
+	if err != nil {
+		logger.Error("Ошибка при получении расписания эксперимента %d: %v", experimentID, err)
+		return fmt.Errorf("не удалось получить расписание эксперимента: %w", err)
+	}
+
+	switch status {
+	case models.StatusStopped, models.StatusConcluded, models.StatusArchived:
+		return fmt.Errorf("эксперимент в статусе «%s»: расписание не меняется", models.StatusTitle(status))
+	case models.StatusRunning, models.StatusPaused:
+		if !sameTime(currentStart, plannedStart) {
+			return errors.New("эксперимент уже запущен: можно изменить только плановое завершение")
+		}
+	}
+	if err := models.ValidateSchedule(status, plannedStart, plannedEnd); err != nil {
+		return err
+	}
+
+	logger.Info("Выполнение DML: расписание эксперимента %d: запуск %s, завершение %s",
+		experimentID, formatPlanned(plannedStart), formatPlanned(plannedEnd))
+	_, err = tx.Exec(ctx, `UPDATE experiments SET planned_start = $1, planned_end = $2 WHERE id = $3`,
+		plannedStart, plannedEnd, experimentID)
+	if err != nil {
+		logger.Error("Ошибка при обновлении расписания эксперимента: %v", err)
+		return fmt.Errorf("не удалось обновить расписание эксперимента: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+// ApplySchedule выполняет переходы по расписанию, наступившие к текущему моменту:
+// запускает запланированные эксперименты, завершает запущенные и приостановленные по плановой дате,
+// а запланированные эксперименты с прошедшим плановым периодом возвращает в черновики.
+// Возвращает число выполненных переходов; ошибка перехода отдельного эксперимента только логируется
+func (r *Repository) ApplySchedule(ctx context.Context) (int, error) {
+	steps := []struct {
+		sql    string
+		status string
+		reason string
+	}{
+		{
+			sql: `SELECT id FROM experiments
+			       WHERE status = 'scheduled' AND planned_start <= CURRENT_TIMESTAMP
+			         AND (planned_end IS NULL OR planned_end > CURRENT_TIMESTAMP)
+			       ORDER BY planned_start, id`,
+			status: models.StatusRunning,
+			reason: "автоматический запуск по расписанию",
+		},
+		{
+			sql: `SELECT id FROM experiments
+			       WHERE status IN ('running', 'paused') AND planned_end <= CURRENT_TIMESTAMP
+			       ORDER BY planned_end, id`,
+			status: models.StatusConcluded,
+			reason: "автоматическое завершение по расписанию",
+		},
+		{
+			sql: `SELECT id FROM experiments
+			       WHERE status = 'scheduled' AND planned_end <= CURRENT_TIMESTAMP
+			       ORDER BY planned_end, id`,
+			status: models.StatusDraft,
+			reason: "плановый период прошел до запуска",
+		},
+	}
+
+	var applied int
+	for _, step := range steps {
+		ids, err := r.queryExperimentIDs(ctx, step.sql)
+		if err != nil {
+			return applied, err
+		}
+		for _, id := range ids {
+			if err := r.TransitionExperiment(ctx, id, step.status, step.reason); err != nil {
+				logger.Error("Планировщик: не удалось перевести эксперимент %d в статус %s: %v", id, step.status, err)
+				continue
+			}
+			logger.Info("Планировщик: эксперимент %d переведен в статус %s (%s)", id, step.status, step.reason)
+			applied++
+		}
+	}
+	return applied, nil
+}
+
+// queryExperimentIDs возвращает ID экспериментов, выбранных запросом sql
+func (r *Repository) queryExperimentIDs(ctx context.Context, sql string) ([]int, error) {
+	rows, err := r.pool.Query(ctx, sql)
+	if err != nil {
+		logger.Error("Ошибка при запросе экспериментов по расписанию: %v", err)
+		return nil, fmt.Errorf("не удалось получить эксперименты по расписанию: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			logger.Error("Ошибка при сканировании ID эксперимента: %v", err)
+			continue
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка обработки экспериментов по расписанию: %w", err)
+	}
+	return ids, nil
+}
+
+// RunExperimentScheduler периодически выполняет переходы экспериментов по расписанию
+// до отмены ctx. Предназначен для запуска в отдельной горутине
+func (r *Repository) RunExperimentScheduler(ctx context.Context, interval time.Duration) {
+	logger.Info("Запуск планировщика экспериментов с интервалом %s", interval)
+	ctx = WithActor(ctx, "планировщик экспериментов")
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.ApplySchedule(ctx); err != nil && ctx.Err() == nil {
+			logger.Error("Ошибка планировщика экспериментов: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			logger.Info("Планировщик экспериментов остановлен")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// sameTime сравнивает необязательные даты
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return a.Equal(*b)
+}
+
+// formatPlanned форматирует плановую дату для журнала
+func formatPlanned(t *time.Time) string {
+	if t == nil {
+		return "не задано"
+	}
+	return t.Format("2006-01-02 15:04")
+}
@@ -0,0 +1,104 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing-platform/db/models"
+	"testing-platform/pkg/logger"
+)
+
+// segmentExpressions SQL-выражения сегмента для каждого измерения
+var segmentExpressions = map[string]string{
+	models.SegmentByTag:     `COALESCE(t.tag, '` + models.SegmentUnspecified + `')`,
+	models.SegmentByDevice:  `COALESCE(NULLIF(u.device, ''), '` + models.SegmentUnspecified + `')`,
+	models.SegmentByCountry: `COALESCE(NULLIF(u.country, ''), '` + models.SegmentUnspecified + `')`,
+	models.SegmentByCohort:  `COALESCE(NULLIF(u.cohort, ''), '` + models.SegmentUnspecified + `')`,
+}
+
+// GetSegmentStats разбивает статистику групп по сегментам измерения dimension и сравнивает
+// CTR тестовых групп с контролем внутри каждого сегмента. experimentID 0 объединяет все эксперименты,
+// что имеет смысл для тегов: у пользователей одного эксперимента теги общие. Группа A одного эксперимента
+// не контроль для группы B другого, поэтому при объединении возвращаются только итоги сегмента
+// (группа models.SegmentPooledGroup) без сравнений
+func (r *Repository) GetSegmentStats(ctx context.Context, experimentID int, dimension string, confidence float64) (*models.SegmentReport, error) {
+	logger.Info("Сегментный анализ эксперимента %d по измерению %s", experimentID, dimension)
+
+	expr, ok := segmentExpressions[dimension]
+	if !ok {
+		return nil, fmt.Errorf("неизвестное измерение сегментации %q", dimension)
+	}
+	if confidence <= 0 || confidence >= 1 {
+		return nil, errors.New("уровень доверия должен быть в интервале (0, 1)")
+	}
+
+	tagJoin := ""
+	if dimension == models.SegmentByTag {
+		tagJoin = `LEFT JOIN LATERAL unnest(e.tags) AS t(tag) ON true`
+	}
+
+	sql := fmt.Sprintf(`SELECT %s AS segment,
+	                CASE WHEN $1 = 0 THEN '`+models.SegmentPooledGroup+`' ELSE u.group_name END,
+	                COUNT(DISTINCT u.id),
+	                COUNT(r.id),
+	                COUNT(r.id) FILTER (WHERE r.clicked),
+	                COALESCE(AVG(r.rating) FILTER (WHERE r.rating > 0), 0)
+	         FROM users u
+	         JOIN experiments e ON e.id = u.experiment_id
+	         %s
+	         LEFT JOIN results r ON r.user_id = u.id
+	         WHERE ($1 = 0 OR u.experiment_id = $1)
+	         GROUP BY 1, 2
+	         ORDER BY 1, 2`, expr, tagJoin)
+
+	rows, err := r.pool.Query(ctx, sql, experimentID)
+	if err != nil {
+		logger.Error("Ошибка при запросе сегментной статистики: %v", err)
+		return nil, fmt.Errorf("не удалось получить сегментную статистику: %w", err)
+	}
+	defer rows.Close()
+
+	report := &models.SegmentReport{ExperimentID: experimentID, Dimension: dimension, Confidence: confidence}
+	index := make(map[string]int)
+	for rows.Next() {
+		var segment string
+		var g models.GroupStats
+		if err := rows.Scan(&segment, &g.Group, &g.Users, &g.TotalRecommendations, &g.TotalClicks, &g.AvgRating); err != nil {
+			logger.Error("Ошибка при сканировании сегментной статистики: %v", err)
+			continue
+		}
+		if g.TotalRecommendations > 0 {
+			g.CTR = float64(g.TotalClicks) / float64(g.TotalRecommendations)
+		}
+
+		i, ok := index[segment]
+		if !ok {
+			i = len(report.Segments)
+			index[segment] = i
+			report.Segments = append(report.Segments, models.SegmentStats{
+				Segment: segment,
+				Groups:  make(map[string]models.GroupStats),
+			})
+		}
+		report.Segments[i].Groups[g.Group] = g
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка обработки сегментной статистики: %w", err)
+	}
+
+	compareSegments(report)
+
+	logger.Info("Сегментный анализ эксперимента %d: %d сегментов", experimentID, len(report.Segments))
+	return report, nil
+}
+
+// compareSegments сравнивает CTR тестовых групп с контролем внутри каждого сегмента отчета;
+// отчет по всем экспериментам (ExperimentID 0) содержит только итоги сегментов и не сравнивается
+func compareSegments(report *models.SegmentReport) {
+	if report.ExperimentID == 0 {
+		return
+	}
+	for i := range report.Segments {
+		report.Segments[i].Comparisons = compareGroupsCTR(report.Segments[i].Groups, report.Confidence)
+	}
+}
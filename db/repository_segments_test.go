@@ -0,0 +1,42 @@
+package db
+
+import (
+	"testing"
+	"testing-platform/db/models"
+)
+
+func TestCompareSegments(t *testing.T) {
+	groups := func(names ...string) map[string]models.GroupStats {
+		g := make(map[string]models.GroupStats)
+		for _, name := range names {
+			g[name] = models.GroupStats{Group: name, TotalRecommendations: 200, TotalClicks: 20}
+		}
+		return g
+	}
+
+	report := &models.SegmentReport{ExperimentID: 7, Confidence: 0.95, Segments: []models.SegmentStats{
+		{Segment: "mobile", Groups: groups("C", "A", "B")},
+		// сегмент без контрольной группы сравнить не с чем
+		{Segment: "tv", Groups: groups("B")},
+	}}
+	compareSegments(report)
+	mobile := report.Segments[0].Comparisons
+	if len(mobile) != 2 || mobile[0].TreatmentGroup != "B" || mobile[1].TreatmentGroup != "C" {
+		t.Fatalf("сравнения сегмента mobile: %+v, ожидается B и C против A", mobile)
+	}
+	if mobile[0].ControlGroup != models.ControlGroup || mobile[0].AbsoluteLift != 0 || mobile[0].PValue != 1 {
+		t.Errorf("равные CTR: %+v, ожидается нулевая разность и p = 1", mobile[0])
+	}
+	if report.Segments[1].Comparisons != nil {
+		t.Errorf("сегмент без контроля: %+v, ожидается без сравнений", report.Segments[1].Comparisons)
+	}
+
+	// группы разных экспериментов не сравниваются между собой
+	pooled := &models.SegmentReport{Confidence: 0.95, Segments: []models.SegmentStats{
+		{Segment: "mobile", Groups: groups(models.SegmentPooledGroup)},
+	}}
+	compareSegments(pooled)
+	if pooled.Segments[0].Comparisons != nil {
+		t.Errorf("отчет по всем экспериментам: %+v, ожидается без сравнений", pooled.Segments[0].Comparisons)
+	}
+}
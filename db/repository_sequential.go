@@ -0,0 +1,262 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"testing-platform/db/models"
+	"testing-platform/pkg/logger"
+	"testing-platform/pkg/stats"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// доля объединенного CTR, используемая как стандартное отклонение смешивающего распределения mSPRT
+const (
+	sequentialMixingShare = 0.1
+	sequentialMinMixingSD = 0.001
+)
+
+// список столбцов таблицы sequential_looks в порядке сканирования
+const sequentialLookColumns = `id, experiment_id, treatment_group, look_number, looked_at,
+	control_recommendations, control_clicks, treatment_recommendations, treatment_clicks,
+	estimate, z_score, likelihood_ratio, z_boundary, p_value, alpha, decision`
+
+// RecordSequentialLook фиксирует очередной просмотр запущенного эксперимента в последовательном тесте (mSPRT):
+// для каждой тестовой группы считает всегда валидное p-значение и границу остановки и сохраняет просмотр.
+// Просмотр фиксируется только явным действием, а не при каждом расчете статистики: иначе число
+// просмотров зависело бы от того, как часто открывают окно. Строка эксперимента блокируется на время
+// фиксации, поэтому параллельные просмотры не получают одинаковый номер и не опираются на устаревший предыдущий.
+// Если с прошлого просмотра объем выборки группы уменьшился (удалены результаты), просмотр не фиксируется:
+// накопленный минимум p-значения тогда уже не соответствует данным
+func (r *Repository) RecordSequentialLook(ctx context.Context, experimentID int, alpha float64) (*models.SequentialStatus, error) {
+	logger.Info("Фиксация просмотра последовательного теста для эксперимента %d", experimentID)
+
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback(ctx)
+
+	var expStatus string
+	err = tx.QueryRow(ctx, `SELECT status FROM experiments WHERE id = $1 FOR UPDATE`, experimentID).Scan(&expStatus)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("эксперимент с ID %d не найден", experimentID)
+	}
+	if err != nil {
+		logger.Error("Ошибка при блокировке эксперимента %d: %v", experimentID, err)
+		return nil, fmt.Errorf("не удалось заблокировать эксперимент: %w", err)
+	}
+	if expStatus != models.StatusRunning {
+		return nil, fmt.Errorf("просмотр фиксируется только у запущенного эксперимента (статус «%s»)", models.StatusTitle(expStatus))
+	}
+
+	groups, err := sequentialGroupCounts(ctx, tx, experimentID)
+	if err != nil {
+		return nil, err
+	}
+	control, ok := groups[models.ControlGroup]
+	if !ok {
+		return nil, fmt.Errorf("в эксперименте нет контрольной группы %s", models.ControlGroup)
+	}
+
+	status := &models.SequentialStatus{ExperimentID: experimentID, Decision: models.SequentialContinue}
+	for _, name := range sortedTreatmentGroups(groups) {
+		treatment := groups[name]
+
+		// предыдущий просмотр нужен для всегда валидного p-значения и проверки роста выборки
+		lookNumber, prevPValue := 0, 1.0
+		var prevControlN, prevTreatmentN int
+		err := tx.QueryRow(ctx, `SELECT look_number, p_value, control_recommendations, treatment_recommendations
+		         FROM sequential_looks
+		         WHERE experiment_id = $1 AND treatment_group = $2
+		         ORDER BY look_number DESC LIMIT 1`, experimentID, name).Scan(&lookNumber, &prevPValue, &prevControlN, &prevTreatmentN)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			logger.Error("Ошибка при получении предыдущего просмотра: %v", err)
+			return nil, fmt.Errorf("не удалось получить предыдущий просмотр: %w", err)
+		}
+		if control.TotalRecommendations < prevControlN || treatment.TotalRecommendations < prevTreatmentN {
+			logger.Warn("Эксперимент %d, группа %s: выборка уменьшилась с прошлого просмотра (%d/%d -> %d/%d)", experimentID, name,
+				prevControlN, prevTreatmentN, control.TotalRecommendations, treatment.TotalRecommendations)
+			return nil, fmt.Errorf("объем выборки группы %s или контроля уменьшился с просмотра №%d (%d/%d рекомендаций, сейчас %d/%d): "+
+				"последовательный тест предполагает только рост выборки, просмотр не зафиксирован", name, lookNumber,
+				prevControlN, prevTreatmentN, control.TotalRecommendations, treatment.TotalRecommendations)
+		}
+
+		pooled := 0.0
+		if n := control.TotalRecommendations + treatment.TotalRecommendations; n > 0 {
+			pooled = float64(control.TotalClicks+treatment.TotalClicks) / float64(n)
+		}
+		mixingSD := math.Max(pooled*sequentialMixingShare, sequentialMinMixingSD)
+
+		res, err := stats.MSPRT(control.TotalClicks, control.TotalRecommendations,
+			treatment.TotalClicks, treatment.TotalRecommendations, alpha, mixingSD, prevPValue)
+		if err != nil {
+			return nil, fmt.Errorf("не удалось выполнить последовательный тест: %w", err)
+		}
+
+		look := models.SequentialLook{
+			ExperimentID:             experimentID,
+			TreatmentGroup:           name,
+			LookNumber:               lookNumber + 1,
+			ControlRecommendations:   control.TotalRecommendations,
+			ControlClicks:            control.TotalClicks,
+			TreatmentRecommendations: treatment.TotalRecommendations,
+			TreatmentClicks:          treatment.TotalClicks,
+			Estimate:                 res.Estimate,
+			ZScore:                   res.ZScore,
+			LikelihoodRatio:          res.LikelihoodRatio,
+			ZBoundary:                res.ZBoundary,
+			PValue:                   res.PValue,
+			Alpha:                    alpha,
+			Decision:                 models.SequentialContinue,
+		}
+		if res.Reject {
+			if res.Estimate > 0 {
+				look.Decision = models.SequentialStopFor(name)
+			} else {
+				look.Decision = models.SequentialStopFor(models.ControlGroup)
+			}
+		}
+
+		sql := `INSERT INTO sequential_looks (experiment_id, treatment_group, look_number,
+		             control_recommendations, control_clicks, treatment_recommendations, treatment_clicks,
+		             estimate, z_score, likelihood_ratio, z_boundary, p_value, alpha, decision)
+		         VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		         RETURNING id, looked_at`
+		err = tx.QueryRow(ctx, sql, look.ExperimentID, look.TreatmentGroup, look.LookNumber,
+			look.ControlRecommendations, look.ControlClicks, look.TreatmentRecommendations, look.TreatmentClicks,
+			look.Estimate, look.ZScore, look.LikelihoodRatio, look.ZBoundary, look.PValue, look.Alpha, look.Decision,
+		).Scan(&look.ID, &look.LookedAt)
+		if err != nil {
+			logger.Error("Ошибка при сохранении просмотра: %v", err)
+			return nil, fmt.Errorf("не удалось сохранить просмотр последовательного теста: %w", err)
+		}
+		status.Looks = append(status.Looks, look)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, err
+	}
+	fillSequentialDecision(status)
+
+	logger.Info("Просмотр последовательного теста для эксперимента %d сохранен, решение: %s", experimentID, status.Decision)
+	return status, nil
+}
+
+// sequentialGroupCounts возвращает число рекомендаций и кликов по группам эксперимента
+// в транзакции фиксации просмотра
+func sequentialGroupCounts(ctx context.Context, tx pgx.Tx, experimentID int) (map[string]models.GroupStats, error) {
+	sql := `SELECT u.group_name, COUNT(r.id), COUNT(r.id) FILTER (WHERE r.clicked)
+	         FROM users u
+	         LEFT JOIN results r ON u.id = r.user_id
+	         WHERE u.experiment_id = $1
+	         GROUP BY u.group_name`
+	rows, err := tx.Query(ctx, sql, experimentID)
+	if err != nil {
+		logger.Error("Ошибка при запросе данных групп для просмотра: %v", err)
+		return nil, fmt.Errorf("не удалось получить данные групп эксперимента: %w", err)
+	}
+	defer rows.Close()
+
+	groups := make(map[string]models.GroupStats)
+	for rows.Next() {
+		var g models.GroupStats
+		if err := rows.Scan(&g.Group, &g.TotalRecommendations, &g.TotalClicks); err != nil {
+			return nil, fmt.Errorf("ошибка чтения данных группы: %w", err)
+		}
+		groups[g.Group] = g
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка обработки данных групп: %w", err)
+	}
+	return groups, nil
+}
+
+// fillSequentialDecision заполняет решение, границу и время по последним просмотрам групп
+func fillSequentialDecision(status *models.SequentialStatus) {
+	status.Decision = models.SequentialContinue
+	status.Boundary = sequentialDecision(status)
+	for i := range status.Looks {
+		if status.UpdatedAt == nil || status.Looks[i].LookedAt.After(*status.UpdatedAt) {
+			status.UpdatedAt = &status.Looks[i].LookedAt
+		}
+	}
+}
+
+// sequentialDecision определяет решение эксперимента по последним просмотрам групп
+// и возвращает границу |z| просмотра, определившего решение
+func sequentialDecision(status *models.SequentialStatus) *float64 {
+	if len(status.Looks) == 0 {
+		return nil
+	}
+
+	// победившая тестовая группа с наибольшим приростом
+	var winner *models.SequentialLook
+	controlWins := 0
+	for i := range status.Looks {
+		look := &status.Looks[i]
+		switch look.Decision {
+		case models.SequentialStopFor(look.TreatmentGroup):
+			if winner == nil || look.Estimate > winner.Estimate {
+				winner = look
+			}
+		case models.SequentialStopFor(models.ControlGroup):
+			controlWins++
+		}
+	}
+	if winner != nil {
+		status.Decision = winner.Decision
+		return &winner.ZBoundary
+	}
+	// контроль побеждает, только если он лучше всех тестовых групп
+	if controlWins == len(status.Looks) {
+		status.Decision = models.SequentialStopFor(models.ControlGroup)
+		return &status.Looks[0].ZBoundary
+	}
+
+	// без решения показывается граница наиболее близкого к остановке сравнения
+	closest := &status.Looks[0]
+	for i := range status.Looks {
+		if math.Abs(status.Looks[i].ZScore) > math.Abs(closest.ZScore) {
+			closest = &status.Looks[i]
+		}
+	}
+	status.Decision = models.SequentialContinue
+	return &closest.ZBoundary
+}
+
+// GetSequentialStatus возвращает последний просмотр по каждой тестовой группе
+// и решение последовательного теста, определенное по этим просмотрам
+func (r *Repository) GetSequentialStatus(ctx context.Context, experimentID int) (*models.SequentialStatus, error) {
+	status := &models.SequentialStatus{ExperimentID: experimentID}
+
+	sql := `SELECT DISTINCT ON (treatment_group) ` + sequentialLookColumns + `
+	         FROM sequential_looks
+	         WHERE experiment_id = $1
+	         ORDER BY treatment_group, look_number DESC`
+	rows, err := r.pool.Query(ctx, sql, experimentID)
+	if err != nil {
+		logger.Error("Ошибка при запросе просмотров последовательного теста: %v", err)
+		return nil, fmt.Errorf("не удалось получить просмотры последовательного теста: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var look models.SequentialLook
+		err := rows.Scan(&look.ID, &look.ExperimentID, &look.TreatmentGroup, &look.LookNumber, &look.LookedAt,
+			&look.ControlRecommendations, &look.ControlClicks, &look.TreatmentRecommendations, &look.TreatmentClicks,
+			&look.Estimate, &look.ZScore, &look.LikelihoodRatio, &look.ZBoundary, &look.PValue, &look.Alpha, &look.Decision)
+		if err != nil {
+			logger.Error("Ошибка при сканировании просмотра: %v", err)
+			continue
+		}
+		status.Looks = append(status.Looks, look)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка обработки просмотров: %w", err)
+	}
+	fillSequentialDecision(status)
+	return status, nil
+}
@@ -0,0 +1,158 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"testing-platform/db/models"
+	"testing-platform/pkg/logger"
+	"testing-platform/pkg/stats"
+)
+
+// expectedAllocation возвращает ожидаемые доли групп эксперимента
+func (r *Repository) expectedAllocation(ctx context.Context, experimentID int) (map[string]float64, error) {
+	variants, err := r.GetVariants(ctx, experimentID)
+	if err != nil {
+		return nil, err
+	}
+	if len(variants) == 0 {
+		return nil, fmt.Errorf("у эксперимента %d нет вариантов", experimentID)
+	}
+	// пользователи делятся между вариантами пропорционально весам
+	allocation := make(map[string]float64, len(variants))
+	for _, v := range variants {
+		allocation[v.Name] = v.Weight / 100
+	}
+	return allocation, nil
+}
+
+// CheckSampleRatio проверяет, соответствует ли распределение пользователей эксперимента
+// по группам ожидаемому (критерий хи-квадрат)
+func (r *Repository) CheckSampleRatio(ctx context.Context, experimentID int) (*models.SRMCheck, error) {
+	counts, err := r.getGroupUserCounts(ctx, experimentID)
+	if err != nil {
+		return nil, err
+	}
+	return r.sampleRatioCheck(ctx, experimentID, counts[experimentID])
+}
+
+// CheckSampleRatios проверяет соотношение групп во всех экспериментах с пользователями
+func (r *Repository) CheckSampleRatios(ctx context.Context) ([]models.SRMCheck, error) {
+	logger.Info("Проверка соотношения групп во всех экспериментах")
+
+	counts, err := r.getGroupUserCounts(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	ids := make([]int, 0, len(counts))
+	for id := range counts {
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+
+	var checks []models.SRMCheck
+	for _, id := range ids {
+		check, err := r.sampleRatioCheck(ctx, id, counts[id])
+		if err != nil {
+			logger.Warn("Проверка соотношения групп эксперимента %d не выполнена: %v", id, err)
+			continue
+		}
+		checks = append(checks, *check)
+	}
+
+	logger.Info("Проверено экспериментов: %d", len(checks))
+	return checks, nil
+}
+
+// sampleRatioCheck выполняет критерий хи-квадрат для числа пользователей по группам
+func (r *Repository) sampleRatioCheck(ctx context.Context, experimentID int, observed map[string]int) (*models.SRMCheck, error) {
+	allocation, err := r.expectedAllocation(ctx, experimentID)
+	if err != nil {
+		return nil, err
+	}
+
+	check := &models.SRMCheck{
+		ExperimentID: experimentID,
+		Observed:     make(map[string]int),
+		Expected:     make(map[string]float64),
+		PValue:       1,
+	}
+
+	// пользователи в группах, которых нет в плане распределения, — явное нарушение
+	for group, n := range observed {
+		if _, ok := allocation[group]; !ok && n > 0 {
+			check.Observed[group] = n
+			check.Mismatch = true
+		}
+	}
+
+	groups := make([]string, 0, len(allocation))
+	for group := range allocation {
+		groups = append(groups, group)
+	}
+	sort.Strings(groups)
+
+	counts := make([]int, len(groups))
+	shares := make([]float64, len(groups))
+	for i, group := range groups {
+		counts[i] = observed[group]
+		shares[i] = allocation[group]
+		check.Observed[group] = observed[group]
+	}
+
+	test, err := stats.ChiSquareGoodnessOfFit(counts, shares)
+	if err != nil {
+		// без пользователей проверять нечего
+		return check, nil
+	}
+	for i, group := range groups {
+		check.Expected[group] = test.Expected[i]
+	}
+	check.ChiSquare = test.Statistic
+	check.DF = test.DF
+	check.PValue = test.PValue
+	if check.PValue < models.SRMThreshold {
+		check.Mismatch = true
+	}
+
+	if check.Mismatch {
+		logger.Warn("Несоответствие соотношения групп в эксперименте %d: chi2 = %.2f, p = %.6f",
+			experimentID, check.ChiSquare, check.PValue)
+	}
+	return check, nil
+}
+
+// getGroupUserCounts возвращает число пользователей по группам для эксперимента
+// (или для всех экспериментов, если experimentID = 0)
+func (r *Repository) getGroupUserCounts(ctx context.Context, experimentID int) (map[int]map[string]int, error) {
+	sql := `SELECT experiment_id, group_name, COUNT(*)
+	         FROM users
+	         WHERE $1 = 0 OR experiment_id = $1
+	         GROUP BY experiment_id, group_name`
+
+	rows, err := r.pool.Query(ctx, sql, experimentID)
+	if err != nil {
+		logger.Error("Ошибка при подсчете пользователей по группам: %v", err)
+		return nil, fmt.Errorf("не удалось подсчитать пользователей по группам: %w", err)
+	}
+	defer rows.Close()
+
+	counts := make(map[int]map[string]int)
+	for rows.Next() {
+		var id, n int
+		var group string
+		if err := rows.Scan(&id, &group, &n); err != nil {
+			logger.Error("Ошибка при сканировании числа пользователей: %v", err)
+			continue
+		}
+		if counts[id] == nil {
+			counts[id] = make(map[string]int)
+		}
+		counts[id][group] = n
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка обработки числа пользователей: %w", err)
+	}
+	return counts, nil
+}
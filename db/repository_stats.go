@@ -0,0 +1,309 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"testing-platform/db/models"
+	"testing-platform/pkg/logger"
+	"testing-platform/pkg/stats"
+)
+
+// sortedTreatmentGroups возвращает отсортированные названия тестовых групп (все, кроме контрольной)
+func sortedTreatmentGroups[T any](groups map[string]T) []string {
+	var names []string
+	for name := range groups {
+		if name != models.ControlGroup {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// compareGroupsCTR выполняет z-тест CTR каждой тестовой группы против контрольной
+func compareGroupsCTR(groups map[string]models.GroupStats, confidence float64) []models.SignificanceTest {
+	control, ok := groups[models.ControlGroup]
+	if !ok {
+		return nil
+	}
+
+	var comparisons []models.SignificanceTest
+	for _, name := range sortedTreatmentGroups(groups) {
+		treatment := groups[name]
+		test, err := stats.TwoProportionZTest(control.TotalClicks, control.TotalRecommendations,
+			treatment.TotalClicks, treatment.TotalRecommendations, confidence)
+		if err != nil {
+			logger.Warn("Не удалось сравнить группы %s и %s: %v", models.ControlGroup, name, err)
+			continue
+		}
+		comparisons = append(comparisons, models.SignificanceTest{
+			ControlGroup:   models.ControlGroup,
+			TreatmentGroup: name,
+			AbsoluteLift:   test.AbsoluteLift,
+			RelativeLift:   test.RelativeLift,
+			StandardError:  test.StandardError,
+			ZScore:         test.ZScore,
+			PValue:         test.PValue,
+			CILower:        test.CILower,
+			CIUpper:        test.CIUpper,
+			Significant:    test.PValue < 1-confidence,
+		})
+	}
+	return comparisons
+}
+
+// GetRatingStats возвращает анализ оценок эксперимента по группам:
+// дисперсию и размер выборки, t-тест Уэлча и U-тест Манна–Уитни против контрольной группы
+func (r *Repository) GetRatingStats(ctx context.Context, experimentID int, confidence float64) (*models.RatingStats, error) {
+	logger.Info("Запрос анализа оценок для эксперимента %d", experimentID)
+	if confidence <= 0 || confidence >= 1 {
+		return nil, errors.New("уровень доверия должен быть в интервале (0, 1)")
+	}
+
+	ratings, err := r.getGroupRatings(ctx, experimentID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.RatingStats{
+		ExperimentID: experimentID,
+		Confidence:   confidence,
+		Groups:       make(map[string]models.RatingGroupStats),
+	}
+	for group, values := range ratings {
+		summary := stats.Summarize(values)
+		result.Groups[group] = models.RatingGroupStats{
+			Group:    group,
+			N:        summary.N,
+			Mean:     summary.Mean,
+			Variance: summary.Variance,
+			StdDev:   summary.StdDev,
+			Median:   summary.Median,
+		}
+	}
+
+	control := ratings[models.ControlGroup]
+	for _, name := range sortedTreatmentGroups(ratings) {
+		comparison := models.RatingComparison{
+			ControlGroup:   models.ControlGroup,
+			TreatmentGroup: name,
+		}
+
+		if welch, err := stats.WelchTTest(control, ratings[name], confidence); err != nil {
+			logger.Warn("t-тест Уэлча для групп %s и %s не выполнен: %v", models.ControlGroup, name, err)
+		} else {
+			comparison.Welch = welchResult(welch)
+		}
+
+		if mw, err := stats.MannWhitneyU(control, ratings[name]); err != nil {
+			logger.Warn("U-тест Манна–Уитни для групп %s и %s не выполнен: %v", models.ControlGroup, name, err)
+		} else {
+			comparison.MannWhitney = &models.MannWhitneyResult{
+				U:                      mw.U,
+				ZScore:                 mw.ZScore,
+				PValue:                 mw.PValue,
+				ProbabilitySuperiority: mw.ProbabilitySuperiority,
+			}
+		}
+
+		result.Comparisons = append(result.Comparisons, comparison)
+	}
+
+	logger.Info("Анализ оценок для эксперимента %d успешно выполнен", experimentID)
+	return result, nil
+}
+
+// getGroupRatings возвращает поставленные оценки (rating > 0) по группам эксперимента
+func (r *Repository) getGroupRatings(ctx context.Context, experimentID int) (map[string][]float64, error) {
+	sql := `SELECT u.group_name, r.rating
+	         FROM users u
+	         JOIN results r ON u.id = r.user_id
+	         WHERE u.experiment_id = $1 AND r.rating > 0`
+
+	rows, err := r.pool.Query(ctx, sql, experimentID)
+	if err != nil {
+		logger.Error("Ошибка при запросе оценок эксперимента: %v", err)
+		return nil, fmt.Errorf("не удалось получить оценки эксперимента: %w", err)
+	}
+	defer rows.Close()
+
+	ratings := make(map[string][]float64)
+	for rows.Next() {
+		var group string
+		var rating int
+		if err := rows.Scan(&group, &rating); err != nil {
+			logger.Error("Ошибка при сканировании оценки: %v", err)
+			continue
+		}
+		ratings[group] = append(ratings[group], float64(rating))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка обработки оценок: %w", err)
+	}
+	return ratings, nil
+}
+
+// GetRatingDistribution возвращает частоты значений оценки 0–5 (0 — рекомендация не оценена)
+// по группам эксперимента и проверяет однородность распределений критерием хи-квадрат
+func (r *Repository) GetRatingDistribution(ctx context.Context, experimentID int) (*models.RatingDistribution, error) {
+	logger.Info("Запрос распределения оценок для эксперимента %d", experimentID)
+
+	sql := `SELECT u.group_name, COALESCE(r.rating, 0), COUNT(*)
+	         FROM users u
+	         JOIN results r ON u.id = r.user_id
+	         WHERE u.experiment_id = $1
+	         GROUP BY 1, 2`
+
+	rows, err := r.pool.Query(ctx, sql, experimentID)
+	if err != nil {
+		logger.Error("Ошибка при запросе распределения оценок: %v", err)
+		return nil, fmt.Errorf("не удалось получить распределение оценок: %w", err)
+	}
+	defer rows.Close()
+
+	dist := &models.RatingDistribution{
+		ExperimentID: experimentID,
+		Values:       models.RatingValues,
+		Counts:       make(map[string][]int),
+		Shares:       make(map[string][]float64),
+	}
+	for rows.Next() {
+		var group string
+		var rating, count int
+		if err := rows.Scan(&group, &rating, &count); err != nil {
+			logger.Error("Ошибка при сканировании частоты оценки: %v", err)
+			continue
+		}
+		i := slices.Index(dist.Values, rating)
+		if i < 0 {
+			logger.Warn("Пропущено недопустимое значение оценки %d", rating)
+			continue
+		}
+		if dist.Counts[group] == nil {
+			dist.Counts[group] = make([]int, len(dist.Values))
+		}
+		dist.Counts[group][i] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка обработки распределения оценок: %w", err)
+	}
+
+	groups := make([]string, 0, len(dist.Counts))
+	for group, counts := range dist.Counts {
+		groups = append(groups, group)
+		var total int
+		for _, c := range counts {
+			total += c
+		}
+		shares := make([]float64, len(counts))
+		for i, c := range counts {
+			shares[i] = float64(c) / float64(total)
+		}
+		dist.Shares[group] = shares
+	}
+	sort.Strings(groups)
+
+	if len(groups) >= 2 {
+		table := make([][]int, len(groups))
+		for i, group := range groups {
+			table[i] = dist.Counts[group]
+		}
+		if test, err := stats.ChiSquareHomogeneity(table); err != nil {
+			logger.Warn("Критерий хи-квадрат для распределения оценок эксперимента %d не выполнен: %v", experimentID, err)
+		} else {
+			dist.ChiSquare = &models.ChiSquareResult{Statistic: test.Statistic, DF: test.DF, PValue: test.PValue}
+			for _, e := range test.Expected {
+				if e < 5 {
+					dist.ChiSquare.LowExpectedCells++
+				}
+			}
+		}
+	}
+
+	logger.Info("Распределение оценок для эксперимента %d успешно получено", experimentID)
+	return dist, nil
+}
+
+// параметры байесовского анализа по умолчанию: равномерный априор Beta(1, 1)
+const (
+	bayesianPriorAlpha = 1.0
+	bayesianPriorBeta  = 1.0
+	bayesianDraws      = 100000
+)
+
+// GetBayesianStats возвращает байесовский анализ CTR эксперимента:
+// Beta-Binomial апостериорные распределения групп, вероятность превзойти контроль,
+// ожидаемые потери и интервалы достоверности, рассчитанные методом Монте-Карло
+func (r *Repository) GetBayesianStats(ctx context.Context, experimentID int, credibility float64) (*models.BayesianStats, error) {
+	logger.Info("Запрос байесовского анализа для эксперимента %d", experimentID)
+
+	expStats, err := r.GetExperimentStats(ctx, experimentID)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := expStats.Groups[models.ControlGroup]; !ok {
+		return nil, fmt.Errorf("в эксперименте нет контрольной группы %s", models.ControlGroup)
+	}
+
+	// контрольная группа первая, далее тестовые по алфавиту
+	names := append([]string{models.ControlGroup}, sortedTreatmentGroups(expStats.Groups)...)
+	arms := make([]stats.BinomialArm, 0, len(names))
+	for _, name := range names {
+		g := expStats.Groups[name]
+		arms = append(arms, stats.BinomialArm{Name: name, Successes: g.TotalClicks, Trials: g.TotalRecommendations})
+	}
+
+	posteriors, err := stats.BetaBinomialAnalysis(arms, stats.BayesianOptions{
+		PriorAlpha:  bayesianPriorAlpha,
+		PriorBeta:   bayesianPriorBeta,
+		Draws:       bayesianDraws,
+		Credibility: credibility,
+		Seed:        uint64(experimentID), // фиксированное зерно: одинаковый результат при повторном просмотре
+	})
+	if err != nil {
+		logger.Error("Ошибка байесовского анализа эксперимента %d: %v", experimentID, err)
+		return nil, fmt.Errorf("не удалось выполнить байесовский анализ: %w", err)
+	}
+
+	result := &models.BayesianStats{
+		ExperimentID: experimentID,
+		Credibility:  credibility,
+		Draws:        bayesianDraws,
+		PriorAlpha:   bayesianPriorAlpha,
+		PriorBeta:    bayesianPriorBeta,
+	}
+	for i, p := range posteriors {
+		result.Groups = append(result.Groups, models.BayesianGroupStats{
+			Group:           p.Name,
+			Clicks:          arms[i].Successes,
+			Recommendations: arms[i].Trials,
+			Alpha:           p.Alpha,
+			Beta:            p.Beta,
+			PosteriorMean:   p.Mean,
+			CredibleLower:   p.CredibleLower,
+			CredibleUpper:   p.CredibleUpper,
+			ProbBeatControl: p.ProbBeatControl,
+			ProbBest:        p.ProbBest,
+			ExpectedLoss:    p.ExpectedLoss,
+		})
+	}
+
+	logger.Info("Байесовский анализ для эксперимента %d успешно выполнен", experimentID)
+	return result, nil
+}
+
+// welchResult преобразует результат t-теста Уэлча в модель
+func welchResult(welch stats.WelchTest) *models.WelchTestResult {
+	return &models.WelchTestResult{
+		MeanDiff:         welch.MeanDiff,
+		StandardError:    welch.StandardError,
+		TStatistic:       welch.TStatistic,
+		DegreesOfFreedom: welch.DF,
+		PValue:           welch.PValue,
+		CILower:          welch.CILower,
+		CIUpper:          welch.CIUpper,
+	}
+}
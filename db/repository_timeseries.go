@@ -0,0 +1,191 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing-platform/db/models"
+	"testing-platform/pkg/logger"
+	"testing-platform/pkg/stats"
+	"time"
+)
+
+// максимальное число интервалов временного ряда
+const maxTimeSeriesBuckets = 5000
+
+// timeSeriesRow показатели группы за интервал
+type timeSeriesRow struct {
+	recommendations int
+	clicks          int
+	ratingSum       int
+	ratings         int
+}
+
+// nextBucket возвращает начало следующего интервала
+func nextBucket(bucket time.Time, granularity string) time.Time {
+	if granularity == models.TimeGranularityHour {
+		return bucket.Add(time.Hour)
+	}
+	return bucket.AddDate(0, 0, 1)
+}
+
+// GetExperimentTimeSeries возвращает показы, клики, CTR и среднюю оценку групп эксперимента
+// по дням или часам, а также накопленный прирост CTR тестовых групп относительно контроля.
+// Интервалы без событий включаются в ряд с нулевыми значениями
+func (r *Repository) GetExperimentTimeSeries(ctx context.Context, experimentID int, granularity string, confidence float64) (*models.ExperimentTimeSeries, error) {
+	logger.Info("Запрос временного ряда эксперимента %d (шаг %s)", experimentID, granularity)
+
+	if granularity != models.TimeGranularityDay && granularity != models.TimeGranularityHour {
+		return nil, fmt.Errorf("неизвестный шаг временного ряда %q", granularity)
+	}
+	if confidence <= 0 || confidence >= 1 {
+		return nil, errors.New("уровень доверия должен быть в интервале (0, 1)")
+	}
+
+	// оценки, как и в GetExperimentStats, учитываются по всем результатам с rating > 0
+	// и относятся к интервалу показа; клики — к интервалу времени клика
+	sql := `WITH impressions AS (
+	            SELECT u.group_name, date_trunc($2, r.created_at) AS bucket, COUNT(*) AS recommendations,
+	                   COALESCE(SUM(r.rating) FILTER (WHERE r.rating > 0), 0) AS rating_sum,
+	                   COUNT(*) FILTER (WHERE r.rating > 0) AS ratings
+	            FROM results r
+	            JOIN users u ON u.id = r.user_id
+	            WHERE u.experiment_id = $1
+	            GROUP BY 1, 2
+	         ),
+	         clicks AS (
+	            SELECT u.group_name, date_trunc($2, COALESCE(r.clicked_at, r.created_at)) AS bucket,
+	                   COUNT(*) AS clicks
+	            FROM results r
+	            JOIN users u ON u.id = r.user_id
+	            WHERE u.experiment_id = $1 AND r.clicked
+	            GROUP BY 1, 2
+	         )
+	         SELECT COALESCE(i.group_name, c.group_name), COALESCE(i.bucket, c.bucket),
+	                COALESCE(i.recommendations, 0), COALESCE(c.clicks, 0),
+	                COALESCE(i.rating_sum, 0), COALESCE(i.ratings, 0)
+	         FROM impressions i
+	         FULL OUTER JOIN clicks c ON c.group_name = i.group_name AND c.bucket = i.bucket
+	         ORDER BY 2, 1`
+
+	rows, err := r.pool.Query(ctx, sql, experimentID, granularity)
+	if err != nil {
+		logger.Error("Ошибка при запросе временного ряда: %v", err)
+		return nil, fmt.Errorf("не удалось получить временной ряд эксперимента: %w", err)
+	}
+	defer rows.Close()
+
+	data := make(map[string]map[time.Time]timeSeriesRow)
+	var first, last time.Time
+	for rows.Next() {
+		var group string
+		var bucket time.Time
+		var row timeSeriesRow
+		if err := rows.Scan(&group, &bucket, &row.recommendations, &row.clicks, &row.ratingSum, &row.ratings); err != nil {
+			logger.Error("Ошибка при сканировании точки временного ряда: %v", err)
+			continue
+		}
+		if data[group] == nil {
+			data[group] = make(map[time.Time]timeSeriesRow)
+		}
+		data[group][bucket] = row
+		if first.IsZero() || bucket.Before(first) {
+			first = bucket
+		}
+		if bucket.After(last) {
+			last = bucket
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка обработки временного ряда: %w", err)
+	}
+
+	series := &models.ExperimentTimeSeries{
+		ExperimentID: experimentID,
+		Granularity:  granularity,
+		Confidence:   confidence,
+		Groups:       make(map[string][]models.TimeSeriesPoint),
+		Lift:         make(map[string][]models.LiftPoint),
+	}
+	if len(data) > 0 {
+		if err := fillTimeSeries(series, data, first, last); err != nil {
+			return nil, err
+		}
+	}
+
+	logger.Info("Временной ряд эксперимента %d: %d интервалов", experimentID, len(series.Buckets))
+	return series, nil
+}
+
+// fillTimeSeries заполняет интервалы series от first до last включительно, точки групп по данным data
+// (интервалы без данных получают нулевые значения) и накопленный прирост CTR тестовых групп
+func fillTimeSeries(series *models.ExperimentTimeSeries, data map[string]map[time.Time]timeSeriesRow, first, last time.Time) error {
+	for bucket := first; !bucket.After(last); bucket = nextBucket(bucket, series.Granularity) {
+		if len(series.Buckets) == maxTimeSeriesBuckets {
+			return fmt.Errorf("слишком много интервалов (больше %d), выберите более крупный шаг", maxTimeSeriesBuckets)
+		}
+		series.Buckets = append(series.Buckets, bucket)
+	}
+
+	for group, byBucket := range data {
+		points := make([]models.TimeSeriesPoint, 0, len(series.Buckets))
+		var cumRecommendations, cumClicks int
+		for _, bucket := range series.Buckets {
+			row := byBucket[bucket]
+			cumRecommendations += row.recommendations
+			cumClicks += row.clicks
+
+			p := models.TimeSeriesPoint{
+				Bucket:                    bucket,
+				Recommendations:           row.recommendations,
+				Clicks:                    row.clicks,
+				Ratings:                   row.ratings,
+				CumulativeRecommendations: cumRecommendations,
+				CumulativeClicks:          cumClicks,
+			}
+			if row.recommendations > 0 {
+				p.CTR = float64(row.clicks) / float64(row.recommendations)
+			}
+			if row.ratings > 0 {
+				p.AvgRating = float64(row.ratingSum) / float64(row.ratings)
+			}
+			if cumRecommendations > 0 {
+				p.CumulativeCTR = float64(cumClicks) / float64(cumRecommendations)
+			}
+			points = append(points, p)
+		}
+		series.Groups[group] = points
+	}
+
+	control, ok := series.Groups[models.ControlGroup]
+	if !ok {
+		return nil
+	}
+	for _, name := range sortedTreatmentGroups(series.Groups) {
+		treatment := series.Groups[name]
+		lift := make([]models.LiftPoint, len(series.Buckets))
+		for i, bucket := range series.Buckets {
+			lift[i].Bucket = bucket
+			c, t := control[i], treatment[i]
+			// накопленные клики могут опережать показы, если время клика раньше времени показа (перенесенные данные)
+			if c.CumulativeClicks > c.CumulativeRecommendations || t.CumulativeClicks > t.CumulativeRecommendations {
+				continue
+			}
+			test, err := stats.TwoProportionZTest(c.CumulativeClicks, c.CumulativeRecommendations,
+				t.CumulativeClicks, t.CumulativeRecommendations, series.Confidence)
+			if err != nil {
+				continue
+			}
+			lift[i] = models.LiftPoint{
+				Bucket:       bucket,
+				RelativeLift: test.RelativeLift,
+				AbsoluteLift: test.AbsoluteLift,
+				CILower:      test.CILower,
+				CIUpper:      test.CIUpper,
+				Defined:      true,
+			}
+		}
+		series.Lift[name] = lift
+	}
+	return nil
+}
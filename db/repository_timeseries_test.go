@@ -0,0 +1,65 @@
+package db
+
+import (
+	"math"
+	"testing"
+	"testing-platform/db/models"
+	"time"
+)
+
+func TestFillTimeSeries(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC) }
+	data := map[string]map[time.Time]timeSeriesRow{
+		"A": {day(1): {recommendations: 100, clicks: 10, ratingSum: 8, ratings: 2}, day(3): {recommendations: 100, clicks: 20}},
+		"B": {day(1): {recommendations: 100, clicks: 15}, day(3): {recommendations: 100, clicks: 25}},
+		// клик перенесенных данных раньше показа: прирост в первом интервале не определен
+		"C": {day(1): {clicks: 3}, day(2): {recommendations: 100, clicks: 10}},
+	}
+	series := &models.ExperimentTimeSeries{
+		Granularity: models.TimeGranularityDay,
+		Confidence:  0.95,
+		Groups:      make(map[string][]models.TimeSeriesPoint),
+		Lift:        make(map[string][]models.LiftPoint),
+	}
+	if err := fillTimeSeries(series, data, day(1), day(3)); err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if len(series.Buckets) != 3 {
+		t.Fatalf("интервалов %d, ожидается 3", len(series.Buckets))
+	}
+
+	control := series.Groups["A"]
+	if control[0].AvgRating != 4 || control[0].CTR != 0.1 {
+		t.Errorf("A, первый день: CTR %g и средняя оценка %g, ожидается 0.1 и 4", control[0].CTR, control[0].AvgRating)
+	}
+	// интервал без событий входит в ряд с нулевыми значениями и прежними накопленными
+	if gap := control[1]; gap.Recommendations != 0 || gap.CumulativeRecommendations != 100 || gap.CumulativeCTR != 0.1 {
+		t.Errorf("A, пропущенный день: %+v", gap)
+	}
+	if last := control[2]; last.CumulativeRecommendations != 200 || last.CumulativeClicks != 30 {
+		t.Errorf("A, последний день: %+v", last)
+	}
+
+	lift := series.Lift["B"]
+	for i := range lift {
+		if !lift[i].Defined || math.Abs(lift[i].AbsoluteLift-0.05) > 1e-12 {
+			t.Errorf("B, интервал %d: прирост %+v, ожидается 0.05", i, lift[i])
+		}
+	}
+	if c := series.Lift["C"]; c[0].Defined || !c[1].Defined {
+		t.Errorf("C: определенность прироста %v, %v, ожидается false, true", c[0].Defined, c[1].Defined)
+	}
+}
+
+func TestFillTimeSeriesTooManyBuckets(t *testing.T) {
+	first := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	series := &models.ExperimentTimeSeries{
+		Granularity: models.TimeGranularityHour,
+		Groups:      make(map[string][]models.TimeSeriesPoint),
+		Lift:        make(map[string][]models.LiftPoint),
+	}
+	data := map[string]map[time.Time]timeSeriesRow{"A": {first: {recommendations: 1}}}
+	if err := fillTimeSeries(series, data, first, first.Add(maxTimeSeriesBuckets*time.Hour)); err == nil {
+		t.Error("ожидается ошибка")
+	}
+}
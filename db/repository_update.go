@@ -0,0 +1,132 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing-platform/db/models"
+	"testing-platform/pkg/logger"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrVersionConflict возвращается UpdateExperiment, если эксперимент изменился после того,
+// как его загрузили для редактирования
+var ErrVersionConflict = errors.New("эксперимент был изменен после загрузки: обновите данные и повторите редактирование")
+
+// UpdateExperiment сохраняет название, теги, процент пользователей и варианты эксперимента.
+// Изменение проходит Experiment.Validate и правила models.ValidateUpdate для текущего статуса;
+// статус, расписание и слой не меняются (для них есть TransitionExperiment и SetExperimentSchedule).
+// exp.Version должна совпадать с версией в БД, иначе возвращается ErrVersionConflict;
+// при успехе exp получает новую версию
+func (r *Repository) UpdateExperiment(ctx context.Context, exp *models.Experiment) error {
+	tx, err := r.pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+	if err := setActor(ctx, tx); err != nil {
+		return err
+	}
+
+	// строка эксперимента блокируется, чтобы проверка версии и правил не устарела до записи
+	var current models.Experiment
+	err = tx.QueryRow(ctx, `SELECT id, user_percent, COALESCE(layer_id, 0), COALESCE(layer_range_start, 0),
+	                               COALESCE(layer_range_end, 0), status, planned_start, planned_end, version
+	                        FROM experiments WHERE id = $1 FOR UPDATE`, exp.ID).Scan(&current.ID, &current.UserPercent,
+		&current.LayerID, &current.LayerStart, &current.LayerEnd, &current.Status,
+		&current.PlannedStart, &current.PlannedEnd, &current.Version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("эксперимент с ID %d не найден", exp.ID)
+	}
+	if err != nil {
+		logger.Error("Ошибка при получении эксперимента %d для изменения: %v", exp.ID, err)
+		return fmt.Errorf("не удалось получить эксперимент: %w", err)
+	}
+	if current.Version != exp.Version {
+		logger.Warn("Конфликт версий эксперимента %d: загружена %d, в БД %d", exp.ID, exp.Version, current.Version)
+		return ErrVersionConflict
+	}
+	// варианты читаются в той же транзакции после блокировки, чтобы текущее состояние было согласованным
+	stored, err := getVariantsByExperiment(ctx, tx, []int{exp.ID})
+	if err != nil {
+		return err
+	}
+	current.Variants = stored[exp.ID]
+
+	exp.Status, exp.PlannedStart, exp.PlannedEnd = current.Status, current.PlannedStart, current.PlannedEnd
+	exp.LayerID, exp.LayerStart, exp.LayerEnd = current.LayerID, current.LayerStart, current.LayerEnd
+	if err := exp.Validate(); err != nil {
+		return err
+	}
+
+	var hasUsers bool
+	err = tx.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM users WHERE experiment_id = $1)`, exp.ID).Scan(&hasUsers)
+	if err != nil {
+		return fmt.Errorf("не удалось проверить пользователей эксперимента: %w", err)
+	}
+	if err := models.ValidateUpdate(&current, exp, hasUsers); err != nil {
+		return err
+	}
+
+	logger.Info("Выполнение DML: изменение эксперимента %d '%s' (версия %d)", exp.ID, exp.Name, exp.Version)
+
+	variants := append([]models.Variant(nil), exp.VariantList()...)
+	exp.AlgorithmA, exp.AlgorithmB = variants[0].Algorithm, variants[1].Algorithm
+	variantsChanged := !models.SameVariants(current.Variants, variants)
+	if variantsChanged {
+		if err := checkAlgorithms(ctx, tx, variants); err != nil {
+			return err
+		}
+	}
+
+	// эксперимент слоя получает диапазон новой ширины вместо прежнего
+	var layerStart, layerEnd *float64
+	if exp.LayerID != 0 {
+		if exp.UserPercent != current.UserPercent {
+			start, err := allocateLayerRange(ctx, tx, exp.LayerID, exp.UserPercent, exp.ID)
+			if err != nil {
+				return err
+			}
+			exp.LayerStart, exp.LayerEnd = start, start+exp.UserPercent
+		}
+		layerStart, layerEnd = &exp.LayerStart, &exp.LayerEnd
+	}
+
+	// версию увеличивает триггер при изменении настроек в строке эксперимента; замена одних вариантов
+	// строку не меняет, поэтому тогда версия увеличивается явно
+	sql := `UPDATE experiments
+	         SET name = $1, algorithm_a = $2, algorithm_b = $3, user_percent = $4, tags = $5,
+	             layer_range_start = $6, layer_range_end = $7,
+	             version = CASE WHEN $10 THEN version + 1 ELSE version END
+	         WHERE id = $8 AND version = $9
+	         RETURNING version`
+	err = tx.QueryRow(ctx, sql, exp.Name, exp.AlgorithmA, exp.AlgorithmB, exp.UserPercent, exp.Tags,
+		layerStart, layerEnd, exp.ID, exp.Version, variantsChanged).Scan(&exp.Version)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return ErrVersionConflict
+	}
+	if err != nil {
+		logger.Error("Ошибка при изменении эксперимента: %v", err)
+		return fmt.Errorf("не удалось изменить эксперимент: %w", err)
+	}
+
+	// варианты черновика пересоздаются целиком: пользователей в них еще нет
+	if variantsChanged {
+		if _, err := tx.Exec(ctx, `DELETE FROM variants WHERE experiment_id = $1`, exp.ID); err != nil {
+			logger.Error("Ошибка при удалении вариантов эксперимента %d: %v", exp.ID, err)
+			return fmt.Errorf("не удалось заменить варианты эксперимента: %w", err)
+		}
+		if err := insertVariants(ctx, tx, exp.ID, variants); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
+	exp.Variants = variants
+
+	logger.Info("Эксперимент %d изменен, новая версия %d", exp.ID, exp.Version)
+	return nil
+}
@@ -0,0 +1,92 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"testing-platform/db/models"
+	"testing-platform/pkg/logger"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// insertVariants сохраняет варианты нового эксперимента в рамках транзакции
+func insertVariants(ctx context.Context, tx pgx.Tx, experimentID int, variants []models.Variant) error {
+	sql := `INSERT INTO variants (experiment_id, name, algorithm, position, weight, params)
+	         VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`
+
+	for i := range variants {
+		v := &variants[i]
+		v.ExperimentID = experimentID
+		v.Position = i
+		if v.Params == nil {
+			v.Params = map[string]any{}
+		}
+		if err := tx.QueryRow(ctx, sql, experimentID, v.Name, v.Algorithm, v.Position, v.Weight, v.Params).Scan(&v.ID); err != nil {
+			logger.Error("Ошибка при сохранении варианта %s: %v", v.Name, err)
+			return fmt.Errorf("не удалось сохранить вариант %s: %w", v.Name, err)
+		}
+	}
+	return nil
+}
+
+// GetVariants возвращает варианты эксперимента в порядке их позиций
+func (r *Repository) GetVariants(ctx context.Context, experimentID int) ([]models.Variant, error) {
+	variants, err := getVariantsByExperiment(ctx, r.pool, []int{experimentID})
+	if err != nil {
+		return nil, err
+	}
+	return variants[experimentID], nil
+}
+
+// querier выполняет запрос через пул соединений или внутри транзакции
+type querier interface {
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+}
+
+// getVariantsByExperiment возвращает варианты нескольких экспериментов одним запросом
+func getVariantsByExperiment(ctx context.Context, q querier, experimentIDs []int) (map[int][]models.Variant, error) {
+	sql := `SELECT id, experiment_id, name, algorithm::text, position, weight::float8, params
+	         FROM variants
+	         WHERE experiment_id = ANY($1)
+	         ORDER BY experiment_id, position`
+
+	rows, err := q.Query(ctx, sql, experimentIDs)
+	if err != nil {
+		logger.Error("Ошибка при запросе вариантов экспериментов: %v", err)
+		return nil, fmt.Errorf("не удалось получить варианты экспериментов: %w", err)
+	}
+	defer rows.Close()
+
+	variants := make(map[int][]models.Variant)
+	for rows.Next() {
+		var v models.Variant
+		if err := rows.Scan(&v.ID, &v.ExperimentID, &v.Name, &v.Algorithm, &v.Position, &v.Weight, &v.Params); err != nil {
+			logger.Error("Ошибка при сканировании варианта: %v", err)
+			continue
+		}
+		variants[v.ExperimentID] = append(variants[v.ExperimentID], v)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("ошибка обработки вариантов: %w", err)
+	}
+	return variants, nil
+}
+
+// attachVariants заполняет варианты у списка экспериментов
+func (r *Repository) attachVariants(ctx context.Context, experiments []models.Experiment) error {
+	if len(experiments) == 0 {
+		return nil
+	}
+	ids := make([]int, len(experiments))
+	for i, exp := range experiments {
+		ids[i] = exp.ID
+	}
+	variants, err := getVariantsByExperiment(ctx, r.pool, ids)
+	if err != nil {
+		return err
+	}
+	for i := range experiments {
+		experiments[i].Variants = variants[experiments[i].ID]
+	}
+	return nil
+}
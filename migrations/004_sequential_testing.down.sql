@@ -0,0 +1 @@
+DROP TABLE IF EXISTS sequential_looks;
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS sequential_looks (
+    id SERIAL PRIMARY KEY,
+    experiment_id INTEGER NOT NULL REFERENCES experiments(id) ON DELETE CASCADE,
+    treatment_group VARCHAR(10) NOT NULL,
+    look_number INTEGER NOT NULL CHECK (look_number > 0),
+    looked_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    control_recommendations INTEGER NOT NULL,
+    control_clicks INTEGER NOT NULL,
+    treatment_recommendations INTEGER NOT NULL,
+    treatment_clicks INTEGER NOT NULL,
+    estimate DOUBLE PRECISION NOT NULL,
+    z_score DOUBLE PRECISION NOT NULL,
+    likelihood_ratio DOUBLE PRECISION NOT NULL,
+    z_boundary DOUBLE PRECISION NOT NULL,
+    p_value DOUBLE PRECISION NOT NULL,
+    alpha DOUBLE PRECISION NOT NULL,
+    decision VARCHAR(20) NOT NULL,
+    UNIQUE (experiment_id, treatment_group, look_number)
+);
@@ -0,0 +1,16 @@
+ALTER TABLE users
+ADD COLUMN IF NOT EXISTS assigned_at TIMESTAMP;
+
+-- для уже назначенных пользователей временем назначения считается начало эксперимента
+UPDATE users u
+SET assigned_at = e.start_date
+FROM experiments e
+WHERE u.experiment_id = e.id AND u.assigned_at IS NULL;
+
+UPDATE users
+SET assigned_at = CURRENT_TIMESTAMP
+WHERE assigned_at IS NULL;
+
+ALTER TABLE users
+ALTER COLUMN assigned_at SET DEFAULT CURRENT_TIMESTAMP,
+ALTER COLUMN assigned_at SET NOT NULL;
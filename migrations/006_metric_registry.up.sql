@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS metrics (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    title VARCHAR(255) NOT NULL,
+    metric_type VARCHAR(20) NOT NULL CHECK (metric_type IN ('proportion', 'mean', 'ratio')),
+    numerator_sql TEXT NOT NULL,
+    denominator_sql TEXT NOT NULL DEFAULT '',
+    higher_is_better BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO metrics (name, title, metric_type, numerator_sql, denominator_sql, higher_is_better) VALUES
+    ('ctr', 'CTR', 'proportion', 'COUNT(r.id) FILTER (WHERE r.clicked)', 'COUNT(r.id)', true),
+    ('rated_share', 'Доля оцененных рекомендаций', 'proportion', 'COUNT(r.id) FILTER (WHERE r.rating > 0)', 'COUNT(r.id)', true),
+    ('clicks_per_user', 'Клики на пользователя', 'mean', 'COUNT(r.id) FILTER (WHERE r.clicked)', '', true),
+    ('avg_rating', 'Средняя оценка', 'ratio', 'COALESCE(SUM(r.rating) FILTER (WHERE r.rating > 0), 0)', 'COUNT(r.id) FILTER (WHERE r.rating > 0)', true)
+ON CONFLICT (name) DO NOTHING;
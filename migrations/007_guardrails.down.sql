@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS guardrail_evaluations;
+DROP TABLE IF EXISTS guardrails;
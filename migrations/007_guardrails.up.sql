@@ -0,0 +1,31 @@
+CREATE TABLE IF NOT EXISTS guardrails (
+    id SERIAL PRIMARY KEY,
+    experiment_id INTEGER NOT NULL REFERENCES experiments(id) ON DELETE CASCADE,
+    metric_id INTEGER NOT NULL REFERENCES metrics(id) ON DELETE CASCADE,
+    max_degradation DOUBLE PRECISION NOT NULL CHECK (max_degradation >= 0),
+    alpha DOUBLE PRECISION NOT NULL DEFAULT 0.05 CHECK (alpha > 0 AND alpha < 1),
+    is_enabled BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (experiment_id, metric_id)
+);
+
+CREATE TABLE IF NOT EXISTS guardrail_evaluations (
+    id SERIAL PRIMARY KEY,
+    guardrail_id INTEGER REFERENCES guardrails(id) ON DELETE SET NULL,
+    experiment_id INTEGER NOT NULL REFERENCES experiments(id) ON DELETE CASCADE,
+    metric_name VARCHAR(100) NOT NULL,
+    treatment_group VARCHAR(10) NOT NULL,
+    evaluated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    max_degradation DOUBLE PRECISION NOT NULL,
+    control_value DOUBLE PRECISION NOT NULL,
+    treatment_value DOUBLE PRECISION NOT NULL,
+    degradation DOUBLE PRECISION NOT NULL,
+    standard_error DOUBLE PRECISION NOT NULL,
+    p_value DOUBLE PRECISION NOT NULL,
+    alpha DOUBLE PRECISION NOT NULL,
+    breached BOOLEAN NOT NULL,
+    experiment_stopped BOOLEAN NOT NULL DEFAULT false
+);
+
+CREATE INDEX IF NOT EXISTS idx_guardrail_evaluations_experiment
+    ON guardrail_evaluations (experiment_id, evaluated_at);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_results_user_created_at;
+
+ALTER TABLE results
+DROP COLUMN IF EXISTS created_at;
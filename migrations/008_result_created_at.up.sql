@@ -0,0 +1,19 @@
+ALTER TABLE results
+ADD COLUMN IF NOT EXISTS created_at TIMESTAMP;
+
+-- для существующих результатов время показа неизвестно: берется время клика,
+-- а для показов без клика — время назначения пользователя в эксперимент
+UPDATE results r
+SET created_at = COALESCE(r.clicked_at, u.assigned_at)
+FROM users u
+WHERE r.user_id = u.id AND r.created_at IS NULL;
+
+UPDATE results
+SET created_at = COALESCE(clicked_at, CURRENT_TIMESTAMP)
+WHERE created_at IS NULL;
+
+ALTER TABLE results
+ALTER COLUMN created_at SET DEFAULT CURRENT_TIMESTAMP,
+ALTER COLUMN created_at SET NOT NULL;
+
+CREATE INDEX IF NOT EXISTS idx_results_user_created_at ON results (user_id, created_at);
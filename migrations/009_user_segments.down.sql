@@ -0,0 +1,4 @@
+ALTER TABLE users
+DROP COLUMN IF EXISTS cohort,
+DROP COLUMN IF EXISTS country,
+DROP COLUMN IF EXISTS device;
@@ -0,0 +1,4 @@
+ALTER TABLE users
+ADD COLUMN IF NOT EXISTS device VARCHAR(100),
+ADD COLUMN IF NOT EXISTS country VARCHAR(100),
+ADD COLUMN IF NOT EXISTS cohort VARCHAR(100);
@@ -0,0 +1,9 @@
+ALTER TABLE users DROP CONSTRAINT IF EXISTS fk_users_variants;
+
+-- пользователи дополнительных вариантов не укладываются в схему A/B
+DELETE FROM users WHERE group_name NOT IN ('A', 'B');
+
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_group_name_check;
+ALTER TABLE users ADD CONSTRAINT users_group_name_check CHECK (group_name IN ('A', 'B'));
+
+DROP TABLE IF EXISTS variants;
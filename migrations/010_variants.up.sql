@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS variants (
+    id SERIAL PRIMARY KEY,
+    experiment_id INTEGER NOT NULL REFERENCES experiments(id) ON DELETE CASCADE,
+    name VARCHAR(10) NOT NULL,
+    algorithm algorithm_type NOT NULL,
+    position INTEGER NOT NULL CHECK (position >= 0),
+    UNIQUE(experiment_id, name),
+    UNIQUE(experiment_id, position)
+);
+
+-- существующие эксперименты A/B получают по два варианта
+INSERT INTO variants (experiment_id, name, algorithm, position)
+SELECT id, 'A', algorithm_a, 0 FROM experiments
+UNION ALL
+SELECT id, 'B', algorithm_b, 1 FROM experiments
+ON CONFLICT DO NOTHING;
+
+-- группа пользователя должна быть вариантом его эксперимента
+ALTER TABLE users DROP CONSTRAINT IF EXISTS users_group_name_check;
+ALTER TABLE users DROP CONSTRAINT IF EXISTS fk_users_variants;
+ALTER TABLE users
+ADD CONSTRAINT fk_users_variants
+FOREIGN KEY (experiment_id, group_name) REFERENCES variants(experiment_id, name) ON UPDATE CASCADE;
@@ -0,0 +1,4 @@
+ALTER TABLE variants DROP CONSTRAINT IF EXISTS variants_weight_check;
+
+ALTER TABLE variants
+DROP COLUMN IF EXISTS weight;
@@ -0,0 +1,18 @@
+ALTER TABLE variants
+ADD COLUMN IF NOT EXISTS weight NUMERIC(5, 2);
+
+-- существующие эксперименты делят трафик поровну; остаток от округления достается контрольной группе
+WITH counts AS (
+    SELECT experiment_id, COUNT(*) AS n FROM variants GROUP BY experiment_id
+)
+UPDATE variants v
+SET weight = ROUND(100.0 / c.n, 2)
+    + CASE WHEN v.position = 0 THEN 100 - c.n * ROUND(100.0 / c.n, 2) ELSE 0 END
+FROM counts c
+WHERE v.experiment_id = c.experiment_id AND v.weight IS NULL;
+
+ALTER TABLE variants
+ALTER COLUMN weight SET NOT NULL;
+
+ALTER TABLE variants DROP CONSTRAINT IF EXISTS variants_weight_check;
+ALTER TABLE variants ADD CONSTRAINT variants_weight_check CHECK (weight > 0 AND weight <= 100);
@@ -0,0 +1,2 @@
+ALTER TABLE experiments
+DROP COLUMN IF EXISTS salt;
@@ -0,0 +1,3 @@
+-- соль для детерминированного распределения пользователей; у каждого эксперимента своя
+ALTER TABLE experiments
+ADD COLUMN IF NOT EXISTS salt VARCHAR(64) NOT NULL DEFAULT md5(random()::text || clock_timestamp()::text);
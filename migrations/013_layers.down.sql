@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_experiments_layer;
+
+ALTER TABLE experiments DROP CONSTRAINT IF EXISTS experiments_layer_range_check;
+
+ALTER TABLE experiments
+DROP COLUMN IF EXISTS layer_range_end,
+DROP COLUMN IF EXISTS layer_range_start,
+DROP COLUMN IF EXISTS layer_id;
+
+DROP TABLE IF EXISTS layers;
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS layers (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    description TEXT NOT NULL DEFAULT '',
+    -- общая соль слоя: хеш пользователя по ней определяет, в диапазон какого эксперимента он попадает
+    salt VARCHAR(64) NOT NULL DEFAULT md5(random()::text || clock_timestamp()::text),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- эксперимент слоя занимает диапазон [layer_range_start, layer_range_end) процентов трафика слоя;
+-- диапазоны экспериментов одного слоя не пересекаются (проверяется при создании эксперимента)
+ALTER TABLE experiments
+ADD COLUMN IF NOT EXISTS layer_id INTEGER REFERENCES layers(id),
+ADD COLUMN IF NOT EXISTS layer_range_start NUMERIC(5, 2),
+ADD COLUMN IF NOT EXISTS layer_range_end NUMERIC(5, 2);
+
+ALTER TABLE experiments DROP CONSTRAINT IF EXISTS experiments_layer_range_check;
+ALTER TABLE experiments ADD CONSTRAINT experiments_layer_range_check CHECK (
+    (layer_id IS NULL AND layer_range_start IS NULL AND layer_range_end IS NULL)
+    OR (layer_id IS NOT NULL AND layer_range_start >= 0 AND layer_range_start < layer_range_end AND layer_range_end <= 100)
+);
+
+CREATE INDEX IF NOT EXISTS idx_experiments_layer ON experiments(layer_id);
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS experiment_status_history;
+
+DROP INDEX IF EXISTS idx_experiments_status;
+
+ALTER TABLE experiments DROP CONSTRAINT IF EXISTS experiments_status_check;
+
+ALTER TABLE experiments
+DROP COLUMN IF EXISTS status_changed_at,
+DROP COLUMN IF EXISTS status;
@@ -0,0 +1,40 @@
+-- жизненный цикл эксперимента: черновик, запланирован, запущен, на паузе, остановлен, завершен, в архиве;
+-- is_active сохраняется для совместимости и равен status = 'running'
+ALTER TABLE experiments
+ADD COLUMN IF NOT EXISTS status VARCHAR(20),
+ADD COLUMN IF NOT EXISTS status_changed_at TIMESTAMP;
+
+UPDATE experiments
+SET status = CASE WHEN is_active THEN 'running' ELSE 'stopped' END,
+    status_changed_at = COALESCE(start_date, CURRENT_TIMESTAMP)
+WHERE status IS NULL;
+
+ALTER TABLE experiments
+ALTER COLUMN status SET DEFAULT 'draft',
+ALTER COLUMN status SET NOT NULL,
+ALTER COLUMN status_changed_at SET DEFAULT CURRENT_TIMESTAMP,
+ALTER COLUMN status_changed_at SET NOT NULL;
+
+ALTER TABLE experiments DROP CONSTRAINT IF EXISTS experiments_status_check;
+ALTER TABLE experiments ADD CONSTRAINT experiments_status_check
+CHECK (status IN ('draft', 'scheduled', 'running', 'paused', 'stopped', 'concluded', 'archived'));
+
+CREATE INDEX IF NOT EXISTS idx_experiments_status ON experiments(status);
+
+-- история смен статуса; from_status пуст для начального статуса при создании
+CREATE TABLE IF NOT EXISTS experiment_status_history (
+    id SERIAL PRIMARY KEY,
+    experiment_id INTEGER NOT NULL REFERENCES experiments(id) ON DELETE CASCADE,
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    reason TEXT NOT NULL,
+    changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_experiment_status_history_experiment
+    ON experiment_status_history (experiment_id, changed_at);
+
+INSERT INTO experiment_status_history (experiment_id, from_status, to_status, reason, changed_at)
+SELECT e.id, NULL, e.status, 'перенос статуса из is_active', e.status_changed_at
+FROM experiments e
+WHERE NOT EXISTS (SELECT 1 FROM experiment_status_history h WHERE h.experiment_id = e.id);
@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_experiments_planned_end;
+DROP INDEX IF EXISTS idx_experiments_planned_start;
+
+ALTER TABLE experiments DROP CONSTRAINT IF EXISTS experiments_planned_range_check;
+
+ALTER TABLE experiments
+DROP COLUMN IF EXISTS planned_end,
+DROP COLUMN IF EXISTS planned_start;
@@ -0,0 +1,12 @@
+-- плановые даты запуска и завершения: планировщик приложения запускает запланированные
+-- эксперименты в planned_start и завершает запущенные и приостановленные в planned_end
+ALTER TABLE experiments
+ADD COLUMN IF NOT EXISTS planned_start TIMESTAMP,
+ADD COLUMN IF NOT EXISTS planned_end TIMESTAMP;
+
+ALTER TABLE experiments DROP CONSTRAINT IF EXISTS experiments_planned_range_check;
+ALTER TABLE experiments ADD CONSTRAINT experiments_planned_range_check
+CHECK (planned_start IS NULL OR planned_end IS NULL OR planned_end > planned_start);
+
+CREATE INDEX IF NOT EXISTS idx_experiments_planned_start ON experiments(planned_start) WHERE status = 'scheduled';
+CREATE INDEX IF NOT EXISTS idx_experiments_planned_end ON experiments(planned_end) WHERE status IN ('running', 'paused');
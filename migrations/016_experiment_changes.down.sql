@@ -0,0 +1,8 @@
+DROP TRIGGER IF EXISTS trg_variants_changes ON variants;
+DROP TRIGGER IF EXISTS trg_experiments_changes ON experiments;
+DROP TRIGGER IF EXISTS trg_experiment_changes_append_only ON experiment_changes;
+
+DROP FUNCTION IF EXISTS log_experiment_change();
+DROP FUNCTION IF EXISTS forbid_experiment_changes_edit();
+
+DROP TABLE IF EXISTS experiment_changes;
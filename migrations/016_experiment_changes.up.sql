@@ -0,0 +1,76 @@
+-- журнал изменений конфигурации экспериментов и их вариантов: только добавление,
+-- снимки строки до и после изменения в JSONB; заполняется триггерами
+CREATE TABLE IF NOT EXISTS experiment_changes (
+    id BIGSERIAL PRIMARY KEY,
+    -- без внешнего ключа: история сохраняется и после удаления эксперимента
+    experiment_id INTEGER NOT NULL,
+    entity VARCHAR(20) NOT NULL CHECK (entity IN ('experiment', 'variant')),
+    operation VARCHAR(10) NOT NULL CHECK (operation IN ('INSERT', 'UPDATE', 'DELETE')),
+    -- автор изменения: параметр сеанса testing_platform.actor или роль БД
+    changed_by VARCHAR(255) NOT NULL,
+    changed_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    old_data JSONB,
+    new_data JSONB
+);
+
+CREATE INDEX IF NOT EXISTS idx_experiment_changes_experiment
+    ON experiment_changes (experiment_id, changed_at);
+
+CREATE OR REPLACE FUNCTION log_experiment_change() RETURNS TRIGGER AS $$
+DECLARE
+    old_row JSONB;
+    new_row JSONB;
+    exp_id INTEGER;
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        old_row := to_jsonb(OLD);
+    END IF;
+    IF TG_OP <> 'DELETE' THEN
+        new_row := to_jsonb(NEW);
+    END IF;
+    -- UPDATE без фактических изменений не записывается
+    IF TG_OP = 'UPDATE' AND old_row = new_row THEN
+        RETURN NULL;
+    END IF;
+
+    IF TG_ARGV[0] = 'experiment' THEN
+        exp_id := COALESCE(new_row, old_row)->>'id';
+    ELSE
+        exp_id := COALESCE(new_row, old_row)->>'experiment_id';
+    END IF;
+
+    INSERT INTO experiment_changes (experiment_id, entity, operation, changed_by, old_data, new_data)
+    VALUES (exp_id, TG_ARGV[0], TG_OP,
+            COALESCE(NULLIF(current_setting('testing_platform.actor', true), ''), current_user),
+            old_row, new_row);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_experiments_changes ON experiments;
+CREATE TRIGGER trg_experiments_changes
+AFTER INSERT OR UPDATE OR DELETE ON experiments
+FOR EACH ROW EXECUTE FUNCTION log_experiment_change('experiment');
+
+DROP TRIGGER IF EXISTS trg_variants_changes ON variants;
+CREATE TRIGGER trg_variants_changes
+AFTER INSERT OR UPDATE OR DELETE ON variants
+FOR EACH ROW EXECUTE FUNCTION log_experiment_change('variant');
+
+-- журнал нельзя исправить задним числом
+CREATE OR REPLACE FUNCTION forbid_experiment_changes_edit() RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'журнал изменений экспериментов доступен только для добавления';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_experiment_changes_append_only ON experiment_changes;
+CREATE TRIGGER trg_experiment_changes_append_only
+BEFORE UPDATE OR DELETE ON experiment_changes
+FOR EACH ROW EXECUTE FUNCTION forbid_experiment_changes_edit();
+
+-- текущее состояние существующих экспериментов становится первой записью истории
+INSERT INTO experiment_changes (experiment_id, entity, operation, changed_by, new_data)
+SELECT e.id, 'experiment', 'INSERT', 'migration', to_jsonb(e)
+FROM experiments e
+WHERE NOT EXISTS (SELECT 1 FROM experiment_changes c WHERE c.experiment_id = e.id);
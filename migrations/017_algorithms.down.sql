@@ -0,0 +1,20 @@
+-- откат возможен, только если эксперименты используют исходные четыре алгоритма
+DO $$
+BEGIN
+    CREATE TYPE algorithm_type AS ENUM ('collaborative', 'content_based', 'hybrid', 'popularity_based');
+EXCEPTION
+    WHEN duplicate_object THEN NULL;
+END $$;
+
+ALTER TABLE variants DROP CONSTRAINT IF EXISTS fk_variants_algorithm;
+ALTER TABLE experiments DROP CONSTRAINT IF EXISTS fk_experiments_algorithm_b;
+ALTER TABLE experiments DROP CONSTRAINT IF EXISTS fk_experiments_algorithm_a;
+
+ALTER TABLE variants
+ALTER COLUMN algorithm TYPE algorithm_type USING algorithm::algorithm_type;
+
+ALTER TABLE experiments
+ALTER COLUMN algorithm_a TYPE algorithm_type USING algorithm_a::algorithm_type,
+ALTER COLUMN algorithm_b TYPE algorithm_type USING algorithm_b::algorithm_type;
+
+DROP TABLE IF EXISTS algorithms;
@@ -0,0 +1,37 @@
+-- реестр алгоритмов рекомендаций вместо перечисления algorithm_type:
+-- новый алгоритм добавляется строкой в таблицу без миграции
+CREATE TABLE IF NOT EXISTS algorithms (
+    name VARCHAR(50) PRIMARY KEY CHECK (name ~ '^[a-z][a-z0-9_]*$'),
+    description TEXT NOT NULL DEFAULT '',
+    owner VARCHAR(100) NOT NULL DEFAULT '',
+    version VARCHAR(50) NOT NULL DEFAULT '1',
+    -- устаревший алгоритм остается у существующих экспериментов, но не назначается новым
+    deprecated BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO algorithms (name, description) VALUES
+    ('collaborative', 'Коллаборативная фильтрация'),
+    ('content_based', 'Рекомендации по содержанию'),
+    ('hybrid', 'Гибридный алгоритм'),
+    ('popularity_based', 'Рекомендации по популярности')
+ON CONFLICT (name) DO NOTHING;
+
+ALTER TABLE experiments
+ALTER COLUMN algorithm_a TYPE VARCHAR(50) USING algorithm_a::text,
+ALTER COLUMN algorithm_b TYPE VARCHAR(50) USING algorithm_b::text;
+
+ALTER TABLE variants
+ALTER COLUMN algorithm TYPE VARCHAR(50) USING algorithm::text;
+
+ALTER TABLE experiments DROP CONSTRAINT IF EXISTS fk_experiments_algorithm_a;
+ALTER TABLE experiments DROP CONSTRAINT IF EXISTS fk_experiments_algorithm_b;
+ALTER TABLE experiments
+ADD CONSTRAINT fk_experiments_algorithm_a FOREIGN KEY (algorithm_a) REFERENCES algorithms(name) ON UPDATE CASCADE,
+ADD CONSTRAINT fk_experiments_algorithm_b FOREIGN KEY (algorithm_b) REFERENCES algorithms(name) ON UPDATE CASCADE;
+
+ALTER TABLE variants DROP CONSTRAINT IF EXISTS fk_variants_algorithm;
+ALTER TABLE variants
+ADD CONSTRAINT fk_variants_algorithm FOREIGN KEY (algorithm) REFERENCES algorithms(name) ON UPDATE CASCADE;
+
+DROP TYPE IF EXISTS algorithm_type;
@@ -0,0 +1,5 @@
+ALTER TABLE algorithms DROP CONSTRAINT IF EXISTS algorithms_params_schema_check;
+ALTER TABLE algorithms DROP COLUMN IF EXISTS params_schema;
+
+ALTER TABLE variants DROP CONSTRAINT IF EXISTS variants_params_check;
+ALTER TABLE variants DROP COLUMN IF EXISTS params;
@@ -0,0 +1,26 @@
+-- параметры алгоритма варианта: один алгоритм можно сравнивать сам с собой
+-- при разных гиперпараметрах, например hybrid с весом 0.3 и 0.7
+ALTER TABLE variants
+ADD COLUMN IF NOT EXISTS params JSONB NOT NULL DEFAULT '{}';
+
+ALTER TABLE variants DROP CONSTRAINT IF EXISTS variants_params_check;
+ALTER TABLE variants
+ADD CONSTRAINT variants_params_check CHECK (jsonb_typeof(params) = 'object');
+
+-- JSON-схема параметров алгоритма; пустая схема допускает любые параметры
+ALTER TABLE algorithms
+ADD COLUMN IF NOT EXISTS params_schema JSONB NOT NULL DEFAULT '{}';
+
+ALTER TABLE algorithms DROP CONSTRAINT IF EXISTS algorithms_params_schema_check;
+ALTER TABLE algorithms
+ADD CONSTRAINT algorithms_params_schema_check CHECK (jsonb_typeof(params_schema) = 'object');
+
+UPDATE algorithms
+SET params_schema = '{
+    "type": "object",
+    "properties": {
+        "weight": {"type": "number", "minimum": 0, "maximum": 1, "description": "доля коллаборативной составляющей"}
+    },
+    "additionalProperties": false
+}'
+WHERE name = 'hybrid' AND params_schema = '{}';
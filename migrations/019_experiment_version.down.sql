@@ -0,0 +1,4 @@
+DROP TRIGGER IF EXISTS trg_experiments_version ON experiments;
+DROP FUNCTION IF EXISTS bump_experiment_version();
+
+ALTER TABLE experiments DROP COLUMN IF EXISTS version;
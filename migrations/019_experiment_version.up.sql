@@ -0,0 +1,18 @@
+-- версия строки эксперимента для оптимистичной блокировки при редактировании:
+-- увеличивается при любом фактическом изменении строки, в том числе при смене статуса и расписания
+ALTER TABLE experiments
+ADD COLUMN IF NOT EXISTS version INTEGER NOT NULL DEFAULT 1;
+
+CREATE OR REPLACE FUNCTION bump_experiment_version() RETURNS TRIGGER AS $$
+BEGIN
+    IF NEW IS DISTINCT FROM OLD THEN
+        NEW.version := OLD.version + 1;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_experiments_version ON experiments;
+CREATE TRIGGER trg_experiments_version
+BEFORE UPDATE ON experiments
+FOR EACH ROW EXECUTE FUNCTION bump_experiment_version();
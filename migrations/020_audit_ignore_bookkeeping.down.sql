@@ -0,0 +1,30 @@
+CREATE OR REPLACE FUNCTION log_experiment_change() RETURNS TRIGGER AS $$
+DECLARE
+    old_row JSONB;
+    new_row JSONB;
+    exp_id INTEGER;
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        old_row := to_jsonb(OLD);
+    END IF;
+    IF TG_OP <> 'DELETE' THEN
+        new_row := to_jsonb(NEW);
+    END IF;
+    -- UPDATE без фактических изменений не записывается
+    IF TG_OP = 'UPDATE' AND old_row = new_row THEN
+        RETURN NULL;
+    END IF;
+
+    IF TG_ARGV[0] = 'experiment' THEN
+        exp_id := COALESCE(new_row, old_row)->>'id';
+    ELSE
+        exp_id := COALESCE(new_row, old_row)->>'experiment_id';
+    END IF;
+
+    INSERT INTO experiment_changes (experiment_id, entity, operation, changed_by, old_data, new_data)
+    VALUES (exp_id, TG_ARGV[0], TG_OP,
+            COALESCE(NULLIF(current_setting('testing_platform.actor', true), ''), current_user),
+            old_row, new_row);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
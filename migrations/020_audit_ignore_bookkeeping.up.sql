@@ -0,0 +1,35 @@
+-- журнал изменений хранит только конфигурацию эксперимента: служебные и производные поля
+-- (версия строки, время смены статуса из experiment_status_history)
+-- убираются из снимков, а UPDATE, изменивший только их, не записывается
+CREATE OR REPLACE FUNCTION log_experiment_change() RETURNS TRIGGER AS $$
+DECLARE
+    old_row JSONB;
+    new_row JSONB;
+    exp_id INTEGER;
+    -- ключи, не попадающие в журнал
+    ignored TEXT[] := ARRAY['version', 'status_changed_at'];
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        old_row := to_jsonb(OLD) - ignored;
+    END IF;
+    IF TG_OP <> 'DELETE' THEN
+        new_row := to_jsonb(NEW) - ignored;
+    END IF;
+    -- UPDATE без изменений конфигурации не записывается
+    IF TG_OP = 'UPDATE' AND old_row = new_row THEN
+        RETURN NULL;
+    END IF;
+
+    IF TG_ARGV[0] = 'experiment' THEN
+        exp_id := COALESCE(new_row, old_row)->>'id';
+    ELSE
+        exp_id := COALESCE(new_row, old_row)->>'experiment_id';
+    END IF;
+
+    INSERT INTO experiment_changes (experiment_id, entity, operation, changed_by, old_data, new_data)
+    VALUES (exp_id, TG_ARGV[0], TG_OP,
+            COALESCE(NULLIF(current_setting('testing_platform.actor', true), ''), current_user),
+            old_row, new_row);
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
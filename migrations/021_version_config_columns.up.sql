@@ -0,0 +1,17 @@
+-- версия эксперимента увеличивается только при изменении настроек, которые видит форма редактирования:
+-- служебные записи (мониторинг ограничителей, планировщик) не должны вызывать ложный конфликт
+-- версий у открытой формы. Статус в сравнение не входит: его меняют планировщик
+-- и остановка по ограничителям, а UpdateExperiment перечитывает статус под блокировкой строки
+-- и проверяет изменение по правилам models.ValidateUpdate для текущего статуса
+CREATE OR REPLACE FUNCTION bump_experiment_version() RETURNS TRIGGER AS $$
+BEGIN
+    IF ROW(NEW.name, NEW.algorithm_a, NEW.algorithm_b, NEW.user_percent, NEW.tags,
+           NEW.planned_start, NEW.planned_end, NEW.layer_id, NEW.layer_range_start, NEW.layer_range_end)
+       IS DISTINCT FROM
+       ROW(OLD.name, OLD.algorithm_a, OLD.algorithm_b, OLD.user_percent, OLD.tags,
+           OLD.planned_start, OLD.planned_end, OLD.layer_id, OLD.layer_range_start, OLD.layer_range_end) THEN
+        NEW.version := OLD.version + 1;
+    END IF;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
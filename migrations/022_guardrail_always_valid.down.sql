@@ -0,0 +1,2 @@
+ALTER TABLE guardrail_evaluations
+DROP COLUMN IF EXISTS always_valid;
@@ -0,0 +1,4 @@
+-- p-значение проверки ограничительной метрики стало всегда валидным (накопленный минимум по проверкам);
+-- старые проверки с p-значением фиксированного горизонта в накопление не попадают
+ALTER TABLE guardrail_evaluations
+ADD COLUMN IF NOT EXISTS always_valid BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,38 @@
+package bucketing
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+)
+
+// назначения хеша: попадание в выборку и выбор варианта считаются независимо,
+// чтобы доля трафика эксперимента не влияла на распределение по вариантам
+const (
+	PurposeEligibility = "eligibility"
+	PurposeVariant     = "variant"
+	PurposeLayer       = "layer"
+)
+
+// Unit возвращает детерминированное число из [0, 1) для пользователя userID
+// в эксперименте с солью salt: первые 8 байт SHA-256 от "salt:purpose:userID"
+func Unit(salt, purpose, userID string) float64 {
+	sum := sha256.Sum256([]byte(salt + ":" + purpose + ":" + userID))
+	// 53 старших бита дают равномерное число двойной точности
+	return float64(binary.BigEndian.Uint64(sum[:8])>>11) / (1 << 53)
+}
+
+// Eligible проверяет, попадает ли пользователь в долю трафика эксперимента percent (0–100)
+func Eligible(salt, userID string, percent float64) bool {
+	if percent >= 100 {
+		return true
+	}
+	return Unit(salt, PurposeEligibility, userID)*100 < math.Max(percent, 0)
+}
+
+// InRange проверяет, попадает ли позиция пользователя в слое с солью layerSalt
+// в диапазон [start, end) процентов трафика слоя
+func InRange(layerSalt, userID string, start, end float64) bool {
+	position := Unit(layerSalt, PurposeLayer, userID) * 100
+	return position >= start && position < end
+}
@@ -0,0 +1,107 @@
+package bucketing
+
+import (
+	"math"
+	"strconv"
+	"testing"
+
+	"testing-platform/pkg/stats"
+)
+
+func TestUnitStable(t *testing.T) {
+	// эталон: первые 8 байт SHA-256 строки "salt:purpose:userID", 53 старших бита
+	tests := []struct {
+		salt, purpose, userID string
+		want                  float64
+	}{
+		{"exp-1", PurposeVariant, "42", 0.4806987681399578},
+		{"exp-1", PurposeEligibility, "42", 0.7708169535563518},
+		{"layer", PurposeLayer, "user-7", 0.6420833531583531},
+	}
+	for _, tt := range tests {
+		if got := Unit(tt.salt, tt.purpose, tt.userID); got != tt.want {
+			t.Errorf("Unit(%q, %q, %q) = %v, ожидается %v", tt.salt, tt.purpose, tt.userID, got, tt.want)
+		}
+	}
+}
+
+func TestUnitUniform(t *testing.T) {
+	// равномерность: критерий согласия хи-квадрат по 20 равным интервалам
+	const (
+		users = 20000
+		bins  = 20
+	)
+	observed := make([]int, bins)
+	shares := make([]float64, bins)
+	for i := range shares {
+		shares[i] = 1
+	}
+	for i := range users {
+		u := Unit("exp-uniform", PurposeVariant, strconv.Itoa(i))
+		if u < 0 || u >= 1 {
+			t.Fatalf("Unit = %v вне интервала [0, 1)", u)
+		}
+		observed[int(u*bins)]++
+	}
+	res, err := stats.ChiSquareGoodnessOfFit(observed, shares)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if res.PValue < 0.001 {
+		t.Errorf("распределение неравномерно: хи-квадрат %.2f, p = %g", res.Statistic, res.PValue)
+	}
+}
+
+func TestEligible(t *testing.T) {
+	const users = 20000
+	var inTen, inThirty int
+	for i := range users {
+		id := strconv.Itoa(i)
+		ten := Eligible("exp-eligible", id, 10)
+		thirty := Eligible("exp-eligible", id, 30)
+		// увеличение доли трафика не исключает уже попавших пользователей
+		if ten && !thirty {
+			t.Fatalf("пользователь %s попал в 10%%, но не в 30%%", id)
+		}
+		if ten {
+			inTen++
+		}
+		if thirty {
+			inThirty++
+		}
+		if !Eligible("exp-eligible", id, 100) || Eligible("exp-eligible", id, 0) {
+			t.Fatalf("пользователь %s: доли 100%% и 0%% должны включать всех и никого", id)
+		}
+	}
+	// допуск — четыре стандартных отклонения биномиальной доли
+	for _, tt := range []struct {
+		count   int
+		percent float64
+	}{{inTen, 10}, {inThirty, 30}} {
+		p := tt.percent / 100
+		tol := 4 * math.Sqrt(p*(1-p)/users)
+		if share := float64(tt.count) / users; math.Abs(share-p) > tol {
+			t.Errorf("в выборку %g%% попало %.4f пользователей, ожидается %.2f ± %.4f", tt.percent, share, p, tol)
+		}
+	}
+}
+
+func TestInRange(t *testing.T) {
+	// диапазоны слоя без пересечений: каждый пользователь попадает ровно в один
+	ranges := [][2]float64{{0, 25}, {25, 60}, {60, 100}}
+	for i := range 5000 {
+		id := strconv.Itoa(i)
+		hits := 0
+		for _, r := range ranges {
+			if InRange("layer-salt", id, r[0], r[1]) {
+				hits++
+			}
+		}
+		if hits != 1 {
+			t.Fatalf("пользователь %s попал в %d диапазонов, ожидается 1", id, hits)
+		}
+	}
+	if InRange("layer-salt", "1", 30, 30) {
+		t.Error("пустой диапазон не должен содержать пользователей")
+	}
+}
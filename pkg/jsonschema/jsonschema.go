@@ -0,0 +1,342 @@
+// Package jsonschema проверяет JSON-документы по подмножеству JSON Schema,
+// достаточному для описания параметров алгоритмов рекомендаций.
+//
+// Поддерживаются ключевые слова: type (строка или список), enum, properties, required,
+// additionalProperties (логическое значение или схема), items, minItems, maxItems,
+// minimum, maximum, exclusiveMinimum, exclusiveMaximum, minLength, maxLength, pattern,
+// а также описательные title, description и default. Пустая схема допускает любое значение.
+// Документы ожидаются в виде, который дает encoding/json: map[string]any, []any,
+// float64, string, bool и nil
+package jsonschema
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// типы JSON Schema
+var knownTypes = []string{"object", "array", "string", "number", "integer", "boolean", "null"}
+
+// ключевые слова, которые понимает валидатор
+var knownKeywords = map[string]bool{
+	"type": true, "enum": true, "properties": true, "required": true, "additionalProperties": true,
+	"items": true, "minItems": true, "maxItems": true, "minimum": true, "maximum": true,
+	"exclusiveMinimum": true, "exclusiveMaximum": true, "minLength": true, "maxLength": true,
+	"pattern": true, "title": true, "description": true, "default": true,
+}
+
+// Error ошибка проверки документа с путем до значения, например "$.weight"
+type Error struct {
+	Path    string
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Path + ": " + e.Message
+}
+
+// CheckSchema проверяет, что схема составлена из поддерживаемых ключевых слов
+// и значения ключевых слов имеют правильный вид
+func CheckSchema(schema map[string]any) error {
+	return checkSchema(schema, "$")
+}
+
+func checkSchema(schema map[string]any, path string) error {
+	for keyword, value := range schema {
+		if !knownKeywords[keyword] {
+			return &Error{Path: path, Message: fmt.Sprintf("неподдерживаемое ключевое слово %q", keyword)}
+		}
+		var err error
+		switch keyword {
+		case "type":
+			_, err = schemaTypes(value)
+		case "enum":
+			if _, ok := value.([]any); !ok {
+				err = fmt.Errorf("enum должен быть массивом")
+			}
+		case "properties":
+			props, ok := value.(map[string]any)
+			if !ok {
+				err = fmt.Errorf("properties должен быть объектом")
+				break
+			}
+			for name, sub := range props {
+				subSchema, ok := sub.(map[string]any)
+				if !ok {
+					return &Error{Path: path + "." + name, Message: "схема свойства должна быть объектом"}
+				}
+				if err := checkSchema(subSchema, path+"."+name); err != nil {
+					return err
+				}
+			}
+		case "required":
+			list, ok := value.([]any)
+			if !ok {
+				err = fmt.Errorf("required должен быть массивом строк")
+				break
+			}
+			for _, item := range list {
+				if _, ok := item.(string); !ok {
+					err = fmt.Errorf("required должен быть массивом строк")
+				}
+			}
+		case "additionalProperties":
+			switch v := value.(type) {
+			case bool:
+			case map[string]any:
+				if err := checkSchema(v, path+".*"); err != nil {
+					return err
+				}
+			default:
+				err = fmt.Errorf("additionalProperties должен быть логическим значением или схемой")
+			}
+		case "items":
+			sub, ok := value.(map[string]any)
+			if !ok {
+				err = fmt.Errorf("items должен быть схемой")
+				break
+			}
+			if err := checkSchema(sub, path+"[]"); err != nil {
+				return err
+			}
+		case "minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum":
+			if _, ok := value.(float64); !ok {
+				err = fmt.Errorf("%s должен быть числом", keyword)
+			}
+		case "minItems", "maxItems", "minLength", "maxLength":
+			if n, ok := value.(float64); !ok || n < 0 || n != math.Trunc(n) {
+				err = fmt.Errorf("%s должен быть неотрицательным целым", keyword)
+			}
+		case "pattern":
+			s, ok := value.(string)
+			if !ok {
+				err = fmt.Errorf("pattern должен быть строкой")
+				break
+			}
+			if _, reErr := regexp.Compile(s); reErr != nil {
+				err = fmt.Errorf("некорректный pattern: %v", reErr)
+			}
+		}
+		if err != nil {
+			return &Error{Path: path, Message: err.Error()}
+		}
+	}
+	return nil
+}
+
+// Validate проверяет значение value по схеме schema и возвращает первое найденное нарушение
+func Validate(schema map[string]any, value any) error {
+	if err := CheckSchema(schema); err != nil {
+		return fmt.Errorf("некорректная схема: %w", err)
+	}
+	return validate(schema, value, "$")
+}
+
+func validate(schema map[string]any, value any, path string) error {
+	if raw, ok := schema["type"]; ok {
+		types, _ := schemaTypes(raw)
+		if !slices.ContainsFunc(types, func(t string) bool { return hasType(value, t) }) {
+			return &Error{Path: path, Message: fmt.Sprintf("ожидается %s, получено %s", strings.Join(types, " или "), typeOf(value))}
+		}
+	}
+	if raw, ok := schema["enum"]; ok {
+		options, _ := raw.([]any)
+		if !slices.ContainsFunc(options, func(o any) bool { return equal(o, value) }) {
+			return &Error{Path: path, Message: "значение не входит в список допустимых"}
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		return validateObject(schema, v, path)
+	case []any:
+		return validateArray(schema, v, path)
+	case string:
+		return validateString(schema, v, path)
+	case float64:
+		return validateNumber(schema, v, path)
+	}
+	return nil
+}
+
+func validateObject(schema map[string]any, object map[string]any, path string) error {
+	if raw, ok := schema["required"]; ok {
+		required, _ := raw.([]any)
+		for _, item := range required {
+			name, _ := item.(string)
+			if _, ok := object[name]; !ok {
+				return &Error{Path: path, Message: fmt.Sprintf("отсутствует обязательное свойство %q", name)}
+			}
+		}
+	}
+
+	props, _ := schema["properties"].(map[string]any)
+	// свойства проверяются в алфавитном порядке, чтобы ошибка была воспроизводимой
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if sub, ok := props[name].(map[string]any); ok {
+			if err := validate(sub, object[name], path+"."+name); err != nil {
+				return err
+			}
+			continue
+		}
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				return &Error{Path: path, Message: fmt.Sprintf("свойство %q не предусмотрено схемой", name)}
+			}
+		case map[string]any:
+			if err := validate(additional, object[name], path+"."+name); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func validateArray(schema map[string]any, array []any, path string) error {
+	if n, ok := schema["minItems"].(float64); ok && float64(len(array)) < n {
+		return &Error{Path: path, Message: fmt.Sprintf("элементов должно быть не меньше %g", n)}
+	}
+	if n, ok := schema["maxItems"].(float64); ok && float64(len(array)) > n {
+		return &Error{Path: path, Message: fmt.Sprintf("элементов должно быть не больше %g", n)}
+	}
+	if items, ok := schema["items"].(map[string]any); ok {
+		for i, item := range array {
+			if err := validate(items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func validateString(schema map[string]any, s string, path string) error {
+	length := float64(utf8.RuneCountInString(s))
+	if n, ok := schema["minLength"].(float64); ok && length < n {
+		return &Error{Path: path, Message: fmt.Sprintf("строка должна быть не короче %g символов", n)}
+	}
+	if n, ok := schema["maxLength"].(float64); ok && length > n {
+		return &Error{Path: path, Message: fmt.Sprintf("строка должна быть не длиннее %g символов", n)}
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		if !regexp.MustCompile(pattern).MatchString(s) {
+			return &Error{Path: path, Message: fmt.Sprintf("строка не соответствует шаблону %s", pattern)}
+		}
+	}
+	return nil
+}
+
+func validateNumber(schema map[string]any, x float64, path string) error {
+	if n, ok := schema["minimum"].(float64); ok && x < n {
+		return &Error{Path: path, Message: fmt.Sprintf("значение должно быть не меньше %g", n)}
+	}
+	if n, ok := schema["maximum"].(float64); ok && x > n {
+		return &Error{Path: path, Message: fmt.Sprintf("значение должно быть не больше %g", n)}
+	}
+	if n, ok := schema["exclusiveMinimum"].(float64); ok && x <= n {
+		return &Error{Path: path, Message: fmt.Sprintf("значение должно быть больше %g", n)}
+	}
+	if n, ok := schema["exclusiveMaximum"].(float64); ok && x >= n {
+		return &Error{Path: path, Message: fmt.Sprintf("значение должно быть меньше %g", n)}
+	}
+	return nil
+}
+
+// schemaTypes возвращает типы из ключевого слова type
+func schemaTypes(raw any) ([]string, error) {
+	var types []string
+	switch v := raw.(type) {
+	case string:
+		types = []string{v}
+	case []any:
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("type должен быть строкой или массивом строк")
+			}
+			types = append(types, s)
+		}
+	default:
+		return nil, fmt.Errorf("type должен быть строкой или массивом строк")
+	}
+	for _, t := range types {
+		if !slices.Contains(knownTypes, t) {
+			return nil, fmt.Errorf("неизвестный тип %q", t)
+		}
+	}
+	return types, nil
+}
+
+// hasType проверяет соответствие значения типу JSON Schema
+func hasType(value any, t string) bool {
+	switch t {
+	case "integer":
+		x, ok := value.(float64)
+		return ok && x == math.Trunc(x)
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	default:
+		return typeOf(value) == t
+	}
+}
+
+// typeOf возвращает тип JSON значения
+func typeOf(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// equal сравнивает JSON значения
+func equal(a, b any) bool {
+	switch av := a.(type) {
+	case map[string]any:
+		bv, ok := b.(map[string]any)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for k, v := range av {
+			if w, ok := bv[k]; !ok || !equal(v, w) {
+				return false
+			}
+		}
+		return true
+	case []any:
+		bv, ok := b.([]any)
+		if !ok || len(av) != len(bv) {
+			return false
+		}
+		for i := range av {
+			if !equal(av[i], bv[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a == b
+	}
+}
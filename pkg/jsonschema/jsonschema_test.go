@@ -0,0 +1,122 @@
+package jsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+// схема параметров алгоритма для проверок
+const paramsSchema = `{
+	"type": "object",
+	"required": ["weight"],
+	"additionalProperties": false,
+	"properties": {
+		"weight": {"type": "number", "minimum": 0, "maximum": 1},
+		"k": {"type": "integer", "exclusiveMinimum": 0},
+		"mode": {"enum": ["fast", "exact"]},
+		"name": {"type": "string", "minLength": 2, "maxLength": 5, "pattern": "^[a-z]+$"},
+		"tags": {"type": "array", "maxItems": 2, "items": {"type": "string"}},
+		"extra": {"type": ["object", "null"], "additionalProperties": {"type": "boolean"}}
+	}
+}`
+
+// decode разбирает JSON так же, как документы получает валидатор
+func decode(t *testing.T, s string) any {
+	t.Helper()
+	var v any
+	if err := json.Unmarshal([]byte(s), &v); err != nil {
+		t.Fatalf("некорректный JSON %s: %v", s, err)
+	}
+	return v
+}
+
+func TestValidate(t *testing.T) {
+	schema := decode(t, paramsSchema).(map[string]any)
+	tests := []struct {
+		name, document, want string
+	}{
+		{"минимальный документ", `{"weight": 0.5}`, ""},
+		{"все свойства", `{"weight": 1, "k": 3, "mode": "fast", "name": "abc", "tags": ["a", "b"], "extra": {"x": true}}`, ""},
+		{"null вместо объекта", `{"weight": 0, "extra": null}`, ""},
+		{"не объект", `[1]`, "$: ожидается object, получено array"},
+		{"нет обязательного свойства", `{"k": 1}`, `$: отсутствует обязательное свойство "weight"`},
+		{"лишнее свойство", `{"weight": 0.5, "x": 1}`, `$: свойство "x" не предусмотрено схемой`},
+		{"больше максимума", `{"weight": 1.5}`, "$.weight: значение должно быть не больше 1"},
+		{"меньше минимума", `{"weight": -0.1}`, "$.weight: значение должно быть не меньше 0"},
+		{"дробное вместо целого", `{"weight": 0.5, "k": 1.5}`, "$.k: ожидается integer, получено number"},
+		{"строгая граница", `{"weight": 0.5, "k": 0}`, "$.k: значение должно быть больше 0"},
+		{"вне перечисления", `{"weight": 0.5, "mode": "slow"}`, "$.mode: значение не входит в список допустимых"},
+		{"короткая строка", `{"weight": 0.5, "name": "a"}`, "$.name: строка должна быть не короче 2 символов"},
+		{"длинная строка", `{"weight": 0.5, "name": "abcdef"}`, "$.name: строка должна быть не длиннее 5 символов"},
+		{"шаблон", `{"weight": 0.5, "name": "AB"}`, "$.name: строка не соответствует шаблону ^[a-z]+$"},
+		{"много элементов", `{"weight": 0.5, "tags": ["a", "b", "c"]}`, "$.tags: элементов должно быть не больше 2"},
+		{"тип элемента", `{"weight": 0.5, "tags": ["a", 1]}`, "$.tags[1]: ожидается string, получено number"},
+		{"схема дополнительных свойств", `{"weight": 0.5, "extra": {"x": 1}}`, "$.extra.x: ожидается boolean, получено number"},
+		// свойства проверяются в алфавитном порядке
+		{"первая ошибка", `{"weight": 2, "k": -1}`, "$.k: значение должно быть больше 0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(schema, decode(t, tt.document))
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("неожиданная ошибка: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("ожидается ошибка %q", tt.want)
+			}
+			var schemaErr *Error
+			if !errors.As(err, &schemaErr) {
+				t.Errorf("ошибка %v должна иметь тип *Error", err)
+			}
+			if err.Error() != tt.want {
+				t.Errorf("ошибка %q, ожидается %q", err.Error(), tt.want)
+			}
+		})
+	}
+
+	// пустая схема допускает любое значение
+	if err := Validate(map[string]any{}, decode(t, `{"a": [1, "b", null]}`)); err != nil {
+		t.Errorf("пустая схема: неожиданная ошибка: %v", err)
+	}
+}
+
+func TestCheckSchema(t *testing.T) {
+	tests := []struct {
+		name, schema, want string
+	}{
+		{"схема параметров", paramsSchema, ""},
+		{"описательные слова", `{"title": "t", "description": "d", "default": 1}`, ""},
+		{"неподдерживаемое слово", `{"oneOf": []}`, `$: неподдерживаемое ключевое слово "oneOf"`},
+		{"неизвестный тип", `{"type": "obj"}`, `$: неизвестный тип "obj"`},
+		{"вложенная ошибка", `{"properties": {"a": {"minimum": "0"}}}`, "$.a: minimum должен быть числом"},
+		{"схема элементов", `{"items": {"type": 1}}`, "$[]: type должен быть строкой или массивом строк"},
+		{"дробная длина", `{"maxLength": 1.5}`, "$: maxLength должен быть неотрицательным целым"},
+		{"required не строки", `{"required": [1]}`, "$: required должен быть массивом строк"},
+		{"enum не массив", `{"enum": "a"}`, "$: enum должен быть массивом"},
+		{"некорректный шаблон", `{"pattern": "("}`, "$: некорректный pattern: error parsing regexp: missing closing ): `(`"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckSchema(decode(t, tt.schema).(map[string]any))
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("неожиданная ошибка: %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.want {
+				t.Errorf("ошибка %v, ожидается %q", err, tt.want)
+			}
+		})
+	}
+
+	// Validate не проверяет документ по некорректной схеме
+	err := Validate(map[string]any{"type": "obj"}, map[string]any{})
+	if err == nil || err.Error() != `некорректная схема: $: неизвестный тип "obj"` {
+		t.Errorf("ошибка %v, ожидается ошибка некорректной схемы", err)
+	}
+}
@@ -0,0 +1,151 @@
+package stats
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"sort"
+)
+
+// BinomialArm наблюдения одной группы: число успехов из числа испытаний
+type BinomialArm struct {
+	Name      string
+	Successes int
+	Trials    int
+}
+
+// BetaArmPosterior апостериорное распределение Beta для доли успехов группы
+type BetaArmPosterior struct {
+	Name            string
+	Alpha           float64
+	Beta            float64
+	Mean            float64
+	CredibleLower   float64 // нижняя граница равностороннего интервала достоверности
+	CredibleUpper   float64
+	ProbBeatControl float64 // P(p_группы > p_контроля), для контроля равна 0
+	ProbBest        float64 // вероятность того, что группа лучшая среди всех
+	ExpectedLoss    float64 // E[max_j p_j - p_группы] — ожидаемые потери при выборе группы
+}
+
+// BayesianOptions параметры байесовского анализа
+type BayesianOptions struct {
+	PriorAlpha  float64 // параметры априорного Beta-распределения
+	PriorBeta   float64
+	Draws       int     // число выборок Монте-Карло
+	Credibility float64 // уровень интервала достоверности
+	Seed        uint64  // зерно генератора для воспроизводимости
+}
+
+// BetaBinomialAnalysis строит Beta-Binomial апостериорные распределения групп и методом Монте-Карло
+// оценивает вероятности превосходства и ожидаемые потери; первая группа считается контрольной
+func BetaBinomialAnalysis(arms []BinomialArm, opts BayesianOptions) ([]BetaArmPosterior, error) {
+	if len(arms) < 2 {
+		return nil, errors.New("для сравнения нужно минимум две группы")
+	}
+	if opts.PriorAlpha <= 0 || opts.PriorBeta <= 0 {
+		return nil, errors.New("параметры априорного распределения должны быть положительными")
+	}
+	if opts.Draws <= 0 {
+		return nil, errors.New("число выборок должно быть положительным")
+	}
+	if opts.Credibility <= 0 || opts.Credibility >= 1 {
+		return nil, errors.New("уровень достоверности должен быть в интервале (0, 1)")
+	}
+
+	rng := rand.New(rand.NewPCG(opts.Seed, uint64(opts.Draws)))
+	posteriors := make([]BetaArmPosterior, len(arms))
+	samples := make([][]float64, len(arms))
+	for i, arm := range arms {
+		if arm.Trials < 0 || arm.Successes < 0 || arm.Successes > arm.Trials {
+			return nil, errors.New("число успехов должно быть от 0 до числа испытаний")
+		}
+		a := opts.PriorAlpha + float64(arm.Successes)
+		b := opts.PriorBeta + float64(arm.Trials-arm.Successes)
+		posteriors[i] = BetaArmPosterior{Name: arm.Name, Alpha: a, Beta: b, Mean: a / (a + b)}
+
+		samples[i] = make([]float64, opts.Draws)
+		for d := range samples[i] {
+			samples[i][d] = SampleBeta(rng, a, b)
+		}
+	}
+
+	// сравнение групп в каждой выборке
+	for d := 0; d < opts.Draws; d++ {
+		best := 0
+		for i := range arms {
+			if samples[i][d] > samples[best][d] {
+				best = i
+			}
+		}
+		posteriors[best].ProbBest++
+		for i := range arms {
+			posteriors[i].ExpectedLoss += samples[best][d] - samples[i][d]
+			if i > 0 && samples[i][d] > samples[0][d] {
+				posteriors[i].ProbBeatControl++
+			}
+		}
+	}
+
+	tail := (1 - opts.Credibility) / 2
+	for i := range posteriors {
+		posteriors[i].ProbBest /= float64(opts.Draws)
+		posteriors[i].ProbBeatControl /= float64(opts.Draws)
+		posteriors[i].ExpectedLoss /= float64(opts.Draws)
+
+		sort.Float64s(samples[i])
+		posteriors[i].CredibleLower = Percentile(samples[i], tail)
+		posteriors[i].CredibleUpper = Percentile(samples[i], 1-tail)
+	}
+	return posteriors, nil
+}
+
+// Percentile возвращает квантиль уровня q отсортированной выборки (линейная интерполяция)
+func Percentile(sorted []float64, q float64) float64 {
+	if len(sorted) == 0 {
+		return math.NaN()
+	}
+	pos := q * float64(len(sorted)-1)
+	lo := int(math.Floor(pos))
+	hi := int(math.Ceil(pos))
+	if lo < 0 {
+		lo = 0
+	}
+	if hi >= len(sorted) {
+		hi = len(sorted) - 1
+	}
+	frac := pos - float64(lo)
+	return sorted[lo] + (sorted[hi]-sorted[lo])*frac
+}
+
+// SampleBeta возвращает случайное значение из распределения Beta(a, b)
+func SampleBeta(rng *rand.Rand, a, b float64) float64 {
+	x := SampleGamma(rng, a)
+	y := SampleGamma(rng, b)
+	return x / (x + y)
+}
+
+// SampleGamma возвращает случайное значение из распределения Gamma(shape, 1) (метод Марсальи–Цанга)
+func SampleGamma(rng *rand.Rand, shape float64) float64 {
+	if shape < 1 {
+		// повышение параметра формы: Gamma(a) = Gamma(a+1) · U^(1/a)
+		return SampleGamma(rng, shape+1) * math.Pow(rng.Float64(), 1/shape)
+	}
+
+	d := shape - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := rng.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := rng.Float64()
+		if u < 1-0.0331*x*x*x*x {
+			return d * v
+		}
+		if math.Log(u) < 0.5*x*x+d*(1-v+math.Log(v)) {
+			return d * v
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package stats
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestPercentile(t *testing.T) {
+	sorted := []float64{1, 2, 3, 4}
+	tests := []struct {
+		q, want float64
+	}{
+		{0, 1},
+		{0.5, 2.5},
+		{1, 4},
+		{0.25, 1.75},
+	}
+	for _, tt := range tests {
+		almostEqual(t, "Percentile", Percentile(sorted, tt.q), tt.want, 1e-12)
+	}
+	if !math.IsNaN(Percentile(nil, 0.5)) {
+		t.Error("квантиль пустой выборки должен быть NaN")
+	}
+}
+
+func TestSampleGammaMean(t *testing.T) {
+	// среднее Gamma(k, 1) равно k, в том числе для k < 1 (повышение параметра формы)
+	rng := rand.New(rand.NewPCG(1, 2))
+	for _, shape := range []float64{0.5, 1, 3.5} {
+		const draws = 200000
+		var sum float64
+		for range draws {
+			sum += SampleGamma(rng, shape)
+		}
+		almostEqual(t, "среднее Gamma", sum/draws, shape, 0.02*math.Max(shape, 1))
+	}
+}
+
+func TestBetaBinomialAnalysis(t *testing.T) {
+	arms := []BinomialArm{
+		{Name: "A", Successes: 10, Trials: 100},
+		{Name: "B", Successes: 20, Trials: 100},
+	}
+	opts := BayesianOptions{PriorAlpha: 1, PriorBeta: 1, Draws: 200000, Credibility: 0.95, Seed: 42}
+
+	posteriors, err := BetaBinomialAnalysis(arms, opts)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+
+	// сопряженное апостериорное Beta(1 + 10, 1 + 90)
+	a := posteriors[0]
+	if a.Alpha != 11 || a.Beta != 91 {
+		t.Errorf("апостериорное распределение A = Beta(%g, %g), ожидается Beta(11, 91)", a.Alpha, a.Beta)
+	}
+	almostEqual(t, "Mean", a.Mean, 11.0/102, 1e-12)
+	// эталон: qbeta(c(0.025, 0.975), 11, 91) в R
+	almostEqual(t, "CredibleLower", a.CredibleLower, 0.05563722, 0.002)
+	almostEqual(t, "CredibleUpper", a.CredibleUpper, 0.17455283, 0.002)
+	// эталон: P(B > A) численным интегрированием ∫ f_B(x) F_A(x) dx
+	almostEqual(t, "ProbBeatControl", posteriors[1].ProbBeatControl, 0.97517303, 0.003)
+	if a.ProbBeatControl != 0 {
+		t.Errorf("для контроля P(> контроля) = %g, ожидается 0", a.ProbBeatControl)
+	}
+	almostEqual(t, "сумма ProbBest", a.ProbBest+posteriors[1].ProbBest, 1, 1e-12)
+	if posteriors[1].ExpectedLoss >= a.ExpectedLoss {
+		t.Errorf("потери лучшей группы %g должны быть меньше потерь контроля %g", posteriors[1].ExpectedLoss, a.ExpectedLoss)
+	}
+
+	// одинаковое зерно дает одинаковый результат
+	again, err := BetaBinomialAnalysis(arms, opts)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	for i := range posteriors {
+		if posteriors[i] != again[i] {
+			t.Errorf("результат с тем же зерном отличается: %+v и %+v", posteriors[i], again[i])
+		}
+	}
+}
+
+func TestBetaBinomialAnalysisErrors(t *testing.T) {
+	valid := BayesianOptions{PriorAlpha: 1, PriorBeta: 1, Draws: 100, Credibility: 0.95}
+	arms := []BinomialArm{{Successes: 1, Trials: 10}, {Successes: 2, Trials: 10}}
+
+	tests := []struct {
+		name string
+		arms []BinomialArm
+		opts BayesianOptions
+	}{
+		{"одна группа", arms[:1], valid},
+		{"нулевой априор", arms, BayesianOptions{PriorBeta: 1, Draws: 100, Credibility: 0.95}},
+		{"нет выборок", arms, BayesianOptions{PriorAlpha: 1, PriorBeta: 1, Credibility: 0.95}},
+		{"уровень достоверности 1", arms, BayesianOptions{PriorAlpha: 1, PriorBeta: 1, Draws: 100, Credibility: 1}},
+		{"успехов больше испытаний", []BinomialArm{{Successes: 11, Trials: 10}, arms[1]}, valid},
+	}
+	for _, tt := range tests {
+		if _, err := BetaBinomialAnalysis(tt.arms, tt.opts); err == nil {
+			t.Errorf("%s: ожидается ошибка", tt.name)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package stats
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand/v2"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BootstrapMethod метод построения бутстрэп-интервала
+type BootstrapMethod string
+
+const (
+	BootstrapPercentile BootstrapMethod = "percentile" // процентильный интервал
+	BootstrapBCa        BootstrapMethod = "bca"        // интервал с поправкой на смещение и ускорение
+)
+
+// максимальное число блоков в jackknife-оценке ускорения для BCa
+const jackknifeMaxBlocks = 2000
+
+// BootstrapOptions параметры бутстрэпа
+type BootstrapOptions struct {
+	Iterations int
+	Confidence float64
+	Method     BootstrapMethod
+	Workers    int    // число горутин (по умолчанию — число процессоров)
+	Seed       uint64 // зерно генератора для воспроизводимости
+}
+
+// BootstrapResult результат бутстрэп-оценки разности статистики между группами
+type BootstrapResult struct {
+	Control       float64 // значение статистики в контрольной группе
+	Treatment     float64 // значение статистики в тестовой группе
+	Difference    float64 // Treatment - Control
+	StandardError float64 // стандартное отклонение бутстрэп-распределения разности
+	CILower       float64
+	CIUpper       float64
+	Iterations    int // число итераций с определенным значением статистики
+}
+
+// BootstrapDifference оценивает доверительный интервал разности statistic(treatment) - statistic(control)
+// повторной выборкой единиц наблюдения с возвращением. Итерации выполняются параллельно,
+// ctx позволяет прервать расчет, progress (может быть nil) получает число выполненных итераций
+func BootstrapDifference[T any](ctx context.Context, control, treatment []T, statistic func([]T) float64,
+	opts BootstrapOptions, progress func(done, total int)) (BootstrapResult, error) {
+	if len(control) == 0 || len(treatment) == 0 {
+		return BootstrapResult{}, errors.New("в каждой группе должна быть хотя бы одна единица наблюдения")
+	}
+	if opts.Iterations <= 0 {
+		return BootstrapResult{}, errors.New("число итераций должно быть положительным")
+	}
+	if opts.Confidence <= 0 || opts.Confidence >= 1 {
+		return BootstrapResult{}, errors.New("уровень доверия должен быть в интервале (0, 1)")
+	}
+	if opts.Method == "" {
+		opts.Method = BootstrapPercentile
+	}
+	if opts.Method != BootstrapPercentile && opts.Method != BootstrapBCa {
+		return BootstrapResult{}, errors.New("неизвестный метод бутстрэпа")
+	}
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	workers = min(workers, opts.Iterations)
+
+	res := BootstrapResult{
+		Control:   statistic(control),
+		Treatment: statistic(treatment),
+	}
+	res.Difference = res.Treatment - res.Control
+
+	diffs := make([]float64, opts.Iterations)
+	var done atomic.Int64
+
+	// отчет о прогрессе из отдельной горутины
+	stopProgress := make(chan struct{})
+	progressDone := make(chan struct{})
+	go func() {
+		defer close(progressDone)
+		if progress == nil {
+			<-stopProgress
+			return
+		}
+		ticker := time.NewTicker(100 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				progress(int(done.Load()), opts.Iterations)
+			case <-stopProgress:
+				progress(int(done.Load()), opts.Iterations)
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	chunk := (opts.Iterations + workers - 1) / workers
+	for w := 0; w < workers; w++ {
+		from := w * chunk
+		to := min(from+chunk, opts.Iterations)
+		if from >= to {
+			break
+		}
+		wg.Add(1)
+		go func(worker, from, to int) {
+			defer wg.Done()
+			rng := rand.New(rand.NewPCG(opts.Seed, uint64(worker)+1))
+			sampleA := make([]T, len(control))
+			sampleB := make([]T, len(treatment))
+			for i := from; i < to; i++ {
+				if ctx.Err() != nil {
+					return
+				}
+				for j := range sampleA {
+					sampleA[j] = control[rng.IntN(len(control))]
+				}
+				for j := range sampleB {
+					sampleB[j] = treatment[rng.IntN(len(treatment))]
+				}
+				diffs[i] = statistic(sampleB) - statistic(sampleA)
+				done.Add(1)
+			}
+		}(w, from, to)
+	}
+	wg.Wait()
+	close(stopProgress)
+	<-progressDone
+
+	if err := ctx.Err(); err != nil {
+		return BootstrapResult{}, err
+	}
+
+	// итерации с неопределенной статистикой (например, пустая выборка оценок) отбрасываются
+	valid := diffs[:0]
+	for _, d := range diffs {
+		if !math.IsNaN(d) && !math.IsInf(d, 0) {
+			valid = append(valid, d)
+		}
+	}
+	if len(valid) == 0 {
+		return BootstrapResult{}, errors.New("статистика не определена ни в одной итерации")
+	}
+	sort.Float64s(valid)
+	res.Iterations = len(valid)
+	res.StandardError = Summarize(valid).StdDev
+
+	lowerQ := (1 - opts.Confidence) / 2
+	upperQ := 1 - lowerQ
+	if opts.Method == BootstrapBCa {
+		acceleration, err := jackknifeAcceleration(ctx, control, treatment, statistic)
+		if err != nil {
+			return BootstrapResult{}, err
+		}
+		lowerQ, upperQ = bcaQuantiles(valid, res.Difference, acceleration, opts.Confidence)
+	}
+	res.CILower = Percentile(valid, lowerQ)
+	res.CIUpper = Percentile(valid, upperQ)
+	return res, nil
+}
+
+// bcaQuantiles возвращает скорректированные уровни квантилей для BCa-интервала
+func bcaQuantiles(sorted []float64, estimate, acceleration, confidence float64) (float64, float64) {
+	// поправка на смещение: доля бутстрэп-значений ниже точечной оценки
+	below := float64(sort.SearchFloat64s(sorted, estimate))
+	equal := float64(sort.SearchFloat64s(sorted, math.Nextafter(estimate, math.Inf(1)))) - below
+	share := (below + equal/2) / float64(len(sorted))
+	share = math.Min(math.Max(share, 1/float64(len(sorted)+1)), 1-1/float64(len(sorted)+1))
+	z0 := NormalQuantile(share)
+
+	adjust := func(q float64) float64 {
+		z := NormalQuantile(q)
+		return NormalCDF(z0 + (z0+z)/(1-acceleration*(z0+z)))
+	}
+	return adjust((1 - confidence) / 2), adjust(1 - (1-confidence)/2)
+}
+
+// jackknifeAcceleration оценивает ускорение BCa методом складного ножа: из объединенной выборки
+// поочередно исключаются единицы наблюдения (для больших выборок — блоки единиц)
+func jackknifeAcceleration[T any](ctx context.Context, control, treatment []T, statistic func([]T) float64) (float64, error) {
+	n := len(control) + len(treatment)
+	blockSize := (n + jackknifeMaxBlocks - 1) / jackknifeMaxBlocks
+
+	// оценка разности без блока [from, to) объединенной выборки
+	leaveOut := func(from, to int) float64 {
+		if to <= len(control) {
+			rest := append(append([]T(nil), control[:from]...), control[to:]...)
+			return statistic(treatment) - statistic(rest)
+		}
+		from, to = max(from-len(control), 0), to-len(control)
+		rest := append(append([]T(nil), treatment[:from]...), treatment[to:]...)
+		return statistic(rest) - statistic(control)
+	}
+
+	var values []float64
+	// блоки не пересекают границу групп
+	for _, bounds := range [][2]int{{0, len(control)}, {len(control), n}} {
+		for from := bounds[0]; from < bounds[1]; from += blockSize {
+			if err := ctx.Err(); err != nil {
+				return 0, err
+			}
+			v := leaveOut(from, min(from+blockSize, bounds[1]))
+			if !math.IsNaN(v) && !math.IsInf(v, 0) {
+				values = append(values, v)
+			}
+		}
+	}
+	if len(values) < 2 {
+		return 0, nil
+	}
+
+	mean := Summarize(values).Mean
+	var num, den float64
+	for _, v := range values {
+		d := mean - v
+		num += d * d * d
+		den += d * d
+	}
+	if den == 0 {
+		return 0, nil
+	}
+	return num / (6 * math.Pow(den, 1.5)), nil
+}
@@ -0,0 +1,124 @@
+package stats
+
+import (
+	"context"
+	"math"
+	"testing"
+)
+
+// mean статистика для бутстрэпа — выборочное среднее
+func mean(values []float64) float64 {
+	return Summarize(values).Mean
+}
+
+func TestBootstrapDifferencePercentile(t *testing.T) {
+	control := make([]float64, 100)
+	treatment := make([]float64, 100)
+	for i := range control {
+		control[i] = float64(i)
+		treatment[i] = float64(i) + 5
+	}
+	opts := BootstrapOptions{Iterations: 20000, Confidence: 0.95, Method: BootstrapPercentile, Workers: 4, Seed: 42}
+
+	res, err := BootstrapDifference(context.Background(), control, treatment, mean, opts, nil)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	almostEqual(t, "Difference", res.Difference, 5, 1e-12)
+	if res.Iterations != opts.Iterations {
+		t.Errorf("Iterations = %d, ожидается %d", res.Iterations, opts.Iterations)
+	}
+	// бутстрэп-дисперсия разности средних: (σ²_A + σ²_B) / n с дисперсией по выборке без поправки,
+	// σ² = (100² - 1) / 12 — стандартная ошибка sqrt(2 · 833.25 / 100) = 4.0823
+	se := math.Sqrt(2 * 833.25 / 100)
+	almostEqual(t, "StandardError", res.StandardError, se, 0.1)
+	almostEqual(t, "CILower", res.CILower, 5-1.959963984540054*se, 0.3)
+	almostEqual(t, "CIUpper", res.CIUpper, 5+1.959963984540054*se, 0.3)
+
+	// одинаковые зерно и число горутин дают одинаковый результат
+	again, err := BootstrapDifference(context.Background(), control, treatment, mean, opts, nil)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if again != res {
+		t.Errorf("результат с тем же зерном отличается: %+v и %+v", res, again)
+	}
+}
+
+func TestJackknifeAcceleration(t *testing.T) {
+	// эталон: a = Σ(θ̄ - θ_i)³ / (6 (Σ(θ̄ - θ_i)²)^1.5) по шести оценкам без одного наблюдения
+	a, err := jackknifeAcceleration(context.Background(), []float64{1, 2, 3}, []float64{2, 4, 9}, mean)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	almostEqual(t, "acceleration", a, 0.04049619353670291, 1e-12)
+}
+
+func TestBCaQuantiles(t *testing.T) {
+	// без смещения и ускорения BCa совпадает с процентильным интервалом
+	sorted := []float64{-2, -1, 1, 2}
+	lower, upper := bcaQuantiles(sorted, 0, 0, 0.9)
+	almostEqual(t, "нижний уровень", lower, 0.05, 1e-12)
+	almostEqual(t, "верхний уровень", upper, 0.95, 1e-12)
+
+	// оценка выше большинства бутстрэп-значений сдвигает оба уровня вверх
+	lower, upper = bcaQuantiles(sorted, 1.5, 0, 0.9)
+	if lower <= 0.05 || upper <= 0.95 {
+		t.Errorf("уровни [%g; %g] должны сдвинуться вверх относительно [0.05; 0.95]", lower, upper)
+	}
+}
+
+func TestBootstrapDifferenceBCa(t *testing.T) {
+	// скошенная вправо выборка: BCa-интервал сдвинут вверх относительно процентильного
+	control := []float64{0}
+	treatment := make([]float64, 60)
+	for i := range treatment {
+		treatment[i] = math.Exp(float64(i%20) / 4)
+	}
+	percentile := BootstrapOptions{Iterations: 20000, Confidence: 0.95, Method: BootstrapPercentile, Workers: 2, Seed: 7}
+	bca := percentile
+	bca.Method = BootstrapBCa
+
+	p, err := BootstrapDifference(context.Background(), control, treatment, mean, percentile, nil)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	b, err := BootstrapDifference(context.Background(), control, treatment, mean, bca, nil)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if b.CILower > b.Difference || b.CIUpper < b.Difference {
+		t.Errorf("BCa-интервал [%g; %g] не содержит оценку %g", b.CILower, b.CIUpper, b.Difference)
+	}
+	if b.CILower <= p.CILower || b.CIUpper <= p.CIUpper {
+		t.Errorf("BCa-интервал [%g; %g] должен быть сдвинут вверх относительно процентильного [%g; %g]",
+			b.CILower, b.CIUpper, p.CILower, p.CIUpper)
+	}
+}
+
+func TestBootstrapDifferenceErrors(t *testing.T) {
+	valid := BootstrapOptions{Iterations: 10, Confidence: 0.95}
+	data := []float64{1, 2, 3}
+
+	tests := []struct {
+		name               string
+		control, treatment []float64
+		opts               BootstrapOptions
+	}{
+		{"пустая группа", nil, data, valid},
+		{"нет итераций", data, data, BootstrapOptions{Confidence: 0.95}},
+		{"уровень доверия 1", data, data, BootstrapOptions{Iterations: 10, Confidence: 1}},
+		{"неизвестный метод", data, data, BootstrapOptions{Iterations: 10, Confidence: 0.95, Method: "jackknife"}},
+	}
+	for _, tt := range tests {
+		if _, err := BootstrapDifference(context.Background(), tt.control, tt.treatment, mean, tt.opts, nil); err == nil {
+			t.Errorf("%s: ожидается ошибка", tt.name)
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := BootstrapDifference(ctx, data, data, mean, valid, nil); err == nil {
+		t.Error("для отмененного контекста ожидается ошибка")
+	}
+}
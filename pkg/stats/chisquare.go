@@ -0,0 +1,104 @@
+package stats
+
+import (
+	"errors"
+	"math"
+)
+
+// ChiSquareTest результат критерия хи-квадрат
+type ChiSquareTest struct {
+	Statistic float64
+	DF        int
+	PValue    float64
+	Expected  []float64 // ожидаемые частоты (для критерия согласия)
+}
+
+// ChiSquareGoodnessOfFit проверяет соответствие наблюдаемых частот ожидаемым долям
+// (доли нормируются на их сумму)
+func ChiSquareGoodnessOfFit(observed []int, shares []float64) (ChiSquareTest, error) {
+	if len(observed) != len(shares) {
+		return ChiSquareTest{}, errors.New("количество частот и долей не совпадает")
+	}
+	if len(observed) < 2 {
+		return ChiSquareTest{}, errors.New("для критерия нужно минимум две категории")
+	}
+
+	var total int
+	var shareSum float64
+	for i := range observed {
+		if observed[i] < 0 || shares[i] <= 0 {
+			return ChiSquareTest{}, errors.New("частоты должны быть неотрицательными, а доли положительными")
+		}
+		total += observed[i]
+		shareSum += shares[i]
+	}
+	if total == 0 {
+		return ChiSquareTest{}, errors.New("нет наблюдений")
+	}
+
+	res := ChiSquareTest{DF: len(observed) - 1, Expected: make([]float64, len(observed))}
+	for i := range observed {
+		res.Expected[i] = float64(total) * shares[i] / shareSum
+		diff := float64(observed[i]) - res.Expected[i]
+		res.Statistic += diff * diff / res.Expected[i]
+	}
+	res.PValue = ChiSquareSF(res.Statistic, float64(res.DF))
+	if math.IsNaN(res.PValue) {
+		res.PValue = 1
+	}
+	return res, nil
+}
+
+// ChiSquareHomogeneity проверяет однородность распределений по таблице сопряженности:
+// строки — группы, столбцы — категории. Категории без наблюдений во всех группах
+// не учитываются. Expected содержит ожидаемые частоты построчно для учтенных категорий
+func ChiSquareHomogeneity(table [][]int) (ChiSquareTest, error) {
+	if len(table) < 2 {
+		return ChiSquareTest{}, errors.New("для критерия нужно минимум две группы")
+	}
+	columns := len(table[0])
+	rowTotals := make([]int, len(table))
+	colTotals := make([]int, columns)
+	var total int
+	for i, row := range table {
+		if len(row) != columns {
+			return ChiSquareTest{}, errors.New("строки таблицы должны иметь одинаковую длину")
+		}
+		for j, v := range row {
+			if v < 0 {
+				return ChiSquareTest{}, errors.New("частоты должны быть неотрицательными")
+			}
+			rowTotals[i] += v
+			colTotals[j] += v
+			total += v
+		}
+		if rowTotals[i] == 0 {
+			return ChiSquareTest{}, errors.New("в каждой группе должно быть хотя бы одно наблюдение")
+		}
+	}
+
+	var used []int
+	for j, v := range colTotals {
+		if v > 0 {
+			used = append(used, j)
+		}
+	}
+	if len(used) < 2 {
+		return ChiSquareTest{}, errors.New("для критерия нужно минимум две категории с наблюдениями")
+	}
+
+	res := ChiSquareTest{DF: (len(table) - 1) * (len(used) - 1)}
+	for i, row := range table {
+		for _, j := range used {
+			expected := float64(rowTotals[i]) * float64(colTotals[j]) / float64(total)
+			diff := float64(row[j]) - expected
+			res.Statistic += diff * diff / expected
+			res.Expected = append(res.Expected, expected)
+		}
+	}
+	res.PValue = ChiSquareSF(res.Statistic, float64(res.DF))
+	if math.IsNaN(res.PValue) {
+		res.PValue = 1
+	}
+	return res, nil
+}
@@ -0,0 +1,141 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestChiSquareSF(t *testing.T) {
+	// эталон: для df = 2 хвост равен exp(-x/2), для df = 1 — erfc(sqrt(x/2)),
+	// для df = 3 — erfc(sqrt(x/2)) + sqrt(2x/π)·exp(-x/2), для df = 4 — exp(-x/2)·(1 + x/2)
+	tests := []struct {
+		name      string
+		x, df, sf float64
+	}{
+		{"df = 1", 4, 1, 0.045500263896358396},
+		{"df = 2", 4 / 3.0, 2, 0.513417119032592},
+		{"df = 3", 2, 3, 0.5724067044708798},
+		{"df = 4, хвост", 10, 4, 0.0404276819945128},
+		{"df = 2, большой хвост", 60, 2, math.Exp(-30)},
+		{"нулевая статистика", 0, 5, 1},
+	}
+	for _, tt := range tests {
+		got := ChiSquareSF(tt.x, tt.df)
+		// сравнение относительное: хвост бывает очень мал
+		if math.Abs(got/tt.sf-1) > 1e-9 {
+			t.Errorf("%s: ChiSquareSF(%g, %g) = %.15g, ожидается %.15g", tt.name, tt.x, tt.df, got, tt.sf)
+		}
+	}
+}
+
+func TestChiSquareGoodnessOfFit(t *testing.T) {
+	tests := []struct {
+		name      string
+		observed  []int
+		shares    []float64
+		statistic float64
+		df        int
+		pValue    float64
+		expected  []float64
+	}{
+		// эталон: chisq.test(c(60, 40)) в R — X-squared = 4, df = 1, p-value = 0.0455
+		{"перекос 60/40", []int{60, 40}, []float64{0.5, 0.5}, 4, 1, 0.045500263896358396, []float64{50, 50}},
+		{"точное совпадение", []int{50, 50}, []float64{1, 1}, 0, 1, 1, []float64{50, 50}},
+		// доли нормируются на сумму: 5:3:2 то же, что 0.5/0.3/0.2
+		{"неравные доли", []int{45, 35, 20}, []float64{5, 3, 2}, 4 / 3.0, 2, 0.513417119032592, []float64{50, 30, 20}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := ChiSquareGoodnessOfFit(tt.observed, tt.shares)
+			if err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+			almostEqual(t, "Statistic", res.Statistic, tt.statistic, 1e-12)
+			if res.DF != tt.df {
+				t.Errorf("DF = %d, ожидается %d", res.DF, tt.df)
+			}
+			almostEqual(t, "PValue", res.PValue, tt.pValue, 1e-12)
+			for i := range tt.expected {
+				almostEqual(t, "Expected", res.Expected[i], tt.expected[i], 1e-12)
+			}
+		})
+	}
+}
+
+func TestChiSquareGoodnessOfFitErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		observed []int
+		shares   []float64
+	}{
+		{"разная длина", []int{1, 2}, []float64{1}},
+		{"одна категория", []int{1}, []float64{1}},
+		{"отрицательная частота", []int{-1, 2}, []float64{1, 1}},
+		{"нулевая доля", []int{1, 2}, []float64{1, 0}},
+		{"нет наблюдений", []int{0, 0}, []float64{1, 1}},
+	}
+	for _, tt := range tests {
+		if _, err := ChiSquareGoodnessOfFit(tt.observed, tt.shares); err == nil {
+			t.Errorf("%s: ожидается ошибка", tt.name)
+		}
+	}
+}
+
+func TestChiSquareHomogeneity(t *testing.T) {
+	// эталон: chisq.test(table, correct = FALSE) в R
+	tests := []struct {
+		name      string
+		table     [][]int
+		statistic float64
+		df        int
+		pValue    float64
+		expected  []float64
+	}{
+		{"2×2", [][]int{{20, 80}, {35, 65}}, 5.642633228840125, 1, 0.01752886126037274,
+			[]float64{27.5, 72.5, 27.5, 72.5}},
+		{"2×3", [][]int{{30, 50, 20}, {20, 50, 30}}, 4, 2, 0.1353352832366127,
+			[]float64{25, 50, 25, 25, 50, 25}},
+		// категория без наблюдений во всех группах не учитывается
+		{"пустая категория", [][]int{{10, 0, 20}, {20, 0, 10}}, 6.666666666666667, 1, 0.009823274507519245,
+			[]float64{15, 15, 15, 15}},
+		{"одинаковые распределения", [][]int{{10, 20}, {20, 40}}, 0, 1, 1,
+			[]float64{10, 20, 20, 40}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := ChiSquareHomogeneity(tt.table)
+			if err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+			almostEqual(t, "Statistic", res.Statistic, tt.statistic, 1e-12)
+			if res.DF != tt.df {
+				t.Errorf("DF = %d, ожидается %d", res.DF, tt.df)
+			}
+			almostEqual(t, "PValue", res.PValue, tt.pValue, 1e-12)
+			if len(res.Expected) != len(tt.expected) {
+				t.Fatalf("получено %d ожидаемых частот, ожидается %d", len(res.Expected), len(tt.expected))
+			}
+			for i := range tt.expected {
+				almostEqual(t, "Expected", res.Expected[i], tt.expected[i], 1e-12)
+			}
+		})
+	}
+}
+
+func TestChiSquareHomogeneityErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		table [][]int
+	}{
+		{"одна группа", [][]int{{1, 2}}},
+		{"строки разной длины", [][]int{{1, 2}, {1}}},
+		{"отрицательная частота", [][]int{{1, -2}, {1, 2}}},
+		{"пустая группа", [][]int{{0, 0}, {1, 2}}},
+		{"одна категория с наблюдениями", [][]int{{0, 3}, {0, 2}}},
+	}
+	for _, tt := range tests {
+		if _, err := ChiSquareHomogeneity(tt.table); err == nil {
+			t.Errorf("%s: ожидается ошибка", tt.name)
+		}
+	}
+}
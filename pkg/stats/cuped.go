@@ -0,0 +1,55 @@
+package stats
+
+import (
+	"errors"
+	"math"
+)
+
+// CUPEDAdjustment параметры CUPED-корректировки метрики по ковариате предпериода
+type CUPEDAdjustment struct {
+	Theta             float64 // коэффициент cov(X, Y) / var(X)
+	CovariateMean     float64 // среднее ковариаты по всем группам
+	Correlation       float64 // корреляция метрики и ковариаты
+	VarianceReduction float64 // доля снижения дисперсии: 1 - var(Y') / var(Y)
+}
+
+// CUPED рассчитывает общий для всех групп коэффициент theta и возвращает скорректированную
+// метрику Y' = Y - theta * (X - mean(X)). Среднее Y' совпадает со средним Y, поэтому
+// оценка эффекта остается несмещенной, а дисперсия уменьшается в (1 - corr²) раз
+func CUPED(metric, covariate []float64) (CUPEDAdjustment, []float64, error) {
+	if len(metric) != len(covariate) {
+		return CUPEDAdjustment{}, nil, errors.New("длины метрики и ковариаты не совпадают")
+	}
+	if len(metric) < 2 {
+		return CUPEDAdjustment{}, nil, errors.New("для CUPED нужно минимум два наблюдения")
+	}
+
+	y := Summarize(metric)
+	x := Summarize(covariate)
+	if x.Variance == 0 {
+		return CUPEDAdjustment{}, nil, errors.New("ковариата не меняется между пользователями")
+	}
+
+	var cov float64
+	for i := range metric {
+		cov += (metric[i] - y.Mean) * (covariate[i] - x.Mean)
+	}
+	cov /= float64(len(metric) - 1)
+
+	adj := CUPEDAdjustment{
+		Theta:         cov / x.Variance,
+		CovariateMean: x.Mean,
+	}
+	if y.Variance > 0 {
+		adj.Correlation = cov / math.Sqrt(x.Variance*y.Variance)
+	}
+
+	adjusted := make([]float64, len(metric))
+	for i := range metric {
+		adjusted[i] = metric[i] - adj.Theta*(covariate[i]-x.Mean)
+	}
+	if y.Variance > 0 {
+		adj.VarianceReduction = 1 - Summarize(adjusted).Variance/y.Variance
+	}
+	return adj, adjusted, nil
+}
@@ -0,0 +1,55 @@
+package stats
+
+import "testing"
+
+func TestCUPED(t *testing.T) {
+	// эталон: theta = cov(x, y) / var(x) = 1.5 / 2.5, cor(x, y) = sqrt(0.6) — lm(y ~ x) и cor в R
+	metric := []float64{2, 4, 5, 4, 5}
+	covariate := []float64{1, 2, 3, 4, 5}
+
+	adj, adjusted, err := CUPED(metric, covariate)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	almostEqual(t, "Theta", adj.Theta, 0.6, 1e-12)
+	almostEqual(t, "CovariateMean", adj.CovariateMean, 3, 1e-12)
+	almostEqual(t, "Correlation", adj.Correlation, 0.7745966692414834, 1e-12)
+	// снижение дисперсии равно квадрату корреляции
+	almostEqual(t, "VarianceReduction", adj.VarianceReduction, 0.6, 1e-12)
+
+	want := []float64{3.2, 4.6, 5.0, 3.4, 3.8}
+	for i := range want {
+		almostEqual(t, "скорректированная метрика", adjusted[i], want[i], 1e-12)
+	}
+	// среднее не меняется, поэтому оценка эффекта остается несмещенной
+	almostEqual(t, "среднее после CUPED", Summarize(adjusted).Mean, Summarize(metric).Mean, 1e-12)
+}
+
+func TestCUPEDConstantMetric(t *testing.T) {
+	adj, adjusted, err := CUPED([]float64{3, 3, 3}, []float64{1, 2, 3})
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if adj.Theta != 0 || adj.Correlation != 0 || adj.VarianceReduction != 0 {
+		t.Errorf("для постоянной метрики ожидаются нулевые параметры, получено %+v", adj)
+	}
+	for _, v := range adjusted {
+		almostEqual(t, "скорректированная метрика", v, 3, 1e-12)
+	}
+}
+
+func TestCUPEDErrors(t *testing.T) {
+	tests := []struct {
+		name              string
+		metric, covariate []float64
+	}{
+		{"разная длина", []float64{1, 2}, []float64{1}},
+		{"одно наблюдение", []float64{1}, []float64{1}},
+		{"постоянная ковариата", []float64{1, 2, 3}, []float64{5, 5, 5}},
+	}
+	for _, tt := range tests {
+		if _, _, err := CUPED(tt.metric, tt.covariate); err == nil {
+			t.Errorf("%s: ожидается ошибка", tt.name)
+		}
+	}
+}
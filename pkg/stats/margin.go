@@ -0,0 +1,41 @@
+package stats
+
+import "math"
+
+// MarginExceedancePValue одностороннее p-значение гипотезы H0: estimate <= margin
+// против H1: estimate > margin при нормально распределенной оценке со стандартной ошибкой standardError.
+// Используется для проверки того, что ухудшение метрики значимо превышает допустимое
+func MarginExceedancePValue(estimate, margin, standardError float64) float64 {
+	if standardError <= 0 {
+		if estimate > margin {
+			return 0
+		}
+		return 1
+	}
+	return 1 - NormalCDF((estimate-margin)/standardError)
+}
+
+// MarginExceedanceSequentialPValue всегда валидное одностороннее p-значение той же гипотезы
+// H0: estimate <= margin для мониторинга с повторными проверками. Используется смешанный тест
+// отношения правдоподобия с полунормальным смешивающим распределением эффекта θ = estimate - margin > 0
+// со стандартным отклонением mixingSD; при H0 отношение правдоподобия — супермартингал, поэтому
+// остановка при p <= alpha на любой из проверок ошибается не чаще alpha.
+// prevPValue — p-значение предыдущей проверки (1, если проверок не было): p-значение не возрастает
+func MarginExceedanceSequentialPValue(estimate, margin, standardError, mixingSD, prevPValue float64) float64 {
+	prev := math.Min(prevPValue, 1)
+	if standardError <= 0 || mixingSD <= 0 {
+		if standardError <= 0 && estimate > margin {
+			return 0
+		}
+		return prev
+	}
+
+	v := standardError * standardError
+	tau2 := mixingSD * mixingSD
+	x := estimate - margin
+	// апостериорные среднее и стандартное отклонение θ при нормальном смешивании
+	m := x * tau2 / (v + tau2)
+	s := math.Sqrt(v * tau2 / (v + tau2))
+	logLR := math.Log(2) + 0.5*math.Log(v/(v+tau2)) + tau2*x*x/(2*v*(v+tau2)) + math.Log(NormalCDF(m/s))
+	return math.Min(prev, math.Exp(-logLR))
+}
@@ -0,0 +1,92 @@
+package stats
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestMarginExceedancePValue(t *testing.T) {
+	tests := []struct {
+		name                 string
+		estimate, margin, se float64
+		want                 float64
+	}{
+		{"на границе", 0.02, 0.02, 0.01, 0.5},
+		// эталон: pnorm(3, lower.tail = FALSE) в R
+		{"три ошибки выше границы", 0.05, 0.02, 0.01, 0.0013498980316301035},
+		{"нулевая ошибка, выше границы", 0.03, 0.02, 0, 0},
+		{"нулевая ошибка, ниже границы", 0.01, 0.02, 0, 1},
+	}
+	for _, tt := range tests {
+		almostEqual(t, tt.name, MarginExceedancePValue(tt.estimate, tt.margin, tt.se), tt.want, 1e-12)
+	}
+}
+
+func TestMarginExceedanceSequentialPValue(t *testing.T) {
+	// эталон: 1 / Λ, Λ = 2 sqrt(V / (V + τ²)) · exp(τ² x² / (2V(V + τ²))) · Φ(m / s)
+	tests := []struct {
+		name                           string
+		estimate, margin, se, mixingSD float64
+		prevPValue, want               float64
+	}{
+		{"превышение", 0.05, 0.02, 0.01, 0.02, 1, 0.03066061382227687},
+		{"на границе", 0.02, 0.02, 0.01, 0.02, 1, 1},
+		{"ниже границы", 0.01, 0.02, 0.01, 0.02, 1, 1},
+		// p-значение не растет: меньшее значение прошлой проверки сохраняется
+		{"прошлая проверка меньше", 0.05, 0.02, 0.01, 0.02, 0.01, 0.01},
+		{"нулевая ошибка, выше границы", 0.03, 0.02, 0, 0.02, 1, 0},
+		{"нулевая ошибка, ниже границы", 0.01, 0.02, 0, 0.02, 0.4, 0.4},
+	}
+	for _, tt := range tests {
+		got := MarginExceedanceSequentialPValue(tt.estimate, tt.margin, tt.se, tt.mixingSD, tt.prevPValue)
+		almostEqual(t, tt.name, got, tt.want, 1e-9)
+	}
+
+	// последовательное p-значение консервативнее однократного
+	single := MarginExceedancePValue(0.05, 0.02, 0.01)
+	if sequential := MarginExceedanceSequentialPValue(0.05, 0.02, 0.01, 0.02, 1); sequential <= single {
+		t.Errorf("последовательное p-значение %g должно быть больше однократного %g", sequential, single)
+	}
+}
+
+func TestMarginExceedanceSequentialFalseStopRate(t *testing.T) {
+	// ухудшение ровно на границе: остановка хотя бы на одной из многих проверок случается не чаще alpha,
+	// тогда как однократный тест на каждой проверке ошибается заметно чаще
+	const (
+		experiments = 400
+		looks       = 30
+		perLook     = 100
+		margin      = 0.05
+		alpha       = 0.05
+	)
+	rng := rand.New(rand.NewPCG(3, 5))
+	var stopped, naive int
+	for range experiments {
+		var sum float64
+		var n int
+		p := 1.0
+		naiveStopped := false
+		for range looks {
+			for range perLook {
+				sum += margin + rng.NormFloat64()
+			}
+			n += perLook
+			estimate, se := sum/float64(n), 1/math.Sqrt(float64(n))
+			if !naiveStopped && MarginExceedancePValue(estimate, margin, se) <= alpha {
+				naiveStopped = true
+				naive++
+			}
+			p = MarginExceedanceSequentialPValue(estimate, margin, se, 0.1, p)
+		}
+		if p <= alpha {
+			stopped++
+		}
+	}
+	if share := float64(stopped) / experiments; share > alpha {
+		t.Errorf("доля ложных остановок %.3f больше alpha = %.2f", share, alpha)
+	}
+	if share := float64(naive) / experiments; share <= 2*alpha {
+		t.Errorf("доля ложных остановок однократного теста %.3f, ожидается заметно больше alpha", share)
+	}
+}
@@ -0,0 +1,181 @@
+package stats
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// Summary описательная статистика выборки
+type Summary struct {
+	N        int
+	Mean     float64
+	Variance float64 // несмещенная выборочная дисперсия
+	StdDev   float64
+	Median   float64
+}
+
+// Summarize рассчитывает описательную статистику выборки
+func Summarize(values []float64) Summary {
+	s := Summary{N: len(values)}
+	if s.N == 0 {
+		return s
+	}
+
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	s.Mean = sum / float64(s.N)
+
+	if s.N > 1 {
+		var sq float64
+		for _, v := range values {
+			sq += (v - s.Mean) * (v - s.Mean)
+		}
+		s.Variance = sq / float64(s.N-1)
+		s.StdDev = math.Sqrt(s.Variance)
+	}
+
+	s.Median = Median(values)
+	return s
+}
+
+// Median возвращает медиану выборки (исходный срез не изменяется)
+func Median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+// WelchTest результат t-теста Уэлча для разности средних
+type WelchTest struct {
+	MeanDiff      float64 // разность средних (тест - контроль)
+	StandardError float64
+	TStatistic    float64
+	DF            float64 // степени свободы по формуле Уэлча–Саттертуэйта
+	PValue        float64 // двустороннее p-значение
+	Confidence    float64
+	CILower       float64
+	CIUpper       float64
+}
+
+// WelchTTest сравнивает средние выборок control и treatment без предположения о равенстве дисперсий
+func WelchTTest(control, treatment []float64, confidence float64) (WelchTest, error) {
+	if len(control) < 2 || len(treatment) < 2 {
+		return WelchTest{}, errors.New("в каждой группе должно быть хотя бы два наблюдения")
+	}
+	if confidence <= 0 || confidence >= 1 {
+		return WelchTest{}, errors.New("уровень доверия должен быть в интервале (0, 1)")
+	}
+
+	a := Summarize(control)
+	b := Summarize(treatment)
+	va := a.Variance / float64(a.N)
+	vb := b.Variance / float64(b.N)
+
+	res := WelchTest{
+		MeanDiff:      b.Mean - a.Mean,
+		StandardError: math.Sqrt(va + vb),
+		Confidence:    confidence,
+		PValue:        1,
+	}
+	if res.StandardError == 0 {
+		// обе выборки постоянны: интервал вырождается в точку
+		res.CILower, res.CIUpper = res.MeanDiff, res.MeanDiff
+		if res.MeanDiff != 0 {
+			res.PValue = 0
+		}
+		return res, nil
+	}
+
+	res.DF = (va + vb) * (va + vb) /
+		(va*va/float64(a.N-1) + vb*vb/float64(b.N-1))
+	res.TStatistic = res.MeanDiff / res.StandardError
+	res.PValue = 2 * (1 - StudentTCDF(math.Abs(res.TStatistic), res.DF))
+
+	t := StudentTQuantile(1-(1-confidence)/2, res.DF)
+	res.CILower = res.MeanDiff - t*res.StandardError
+	res.CIUpper = res.MeanDiff + t*res.StandardError
+	return res, nil
+}
+
+// MannWhitneyTest результат U-теста Манна–Уитни
+type MannWhitneyTest struct {
+	U                      float64 // U-статистика тестовой выборки
+	ZScore                 float64 // нормальное приближение с поправкой на связки
+	PValue                 float64 // двустороннее p-значение
+	ProbabilitySuperiority float64 // P(X_тест > X_контроль) + 0.5·P(X_тест = X_контроль)
+}
+
+// MannWhitneyU сравнивает распределения выборок control и treatment ранговым критерием
+func MannWhitneyU(control, treatment []float64) (MannWhitneyTest, error) {
+	nA, nB := len(control), len(treatment)
+	if nA == 0 || nB == 0 {
+		return MannWhitneyTest{}, errors.New("в каждой группе должно быть хотя бы одно наблюдение")
+	}
+
+	type observation struct {
+		value     float64
+		treatment bool
+	}
+	all := make([]observation, 0, nA+nB)
+	for _, v := range control {
+		all = append(all, observation{value: v})
+	}
+	for _, v := range treatment {
+		all = append(all, observation{value: v, treatment: true})
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].value < all[j].value })
+
+	// ранжирование со средними рангами для связок
+	n := float64(nA + nB)
+	var rankSumB, tieCorrection float64
+	for i := 0; i < len(all); {
+		j := i
+		for j < len(all) && all[j].value == all[i].value {
+			j++
+		}
+		rank := float64(i+j+1) / 2
+		for k := i; k < j; k++ {
+			if all[k].treatment {
+				rankSumB += rank
+			}
+		}
+		t := float64(j - i)
+		tieCorrection += t*t*t - t
+		i = j
+	}
+
+	res := MannWhitneyTest{PValue: 1}
+	fa, fb := float64(nA), float64(nB)
+	res.U = rankSumB - fb*(fb+1)/2
+	res.ProbabilitySuperiority = res.U / (fa * fb)
+
+	mean := fa * fb / 2
+	variance := fa * fb / 12 * ((n + 1) - tieCorrection/(n*(n-1)))
+	if variance <= 0 {
+		return res, nil
+	}
+
+	// поправка на непрерывность
+	diff := res.U - mean
+	switch {
+	case diff > 0.5:
+		diff -= 0.5
+	case diff < -0.5:
+		diff += 0.5
+	default:
+		diff = 0
+	}
+	res.ZScore = diff / math.Sqrt(variance)
+	res.PValue = TwoSidedPValue(res.ZScore)
+	return res, nil
+}
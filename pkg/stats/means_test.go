@@ -0,0 +1,104 @@
+package stats
+
+import "testing"
+
+// данные sleep из R: прирост сна в двух группах
+var (
+	sleepGroup1 = []float64{0.7, -1.6, -0.2, -1.2, -0.1, 3.4, 3.7, 0.8, 0.0, 2.0}
+	sleepGroup2 = []float64{1.9, 0.8, 1.1, 0.1, -0.1, 4.4, 5.5, 1.6, 4.6, 3.4}
+)
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		name           string
+		values         []float64
+		mean, variance float64
+		median         float64
+	}{
+		{"пустая выборка", nil, 0, 0, 0},
+		{"одно наблюдение", []float64{4}, 4, 0, 4},
+		{"нечетное число", []float64{5, 1, 3}, 3, 4, 3},
+		{"четное число", []float64{4, 1, 3, 2}, 2.5, 5.0 / 3, 2.5},
+		{"sleep, группа 1", sleepGroup1, 0.75, 3.200555555555556, 0.35},
+	}
+	for _, tt := range tests {
+		s := Summarize(tt.values)
+		if s.N != len(tt.values) {
+			t.Errorf("%s: N = %d, ожидается %d", tt.name, s.N, len(tt.values))
+		}
+		almostEqual(t, tt.name+": Mean", s.Mean, tt.mean, 1e-12)
+		almostEqual(t, tt.name+": Variance", s.Variance, tt.variance, 1e-12)
+		almostEqual(t, tt.name+": Median", s.Median, tt.median, 1e-12)
+	}
+}
+
+func TestStudentT(t *testing.T) {
+	// эталонные значения: pt / qt в R
+	almostEqual(t, "pt(2, 5)", StudentTCDF(2, 5), 0.9490302605850673, 1e-9)
+	almostEqual(t, "pt(-2, 5)", StudentTCDF(-2, 5), 1-0.9490302605850673, 1e-9)
+	almostEqual(t, "pt(1.5, 17.5)", StudentTCDF(1.5, 17.5), 0.9242802200080338, 1e-9)
+	almostEqual(t, "pt(0, 3)", StudentTCDF(0, 3), 0.5, 1e-12)
+	almostEqual(t, "qt(0.975, 10)", StudentTQuantile(0.975, 10), 2.228138851986273, 1e-8)
+	almostEqual(t, "qt(0.95, 1)", StudentTQuantile(0.95, 1), 6.313751514675041, 1e-8)
+}
+
+func TestWelchTTest(t *testing.T) {
+	// эталон: t.test(extra ~ group, data = sleep) в R —
+	// t = -1.8608, df = 17.776, p-value = 0.07939, 95% CI [-3.3654832; 0.2054832] для разности group1 - group2
+	res, err := WelchTTest(sleepGroup1, sleepGroup2, 0.95)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	almostEqual(t, "MeanDiff", res.MeanDiff, 1.58, 1e-12)
+	almostEqual(t, "TStatistic", res.TStatistic, 1.8608134674868526, 1e-9)
+	almostEqual(t, "DF", res.DF, 17.776473516178495, 1e-9)
+	almostEqual(t, "PValue", res.PValue, 0.07939414018735613, 1e-7)
+	almostEqual(t, "CILower", res.CILower, -0.2054832, 1e-6)
+	almostEqual(t, "CIUpper", res.CIUpper, 3.3654832, 1e-6)
+
+	// постоянные выборки: интервал вырождается в точку
+	res, err = WelchTTest([]float64{1, 1}, []float64{2, 2}, 0.95)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if res.PValue != 0 || res.CILower != 1 || res.CIUpper != 1 {
+		t.Errorf("для постоянных выборок ожидается p = 0 и интервал [1; 1], получено p = %g, [%g; %g]",
+			res.PValue, res.CILower, res.CIUpper)
+	}
+
+	if _, err := WelchTTest([]float64{1}, []float64{1, 2}, 0.95); err == nil {
+		t.Error("для группы из одного наблюдения ожидается ошибка")
+	}
+}
+
+func TestMannWhitneyU(t *testing.T) {
+	tests := []struct {
+		name                 string
+		control, treatment   []float64
+		u, z, p, superiority float64
+	}{
+		// эталон: wilcox.test(extra ~ group, data = sleep) в R — W = 25.5 для group1, p-value = 0.06933
+		// (нормальное приближение с поправкой на связки и непрерывность)
+		{"связки", sleepGroup1, sleepGroup2, 74.5, 1.8162790619136817, 0.06932757543362662, 0.745},
+		// полное разделение без связок: wilcox.test(1:5, 6:10, exact = FALSE) — p-value = 0.01219
+		{"без связок", []float64{1, 2, 3, 4, 5}, []float64{6, 7, 8, 9, 10},
+			25, 2.5067182457620487, 0.012185780355344818, 1},
+		{"одинаковые выборки", []float64{1, 2, 3}, []float64{1, 2, 3}, 4.5, 0, 1, 0.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := MannWhitneyU(tt.control, tt.treatment)
+			if err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+			almostEqual(t, "U", res.U, tt.u, 1e-12)
+			almostEqual(t, "ZScore", res.ZScore, tt.z, 1e-9)
+			almostEqual(t, "PValue", res.PValue, tt.p, 1e-8)
+			almostEqual(t, "ProbabilitySuperiority", res.ProbabilitySuperiority, tt.superiority, 1e-12)
+		})
+	}
+
+	if _, err := MannWhitneyU(nil, []float64{1}); err == nil {
+		t.Error("для пустой группы ожидается ошибка")
+	}
+}
@@ -0,0 +1,39 @@
+package stats
+
+import "sort"
+
+// Bonferroni возвращает p-значения с поправкой Бонферрони (контроль FWER):
+// каждое p-значение умножается на число проверок и ограничивается единицей
+func Bonferroni(pValues []float64) []float64 {
+	m := float64(len(pValues))
+	adjusted := make([]float64, len(pValues))
+	for i, p := range pValues {
+		adjusted[i] = min(p*m, 1)
+	}
+	return adjusted
+}
+
+// BenjaminiHochberg возвращает скорректированные p-значения (q-значения) по процедуре
+// Бенджамини–Хохберга (контроль FDR). Порядок результата совпадает с порядком входа
+func BenjaminiHochberg(pValues []float64) []float64 {
+	m := len(pValues)
+	adjusted := make([]float64, m)
+	if m == 0 {
+		return adjusted
+	}
+
+	order := make([]int, m)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return pValues[order[a]] < pValues[order[b]] })
+
+	// проход от наибольшего p-значения: q_(i) = min(q_(i+1), p_(i) * m / i)
+	running := 1.0
+	for rank := m; rank >= 1; rank-- {
+		idx := order[rank-1]
+		running = min(running, pValues[idx]*float64(m)/float64(rank))
+		adjusted[idx] = running
+	}
+	return adjusted
+}
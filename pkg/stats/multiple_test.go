@@ -0,0 +1,33 @@
+package stats
+
+import "testing"
+
+func TestMultipleTestingCorrections(t *testing.T) {
+	// эталон: p.adjust(p, "bonferroni") и p.adjust(p, "BH") в R
+	tests := []struct {
+		name           string
+		pValues        []float64
+		bonferroni, bh []float64
+	}{
+		{"пусто", nil, []float64{}, []float64{}},
+		{"одна проверка", []float64{0.03}, []float64{0.03}, []float64{0.03}},
+		// порядок входа сохраняется, q-значения монотонны по рангу
+		{"пять проверок", []float64{0.01, 0.04, 0.03, 0.005, 0.2},
+			[]float64{0.05, 0.2, 0.15, 0.025, 1}, []float64{0.025, 0.05, 0.05, 0.025, 0.2}},
+		{"одинаковые p-значения", []float64{0.02, 0.02, 0.5},
+			[]float64{0.06, 0.06, 1}, []float64{0.03, 0.03, 0.5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bonferroni := Bonferroni(tt.pValues)
+			bh := BenjaminiHochberg(tt.pValues)
+			if len(bonferroni) != len(tt.pValues) || len(bh) != len(tt.pValues) {
+				t.Fatalf("длины результатов %d и %d, ожидается %d", len(bonferroni), len(bh), len(tt.pValues))
+			}
+			for i := range tt.pValues {
+				almostEqual(t, "Bonferroni", bonferroni[i], tt.bonferroni[i], 1e-12)
+				almostEqual(t, "BenjaminiHochberg", bh[i], tt.bh[i], 1e-12)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package stats
+
+import (
+	"errors"
+	"math"
+)
+
+// SampleSizeTwoProportions возвращает размер каждой группы, необходимый для обнаружения
+// относительного изменения доли relativeMDE от базового уровня baseline
+// двусторонним z-тестом с уровнем значимости alpha и мощностью power
+func SampleSizeTwoProportions(baseline, relativeMDE, alpha, power float64) (int, error) {
+	if baseline <= 0 || baseline >= 1 {
+		return 0, errors.New("базовая доля должна быть в интервале (0, 1)")
+	}
+	if relativeMDE == 0 {
+		return 0, errors.New("минимальный обнаруживаемый эффект не может быть нулевым")
+	}
+	if alpha <= 0 || alpha >= 1 {
+		return 0, errors.New("уровень значимости должен быть в интервале (0, 1)")
+	}
+	if power <= 0 || power >= 1 {
+		return 0, errors.New("мощность должна быть в интервале (0, 1)")
+	}
+
+	p1 := baseline
+	p2 := baseline * (1 + relativeMDE)
+	if p2 <= 0 || p2 >= 1 {
+		return 0, errors.New("ожидаемая доля в тестовой группе выходит за интервал (0, 1)")
+	}
+
+	zAlpha := NormalQuantile(1 - alpha/2)
+	zBeta := NormalQuantile(power)
+	pBar := (p1 + p2) / 2
+	numerator := zAlpha*math.Sqrt(2*pBar*(1-pBar)) + zBeta*math.Sqrt(p1*(1-p1)+p2*(1-p2))
+	n := numerator * numerator / ((p2 - p1) * (p2 - p1))
+	return int(math.Ceil(n)), nil
+}
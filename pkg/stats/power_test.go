@@ -0,0 +1,66 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+// proportionPower мощность двустороннего z-теста для долей p1 и p2 при n наблюдениях в группе
+// (та же формула, что у power.prop.test в R)
+func proportionPower(p1, p2, alpha float64, n int) float64 {
+	pBar := (p1 + p2) / 2
+	zAlpha := NormalQuantile(1 - alpha/2)
+	return NormalCDF((math.Sqrt(float64(n))*math.Abs(p2-p1) - zAlpha*math.Sqrt(2*pBar*(1-pBar))) /
+		math.Sqrt(p1*(1-p1)+p2*(1-p2)))
+}
+
+func TestSampleSizeTwoProportions(t *testing.T) {
+	tests := []struct {
+		name                        string
+		baseline, mde, alpha, power float64
+		want                        int
+	}{
+		// эталон: power.prop.test(p1 = 0.5, p2 = 0.75, power = 0.9) в R — n = 76.7
+		{"пример из R", 0.5, 0.5, 0.05, 0.9, 77},
+		{"CTR 10%, +10%", 0.1, 0.1, 0.05, 0.8, 14751},
+		{"снижение", 0.2, -0.25, 0.01, 0.9, 1717},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := SampleSizeTwoProportions(tt.baseline, tt.mde, tt.alpha, tt.power)
+			if err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+			if n != tt.want {
+				t.Errorf("n = %d, ожидается %d", n, tt.want)
+			}
+
+			// обратная проверка: n дает нужную мощность, а n - 1 уже нет
+			p2 := tt.baseline * (1 + tt.mde)
+			if got := proportionPower(tt.baseline, p2, tt.alpha, n); got < tt.power {
+				t.Errorf("мощность при n = %d равна %.5f, ожидается не меньше %.2f", n, got, tt.power)
+			}
+			if got := proportionPower(tt.baseline, p2, tt.alpha, n-1); got >= tt.power {
+				t.Errorf("мощность при n = %d равна %.5f, ожидается меньше %.2f", n-1, got, tt.power)
+			}
+		})
+	}
+}
+
+func TestSampleSizeTwoProportionsErrors(t *testing.T) {
+	tests := []struct {
+		name                        string
+		baseline, mde, alpha, power float64
+	}{
+		{"базовая доля 0", 0, 0.1, 0.05, 0.8},
+		{"нулевой эффект", 0.1, 0, 0.05, 0.8},
+		{"alpha 1", 0.1, 0.1, 1, 0.8},
+		{"мощность 0", 0.1, 0.1, 0.05, 0},
+		{"доля теста больше 1", 0.6, 1, 0.05, 0.8},
+	}
+	for _, tt := range tests {
+		if _, err := SampleSizeTwoProportions(tt.baseline, tt.mde, tt.alpha, tt.power); err == nil {
+			t.Errorf("%s: ожидается ошибка", tt.name)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package stats
+
+import (
+	"errors"
+	"math"
+)
+
+// ProportionTest результат z-теста для разности двух долей
+type ProportionTest struct {
+	ControlRate   float64 // доля успехов в контрольной группе
+	TreatmentRate float64 // доля успехов в тестовой группе
+	AbsoluteLift  float64 // разность долей (тест - контроль)
+	RelativeLift  float64 // относительный прирост к контролю
+	StandardError float64 // стандартная ошибка разности (без объединения групп)
+	ZScore        float64 // z-статистика (с объединенной оценкой доли)
+	PValue        float64 // двустороннее p-значение
+	Confidence    float64 // уровень доверия интервала
+	CILower       float64 // нижняя граница доверительного интервала разности
+	CIUpper       float64 // верхняя граница доверительного интервала разности
+}
+
+// TwoProportionZTest сравнивает доли successesB/nB и successesA/nA
+// группа A считается контрольной, confidence задает уровень доверительного интервала
+func TwoProportionZTest(successesA, nA, successesB, nB int, confidence float64) (ProportionTest, error) {
+	if nA <= 0 || nB <= 0 {
+		return ProportionTest{}, errors.New("в каждой группе должно быть хотя бы одно наблюдение")
+	}
+	if successesA < 0 || successesA > nA || successesB < 0 || successesB > nB {
+		return ProportionTest{}, errors.New("число успехов должно быть от 0 до размера группы")
+	}
+	if confidence <= 0 || confidence >= 1 {
+		return ProportionTest{}, errors.New("уровень доверия должен быть в интервале (0, 1)")
+	}
+
+	pA := float64(successesA) / float64(nA)
+	pB := float64(successesB) / float64(nB)
+
+	res := ProportionTest{
+		ControlRate:   pA,
+		TreatmentRate: pB,
+		AbsoluteLift:  pB - pA,
+		Confidence:    confidence,
+		PValue:        1,
+	}
+	if pA > 0 {
+		res.RelativeLift = (pB - pA) / pA
+	}
+
+	// стандартная ошибка для доверительного интервала
+	res.StandardError = math.Sqrt(pA*(1-pA)/float64(nA) + pB*(1-pB)/float64(nB))
+	z := NormalQuantile(1 - (1-confidence)/2)
+	res.CILower = res.AbsoluteLift - z*res.StandardError
+	res.CIUpper = res.AbsoluteLift + z*res.StandardError
+
+	// объединенная оценка доли для проверки гипотезы о равенстве
+	pooled := float64(successesA+successesB) / float64(nA+nB)
+	pooledSE := math.Sqrt(pooled * (1 - pooled) * (1/float64(nA) + 1/float64(nB)))
+	if pooledSE > 0 {
+		res.ZScore = res.AbsoluteLift / pooledSE
+		res.PValue = TwoSidedPValue(res.ZScore)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,83 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+// almostEqual сравнивает числа с абсолютной точностью tol
+func almostEqual(t *testing.T, name string, got, want, tol float64) {
+	t.Helper()
+	if math.Abs(got-want) > tol || math.IsNaN(got) != math.IsNaN(want) {
+		t.Errorf("%s = %.10g, ожидается %.10g (±%g)", name, got, want, tol)
+	}
+}
+
+func TestNormalDistribution(t *testing.T) {
+	// эталонные значения: pnorm / qnorm в R
+	tests := []struct {
+		x, cdf float64
+	}{
+		{0, 0.5},
+		{1, 0.8413447460685429},
+		{1.959963984540054, 0.975},
+		{-2.326347874040841, 0.01},
+		{3, 0.9986501019683699},
+	}
+	for _, tt := range tests {
+		almostEqual(t, "NormalCDF", NormalCDF(tt.x), tt.cdf, 1e-12)
+		almostEqual(t, "NormalQuantile", NormalQuantile(tt.cdf), tt.x, 1e-8)
+	}
+
+	if !math.IsInf(NormalQuantile(0), -1) || !math.IsInf(NormalQuantile(1), 1) {
+		t.Error("квантили уровней 0 и 1 должны быть бесконечными")
+	}
+	almostEqual(t, "TwoSidedPValue", TwoSidedPValue(-1.959963984540054), 0.05, 1e-12)
+}
+
+func TestTwoProportionZTest(t *testing.T) {
+	// эталон: prop.test(c(250, 200), c(1000, 1000), correct = FALSE) в R — X-squared = z² = 7.1685, p = 0.007420
+	tests := []struct {
+		name                   string
+		sA, nA, sB, nB         int
+		confidence             float64
+		z, p, se, lower, upper float64
+	}{
+		{"значимое различие", 200, 1000, 250, 1000, 0.95,
+			2.677397763008329, 0.007419649261025693, 0.01864135188230725, 0.01346362168753984, 0.08653637831246014},
+		{"одинаковые доли", 50, 500, 100, 1000, 0.95, 0, 1, 0.016431676725154984, -0.03220549458690883, 0.03220549458690883},
+		{"ухудшение", 120, 800, 90, 800, 0.9,
+			-2.221079985075453, 0.026345544407226022, 0.016857629949076473, -0.06522833376354419, -0.009771666236455794},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := TwoProportionZTest(tt.sA, tt.nA, tt.sB, tt.nB, tt.confidence)
+			if err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+			almostEqual(t, "ZScore", res.ZScore, tt.z, 1e-9)
+			almostEqual(t, "PValue", res.PValue, tt.p, 1e-9)
+			almostEqual(t, "StandardError", res.StandardError, tt.se, 1e-12)
+			almostEqual(t, "CILower", res.CILower, tt.lower, 1e-7)
+			almostEqual(t, "CIUpper", res.CIUpper, tt.upper, 1e-7)
+		})
+	}
+}
+
+func TestTwoProportionZTestErrors(t *testing.T) {
+	tests := []struct {
+		name           string
+		sA, nA, sB, nB int
+		confidence     float64
+	}{
+		{"пустая группа", 0, 0, 1, 10, 0.95},
+		{"успехов больше испытаний", 11, 10, 1, 10, 0.95},
+		{"отрицательное число успехов", -1, 10, 1, 10, 0.95},
+		{"уровень доверия 1", 1, 10, 1, 10, 1},
+	}
+	for _, tt := range tests {
+		if _, err := TwoProportionZTest(tt.sA, tt.nA, tt.sB, tt.nB, tt.confidence); err == nil {
+			t.Errorf("%s: ожидается ошибка", tt.name)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package stats
+
+import (
+	"errors"
+	"math"
+)
+
+// RatioTest результат сравнения метрик-отношений двух групп дельта-методом
+type RatioTest struct {
+	ControlRatio   float64 // сумма числителей / сумма знаменателей в контроле
+	TreatmentRatio float64
+	Difference     float64 // TreatmentRatio - ControlRatio
+	StandardError  float64
+	ZScore         float64
+	PValue         float64
+	Confidence     float64
+	CILower        float64
+	CIUpper        float64
+}
+
+// ratioEstimate оценка отношения и ее дисперсии по наблюдениям пользователей
+func ratioEstimate(numerators, denominators []float64) (ratio, variance float64, err error) {
+	n := len(numerators)
+	if n != len(denominators) {
+		return 0, 0, errors.New("длины числителей и знаменателей не совпадают")
+	}
+	if n < 2 {
+		return 0, 0, errors.New("в каждой группе должно быть хотя бы два наблюдения")
+	}
+
+	num := Summarize(numerators)
+	den := Summarize(denominators)
+	if den.Mean == 0 {
+		return 0, 0, errors.New("сумма знаменателей равна нулю")
+	}
+
+	var cov float64
+	for i := range numerators {
+		cov += (numerators[i] - num.Mean) * (denominators[i] - den.Mean)
+	}
+	cov /= float64(n - 1)
+
+	ratio = num.Mean / den.Mean
+	variance = (num.Variance - 2*ratio*cov + ratio*ratio*den.Variance) / (float64(n) * den.Mean * den.Mean)
+	return ratio, math.Max(variance, 0), nil
+}
+
+// RatioDeltaTest сравнивает отношения сумм числителей и знаменателей по пользователям
+// (например, оценок на оцененную рекомендацию). Дисперсия отношения учитывает
+// зависимость наблюдений одного пользователя и оценивается дельта-методом
+func RatioDeltaTest(controlNum, controlDen, treatmentNum, treatmentDen []float64, confidence float64) (RatioTest, error) {
+	if confidence <= 0 || confidence >= 1 {
+		return RatioTest{}, errors.New("уровень доверия должен быть в интервале (0, 1)")
+	}
+	ratioA, varA, err := ratioEstimate(controlNum, controlDen)
+	if err != nil {
+		return RatioTest{}, err
+	}
+	ratioB, varB, err := ratioEstimate(treatmentNum, treatmentDen)
+	if err != nil {
+		return RatioTest{}, err
+	}
+
+	res := RatioTest{
+		ControlRatio:   ratioA,
+		TreatmentRatio: ratioB,
+		Difference:     ratioB - ratioA,
+		StandardError:  math.Sqrt(varA + varB),
+		Confidence:     confidence,
+		PValue:         1,
+	}
+	if res.StandardError == 0 {
+		res.CILower, res.CIUpper = res.Difference, res.Difference
+		if res.Difference != 0 {
+			res.PValue = 0
+		}
+		return res, nil
+	}
+
+	res.ZScore = res.Difference / res.StandardError
+	res.PValue = TwoSidedPValue(res.ZScore)
+	z := NormalQuantile(1 - (1-confidence)/2)
+	res.CILower = res.Difference - z*res.StandardError
+	res.CIUpper = res.Difference + z*res.StandardError
+	return res, nil
+}
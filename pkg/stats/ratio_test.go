@@ -0,0 +1,68 @@
+package stats
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRatioDeltaTest(t *testing.T) {
+	// эталон: дисперсия отношения средних дельта-методом с градиентом (1/μy, -μx/μy²),
+	// посчитанная отдельно от реализации: (σx² / μy² - 2μx·cov / μy³ + μx²·σy² / μy⁴) / n
+	res, err := RatioDeltaTest(
+		[]float64{2, 3, 0, 5, 1, 4}, []float64{4, 5, 2, 6, 3, 5},
+		[]float64{3, 4, 1, 6, 2, 5, 4}, []float64{4, 5, 3, 6, 3, 6, 5}, 0.95)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	almostEqual(t, "ControlRatio", res.ControlRatio, 0.6, 1e-12)
+	almostEqual(t, "TreatmentRatio", res.TreatmentRatio, 0.78125, 1e-12)
+	almostEqual(t, "Difference", res.Difference, 0.18125, 1e-12)
+	almostEqual(t, "StandardError", res.StandardError, 0.11953080899292287, 1e-12)
+	almostEqual(t, "ZScore", res.ZScore, 1.5163454637936191, 1e-9)
+	almostEqual(t, "PValue", res.PValue, 0.12943202130817344, 1e-7)
+	almostEqual(t, "CILower", res.CILower, -0.05302608066906506, 1e-7)
+	almostEqual(t, "CIUpper", res.CIUpper, 0.4155260806690653, 1e-7)
+}
+
+func TestRatioDeltaTestUnitDenominators(t *testing.T) {
+	// при единичных знаменателях отношение — среднее, а ошибка совпадает с ошибкой t-теста Уэлча:
+	// t.test(extra ~ group, data = sleep) в R дает разность средних 1.58 и t = -1.8608
+	ones := []float64{1, 1, 1, 1, 1, 1, 1, 1, 1, 1}
+	res, err := RatioDeltaTest(sleepGroup1, ones, sleepGroup2, ones, 0.95)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	almostEqual(t, "Difference", res.Difference, 1.58, 1e-12)
+	almostEqual(t, "StandardError", res.StandardError, math.Sqrt((3.200555555555556+4.009)/10), 1e-12)
+	almostEqual(t, "ZScore", res.ZScore, 1.8608, 1e-4)
+}
+
+func TestRatioDeltaTestDegenerate(t *testing.T) {
+	// одинаковые отношения у всех пользователей: дисперсия нулевая, разность точная
+	res, err := RatioDeltaTest([]float64{1, 2}, []float64{2, 4}, []float64{3, 6}, []float64{4, 8}, 0.95)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if res.StandardError != 0 || res.PValue != 0 || res.CILower != 0.25 || res.CIUpper != 0.25 {
+		t.Errorf("ошибка %g, p = %g, интервал [%g; %g], ожидается 0, 0, [0.25; 0.25]",
+			res.StandardError, res.PValue, res.CILower, res.CIUpper)
+	}
+}
+
+func TestRatioDeltaTestErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		num, den   []float64
+		confidence float64
+	}{
+		{"уровень доверия", []float64{1, 2}, []float64{1, 1}, 1},
+		{"разная длина", []float64{1, 2}, []float64{1}, 0.95},
+		{"одно наблюдение", []float64{1}, []float64{1}, 0.95},
+		{"нулевые знаменатели", []float64{1, 2}, []float64{0, 0}, 0.95},
+	}
+	for _, tt := range tests {
+		if _, err := RatioDeltaTest([]float64{1, 2}, []float64{1, 1}, tt.num, tt.den, tt.confidence); err == nil {
+			t.Errorf("%s: ожидается ошибка", tt.name)
+		}
+	}
+}
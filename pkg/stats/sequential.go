@@ -0,0 +1,57 @@
+package stats
+
+import (
+	"errors"
+	"math"
+)
+
+// MSPRTResult результат одного просмотра в смешанном последовательном тесте отношения правдоподобия (mSPRT)
+type MSPRTResult struct {
+	Estimate        float64 // разность долей (тест - контроль)
+	Variance        float64 // дисперсия оценки разности
+	ZScore          float64
+	LikelihoodRatio float64 // смешанное отношение правдоподобия Λ
+	ZBoundary       float64 // граница |z|, при пересечении которой Λ ≥ 1/α
+	PValue          float64 // всегда валидное p-значение
+	Reject          bool    // гипотеза о равенстве отвергается, эксперимент можно останавливать
+}
+
+// MSPRT выполняет очередной просмотр последовательного теста для разности двух долей с нормальным
+// смешивающим распределением N(0, mixingSD²); prevPValue — всегда валидное p-значение предыдущего
+// просмотра (1, если просмотров не было). Результат остается валидным при любом числе просмотров
+func MSPRT(successesA, nA, successesB, nB int, alpha, mixingSD, prevPValue float64) (MSPRTResult, error) {
+	if alpha <= 0 || alpha >= 1 {
+		return MSPRTResult{}, errors.New("уровень значимости должен быть в интервале (0, 1)")
+	}
+	if mixingSD <= 0 {
+		return MSPRTResult{}, errors.New("параметр смешивания должен быть положительным")
+	}
+	if successesA < 0 || successesA > nA || successesB < 0 || successesB > nB {
+		return MSPRTResult{}, errors.New("число успехов должно быть от 0 до размера группы")
+	}
+
+	res := MSPRTResult{LikelihoodRatio: 1, PValue: math.Min(prevPValue, 1)}
+	if nA == 0 || nB == 0 {
+		res.Reject = res.PValue <= alpha
+		return res, nil
+	}
+
+	pA := float64(successesA) / float64(nA)
+	pB := float64(successesB) / float64(nB)
+	res.Estimate = pB - pA
+	res.Variance = pA*(1-pA)/float64(nA) + pB*(1-pB)/float64(nB)
+	if res.Variance == 0 {
+		res.Reject = res.PValue <= alpha
+		return res, nil
+	}
+
+	tau2 := mixingSD * mixingSD
+	v := res.Variance
+	res.ZScore = res.Estimate / math.Sqrt(v)
+	res.LikelihoodRatio = math.Sqrt(v/(v+tau2)) * math.Exp(tau2*res.Estimate*res.Estimate/(2*v*(v+tau2)))
+	res.ZBoundary = math.Sqrt((v + tau2) / tau2 * (2*math.Log(1/alpha) + math.Log((v+tau2)/v)))
+
+	res.PValue = math.Min(res.PValue, 1/res.LikelihoodRatio)
+	res.Reject = res.PValue <= alpha
+	return res, nil
+}
@@ -0,0 +1,110 @@
+package stats
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+func TestMSPRT(t *testing.T) {
+	// эталон: Λ = sqrt(V / (V + τ²)) · exp(τ² d² / (2V(V + τ²))), граница |z| из условия Λ = 1/α
+	tests := []struct {
+		name                  string
+		sA, nA, sB, nB        int
+		mixingSD, prevPValue  float64
+		estimate, z, lr, zMax float64
+		pValue                float64
+		reject                bool
+	}{
+		{"слабый эффект", 100, 1000, 130, 1000, 0.01, 1,
+			0.03, 2.1050687930179994, 1.7003202665247306, 4.4015489640007806, 0.588124496124422, false},
+		{"сильный эффект", 100, 1000, 150, 1000, 0.03, 1,
+			0.05, 3.390317518104051, 45.16095861510221, 3.0775925905878907, 0.02214301978225926, true},
+		// p-значение не растет: меньшее значение прошлого просмотра сохраняется
+		{"прошлый просмотр меньше", 100, 1000, 130, 1000, 0.01, 0.2,
+			0.03, 2.1050687930179994, 1.7003202665247306, 4.4015489640007806, 0.2, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := MSPRT(tt.sA, tt.nA, tt.sB, tt.nB, 0.05, tt.mixingSD, tt.prevPValue)
+			if err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+			almostEqual(t, "Estimate", res.Estimate, tt.estimate, 1e-12)
+			almostEqual(t, "ZScore", res.ZScore, tt.z, 1e-9)
+			almostEqual(t, "LikelihoodRatio", res.LikelihoodRatio, tt.lr, 1e-9)
+			almostEqual(t, "ZBoundary", res.ZBoundary, tt.zMax, 1e-9)
+			almostEqual(t, "PValue", res.PValue, tt.pValue, 1e-12)
+			if res.Reject != tt.reject {
+				t.Errorf("Reject = %v, ожидается %v", res.Reject, tt.reject)
+			}
+		})
+	}
+}
+
+func TestMSPRTEmptyGroup(t *testing.T) {
+	// без наблюдений в группе сохраняется p-значение прошлого просмотра
+	res, err := MSPRT(0, 0, 5, 10, 0.05, 0.01, 0.03)
+	if err != nil {
+		t.Fatalf("неожиданная ошибка: %v", err)
+	}
+	if res.PValue != 0.03 || !res.Reject {
+		t.Errorf("PValue = %g, Reject = %v; ожидается 0.03 и остановка", res.PValue, res.Reject)
+	}
+}
+
+func TestMSPRTErrors(t *testing.T) {
+	tests := []struct {
+		name            string
+		sA, nA, sB, nB  int
+		alpha, mixingSD float64
+	}{
+		{"alpha 0", 1, 10, 1, 10, 0, 0.01},
+		{"нулевое смешивание", 1, 10, 1, 10, 0.05, 0},
+		{"успехов больше испытаний", 11, 10, 1, 10, 0.05, 0.01},
+	}
+	for _, tt := range tests {
+		if _, err := MSPRT(tt.sA, tt.nA, tt.sB, tt.nB, tt.alpha, tt.mixingSD, 1); err == nil {
+			t.Errorf("%s: ожидается ошибка", tt.name)
+		}
+	}
+}
+
+func TestMSPRTFalsePositiveRate(t *testing.T) {
+	// при равных долях остановка хотя бы на одном из многих просмотров случается не чаще alpha
+	const (
+		experiments = 400
+		looks       = 30
+		perLook     = 200
+		rate        = 0.1
+		alpha       = 0.05
+	)
+	rng := rand.New(rand.NewPCG(7, 11))
+	stopped := 0
+	for range experiments {
+		var sA, sB, n int
+		p := 1.0
+		for range looks {
+			for range perLook {
+				if rng.Float64() < rate {
+					sA++
+				}
+				if rng.Float64() < rate {
+					sB++
+				}
+			}
+			n += perLook
+			res, err := MSPRT(sA, n, sB, n, alpha, 0.01, p)
+			if err != nil {
+				t.Fatalf("неожиданная ошибка: %v", err)
+			}
+			p = res.PValue
+			if res.Reject {
+				stopped++
+				break
+			}
+		}
+	}
+	if share := float64(stopped) / experiments; share > alpha {
+		t.Errorf("доля ложных остановок %.3f больше alpha = %.2f", share, alpha)
+	}
+}
@@ -0,0 +1,288 @@
+package ui
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"testing-platform/db/models"
+	"testing-platform/pkg/logger"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+// AlgorithmsWindow окно управления реестром алгоритмов рекомендаций
+type AlgorithmsWindow struct {
+	window fyne.Window
+	mw     *MainWindow
+
+	algorithmList    *widget.List
+	nameEntry        *widget.Entry
+	descriptionEntry *widget.Entry
+	ownerEntry       *widget.Entry
+	versionEntry     *widget.Entry
+	deprecatedCheck  *widget.Check
+	statusLabel      *widget.Label
+
+	schemaEntry *widget.Entry
+
+	algorithms []models.Algorithm
+	selected   string // имя выбранного алгоритма, пусто — новый алгоритм
+}
+
+// NewAlgorithmsWindow создает окно реестра алгоритмов
+func NewAlgorithmsWindow(mw *MainWindow) *AlgorithmsWindow {
+	w := &AlgorithmsWindow{
+		window: fyne.CurrentApp().NewWindow("Реестр алгоритмов"),
+		mw:     mw,
+	}
+	w.window.Resize(fyne.NewSize(1000, 600))
+	w.buildUI()
+	w.loadAlgorithms()
+	return w
+}
+
+func (w *AlgorithmsWindow) buildUI() {
+	w.algorithmList = widget.NewList(
+		func() int { return len(w.algorithms) },
+		func() fyne.CanvasObject {
+			return container.NewVBox(
+				widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+				widget.NewLabel(""),
+			)
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			a := w.algorithms[i]
+			cont := o.(*fyne.Container)
+			title := fmt.Sprintf("%s (версия %s)", a.Name, a.Version)
+			if a.Deprecated {
+				title += " — устарел"
+			}
+			cont.Objects[0].(*widget.Label).SetText(title)
+			owner := a.Owner
+			if owner == "" {
+				owner = "владелец не указан"
+			}
+			cont.Objects[1].(*widget.Label).SetText(fmt.Sprintf("%s; %s", owner, a.Description))
+		},
+	)
+	w.algorithmList.OnSelected = w.onAlgorithmSelected
+
+	w.nameEntry = widget.NewEntry()
+	w.nameEntry.SetPlaceHolder("Системное имя (например: session_based)")
+	w.descriptionEntry = widget.NewMultiLineEntry()
+	w.descriptionEntry.SetPlaceHolder("Краткое описание алгоритма")
+	w.ownerEntry = widget.NewEntry()
+	w.ownerEntry.SetPlaceHolder("Команда или ответственный")
+	w.versionEntry = widget.NewEntry()
+	w.versionEntry.SetText("1")
+	w.deprecatedCheck = widget.NewCheck("Устарел (не назначается новым экспериментам)", nil)
+	w.schemaEntry = widget.NewMultiLineEntry()
+	w.schemaEntry.SetMinRowsVisible(6)
+	w.schemaEntry.TextStyle = fyne.TextStyle{Monospace: true}
+	w.schemaEntry.SetPlaceHolder(`{"type": "object", "properties": {"weight": {"type": "number", "minimum": 0, "maximum": 1}}}`)
+
+	w.statusLabel = widget.NewLabel("")
+	w.statusLabel.Wrapping = fyne.TextWrapWord
+
+	help := widget.NewLabel("Алгоритмы реестра доступны в форме эксперимента без изменения кода и миграций. " +
+		"Имя алгоритма после регистрации не меняется. Алгоритм, назначенный вариантам экспериментов, " +
+		"нельзя удалить — пометьте его устаревшим, чтобы он не предлагался для новых экспериментов. " +
+		"Схема параметров (подмножество JSON Schema) проверяет параметры вариантов новых экспериментов; " +
+		"пустая схема допускает любые параметры.")
+	help.Wrapping = fyne.TextWrapWord
+
+	form := widget.NewForm(
+		widget.NewFormItem("Имя", w.nameEntry),
+		widget.NewFormItem("Описание", w.descriptionEntry),
+		widget.NewFormItem("Владелец", w.ownerEntry),
+		widget.NewFormItem("Версия", w.versionEntry),
+		widget.NewFormItem("", w.deprecatedCheck),
+		widget.NewFormItem("Схема параметров", w.schemaEntry),
+	)
+
+	saveBtn := widget.NewButton("Сохранить", w.saveAlgorithm)
+	newBtn := widget.NewButton("Новый алгоритм", w.clearForm)
+	deleteBtn := widget.NewButton("Удалить", w.deleteAlgorithm)
+	closeBtn := widget.NewButton("Закрыть", func() { w.window.Close() })
+
+	editor := container.NewBorder(
+		container.NewVBox(
+			widget.NewLabelWithStyle("Алгоритм", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			form,
+			container.NewHBox(saveBtn, newBtn, deleteBtn),
+			w.statusLabel,
+		),
+		nil, nil, nil,
+		help,
+	)
+
+	split := container.NewHSplit(container.NewScroll(w.algorithmList), editor)
+	split.SetOffset(0.4)
+
+	w.window.SetContent(container.NewPadded(container.NewBorder(
+		nil,
+		container.NewHBox(layout.NewSpacer(), closeBtn),
+		nil, nil,
+		split,
+	)))
+}
+
+// loadAlgorithms загружает все алгоритмы реестра, включая устаревшие
+func (w *AlgorithmsWindow) loadAlgorithms() {
+	algorithms, err := w.mw.rep.GetAlgorithms(context.Background(), true)
+	if err != nil {
+		logger.Error("Ошибка загрузки алгоритмов: %v", err)
+		w.statusLabel.SetText("Ошибка загрузки алгоритмов: " + err.Error())
+		return
+	}
+	w.algorithms = algorithms
+	w.algorithmList.UnselectAll()
+	w.algorithmList.Refresh()
+}
+
+// onAlgorithmSelected заполняет форму выбранным алгоритмом
+func (w *AlgorithmsWindow) onAlgorithmSelected(id widget.ListItemID) {
+	if id < 0 || id >= len(w.algorithms) {
+		return
+	}
+	a := w.algorithms[id]
+	w.selected = a.Name
+	w.nameEntry.SetText(a.Name)
+	w.nameEntry.Disable()
+	w.descriptionEntry.SetText(a.Description)
+	w.ownerEntry.SetText(a.Owner)
+	w.versionEntry.SetText(a.Version)
+	w.deprecatedCheck.SetChecked(a.Deprecated)
+	w.schemaEntry.SetText(formatSchema(a.ParamsSchema))
+	w.statusLabel.SetText(fmt.Sprintf("Редактирование алгоритма %s", a.Name))
+}
+
+// clearForm очищает форму для нового алгоритма
+func (w *AlgorithmsWindow) clearForm() {
+	w.selected = ""
+	w.algorithmList.UnselectAll()
+	w.nameEntry.SetText("")
+	w.nameEntry.Enable()
+	w.descriptionEntry.SetText("")
+	w.ownerEntry.SetText("")
+	w.versionEntry.SetText("1")
+	w.deprecatedCheck.SetChecked(false)
+	w.schemaEntry.SetText("")
+	w.statusLabel.SetText("")
+}
+
+// saveAlgorithm регистрирует новый или изменяет выбранный алгоритм
+func (w *AlgorithmsWindow) saveAlgorithm() {
+	schema, err := parseParams(w.schemaEntry.Text)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("схема параметров: %w", err), w.window)
+		return
+	}
+	a := &models.Algorithm{
+		Name:         strings.TrimSpace(w.nameEntry.Text),
+		Description:  strings.TrimSpace(w.descriptionEntry.Text),
+		Owner:        strings.TrimSpace(w.ownerEntry.Text),
+		Version:      w.versionEntry.Text,
+		Deprecated:   w.deprecatedCheck.Checked,
+		ParamsSchema: schema,
+	}
+
+	// изменение алгоритма каскадно меняет варианты экспериментов: журнал записывает их от имени оператора
+	ctx := w.mw.actorContext()
+	if w.selected == "" {
+		err = w.mw.rep.CreateAlgorithm(ctx, a)
+	} else {
+		a.Name = w.selected
+		err = w.mw.rep.UpdateAlgorithm(ctx, a)
+	}
+	if err != nil {
+		dialog.ShowError(err, w.window)
+		return
+	}
+
+	w.loadAlgorithms()
+	w.clearForm()
+	w.statusLabel.SetText(fmt.Sprintf("Алгоритм %s сохранен", a.Name))
+}
+
+// deleteAlgorithm удаляет выбранный алгоритм после подтверждения
+func (w *AlgorithmsWindow) deleteAlgorithm() {
+	if w.selected == "" {
+		dialog.ShowInformation("Не выбран алгоритм", "Выберите алгоритм в списке", w.window)
+		return
+	}
+	name := w.selected
+	dialog.ShowConfirm("Удаление алгоритма", fmt.Sprintf("Удалить алгоритм %s из реестра?", name), func(ok bool) {
+		if !ok {
+			return
+		}
+		if err := w.mw.rep.DeleteAlgorithm(w.mw.actorContext(), name); err != nil {
+			dialog.ShowError(err, w.window)
+			return
+		}
+		w.loadAlgorithms()
+		w.clearForm()
+		w.statusLabel.SetText("Алгоритм удален")
+	}, w.window)
+}
+
+// Show отображает окно
+func (w *AlgorithmsWindow) Show() {
+	w.window.Show()
+}
+
+// parseParams разбирает JSON-объект из поля ввода; пустое поле — пустой объект
+func parseParams(text string) (map[string]any, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return map[string]any{}, nil
+	}
+	var params map[string]any
+	if err := json.Unmarshal([]byte(text), &params); err != nil {
+		return nil, fmt.Errorf("ожидается JSON-объект, например {\"weight\": 0.3}: %v", err)
+	}
+	if params == nil {
+		return nil, errors.New("ожидается JSON-объект, а не null")
+	}
+	return params, nil
+}
+
+// formatSchema форматирует схему параметров для редактирования; пустая схема — пустое поле
+func formatSchema(schema map[string]any) string {
+	if len(schema) == 0 {
+		return ""
+	}
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return fmt.Sprint(schema)
+	}
+	return string(data)
+}
+
+// paramsPlaceholder возвращает подсказку к полю параметров варианта: свойства из схемы алгоритма
+func paramsPlaceholder(a models.Algorithm) string {
+	props, _ := a.ParamsSchema["properties"].(map[string]any)
+	if len(props) == 0 {
+		return "Параметры алгоритма, JSON (необязательно)"
+	}
+	names := make([]string, 0, len(props))
+	for name := range props {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		if prop, ok := props[name].(map[string]any); ok {
+			if t, ok := prop["type"].(string); ok {
+				names[i] = fmt.Sprintf("%s: %s", name, t)
+			}
+		}
+	}
+	return "Параметры JSON {" + strings.Join(names, ", ") + "}"
+}
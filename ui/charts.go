@@ -0,0 +1,342 @@
+package ui
+
+import (
+	"fmt"
+	"image/color"
+	"math"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/theme"
+	"fyne.io/fyne/v2/widget"
+)
+
+// цвета рядов графиков по порядку
+var chartPalette = []color.Color{
+	color.NRGBA{R: 0x1f, G: 0x77, B: 0xb4, A: 0xff},
+	color.NRGBA{R: 0xff, G: 0x7f, B: 0x0e, A: 0xff},
+	color.NRGBA{R: 0x2c, G: 0xa0, B: 0x2c, A: 0xff},
+	color.NRGBA{R: 0xd6, G: 0x27, B: 0x28, A: 0xff},
+	color.NRGBA{R: 0x94, G: 0x67, B: 0xbd, A: 0xff},
+	color.NRGBA{R: 0x8c, G: 0x56, B: 0x4b, A: 0xff},
+	color.NRGBA{R: 0xe3, G: 0x77, B: 0xc2, A: 0xff},
+	color.NRGBA{R: 0x7f, G: 0x7f, B: 0x7f, A: 0xff},
+}
+
+// отступы области построения графика
+const (
+	chartMarginLeft   = 70
+	chartMarginRight  = 20
+	chartMarginTop    = 40
+	chartMarginBottom = 40
+	chartYTicks       = 5
+	chartMaxXLabels   = 8
+)
+
+// chartColor возвращает цвет ряда с номером i
+func chartColor(i int) color.Color {
+	return chartPalette[i%len(chartPalette)]
+}
+
+// chartSeries ряд значений графика; NaN — нет значения в точке
+type chartSeries struct {
+	Name   string
+	Values []float64
+	Color  color.Color
+	Dashed bool // вспомогательный ряд (например, граница интервала)
+}
+
+// LineChart линейный график нескольких рядов по общей оси X
+type LineChart struct {
+	widget.BaseWidget
+	title  string
+	labels []string
+	series []chartSeries
+	format func(float64) string
+}
+
+// newLineChart создает линейный график; labels — подписи точек по оси X,
+// format — форматирование значений оси Y
+func newLineChart(title string, labels []string, series []chartSeries, format func(float64) string) *LineChart {
+	if format == nil {
+		format = func(v float64) string { return fmt.Sprintf("%.2f", v) }
+	}
+	c := &LineChart{title: title, labels: labels, series: series, format: format}
+	c.ExtendBaseWidget(c)
+	return c
+}
+
+// CreateRenderer создает отрисовщик графика
+func (c *LineChart) CreateRenderer() fyne.WidgetRenderer {
+	return &chartRenderer{draw: c.draw, target: c}
+}
+
+// valueRange возвращает диапазон значений всех рядов с запасом
+func (c *LineChart) valueRange() (float64, float64, bool) {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, s := range c.series {
+		for _, v := range s.Values {
+			if math.IsNaN(v) || math.IsInf(v, 0) {
+				continue
+			}
+			lo, hi = math.Min(lo, v), math.Max(hi, v)
+		}
+	}
+	if math.IsInf(lo, 1) {
+		return 0, 0, false
+	}
+	if hi == lo {
+		pad := math.Max(math.Abs(hi)*0.1, 0.01)
+		return lo - pad, hi + pad, true
+	}
+	pad := (hi - lo) * 0.05
+	return lo - pad, hi + pad, true
+}
+
+// draw строит объекты графика для заданного размера
+func (c *LineChart) draw(size fyne.Size) []fyne.CanvasObject {
+	objects := []fyne.CanvasObject{chartTitle(c.title)}
+	lo, hi, ok := c.valueRange()
+	n := len(c.labels)
+	if !ok || n == 0 {
+		objects = append(objects, chartText("Нет данных", fyne.NewPos(chartMarginLeft, chartMarginTop)))
+		return objects
+	}
+
+	plot := newPlotArea(size)
+	objects = append(objects, plot.axes(lo, hi, c.format)...)
+	objects = append(objects, plot.xLabels(c.labels, false)...)
+	objects = append(objects, chartLegend(c.series, size)...)
+
+	for _, s := range c.series {
+		var prev *fyne.Position
+		for i, v := range s.Values {
+			if i >= n || math.IsNaN(v) || math.IsInf(v, 0) {
+				prev = nil
+				continue
+			}
+			pos := fyne.NewPos(plot.pointX(i, n), plot.valueY(v, lo, hi))
+			if prev != nil && !(s.Dashed && i%2 == 1) {
+				line := canvas.NewLine(s.Color)
+				line.StrokeWidth = 2
+				if s.Dashed {
+					line.StrokeWidth = 1
+				}
+				line.Position1, line.Position2 = *prev, pos
+				objects = append(objects, line)
+			}
+			if !s.Dashed && n <= 60 {
+				dot := canvas.NewCircle(s.Color)
+				dot.Resize(fyne.NewSize(5, 5))
+				dot.Move(fyne.NewPos(pos.X-2.5, pos.Y-2.5))
+				objects = append(objects, dot)
+			}
+			p := pos
+			prev = &p
+		}
+	}
+	return objects
+}
+
+// BarChart столбчатая диаграмма: столбцы рядов стоят рядом в каждой категории
+type BarChart struct {
+	widget.BaseWidget
+	title  string
+	labels []string
+	series []chartSeries
+	format func(float64) string
+}
+
+// newBarChart создает столбчатую диаграмму; labels — подписи категорий,
+// значения рядов выровнены по категориям и не должны быть отрицательными
+func newBarChart(title string, labels []string, series []chartSeries, format func(float64) string) *BarChart {
+	if format == nil {
+		format = func(v float64) string { return fmt.Sprintf("%.2f", v) }
+	}
+	c := &BarChart{title: title, labels: labels, series: series, format: format}
+	c.ExtendBaseWidget(c)
+	return c
+}
+
+// CreateRenderer создает отрисовщик диаграммы
+func (c *BarChart) CreateRenderer() fyne.WidgetRenderer {
+	return &chartRenderer{draw: c.draw, target: c}
+}
+
+// draw строит объекты диаграммы для заданного размера
+func (c *BarChart) draw(size fyne.Size) []fyne.CanvasObject {
+	objects := []fyne.CanvasObject{chartTitle(c.title)}
+	var hi float64
+	for _, s := range c.series {
+		for _, v := range s.Values {
+			if !math.IsNaN(v) && !math.IsInf(v, 0) {
+				hi = math.Max(hi, v)
+			}
+		}
+	}
+	n := len(c.labels)
+	if hi <= 0 || n == 0 || len(c.series) == 0 {
+		objects = append(objects, chartText("Нет данных", fyne.NewPos(chartMarginLeft, chartMarginTop)))
+		return objects
+	}
+	hi *= 1.05
+
+	plot := newPlotArea(size)
+	objects = append(objects, plot.axes(0, hi, c.format)...)
+	objects = append(objects, plot.xLabels(c.labels, true)...)
+	objects = append(objects, chartLegend(c.series, size)...)
+
+	// в категории столбцы занимают 80% ширины, промежутки между категориями — остальное
+	slot := plot.width / float32(n)
+	barWidth := slot * 0.8 / float32(len(c.series))
+	for i := 0; i < n; i++ {
+		left := plot.pointX(i, n) - slot*0.4
+		for k, s := range c.series {
+			if i >= len(s.Values) || math.IsNaN(s.Values[i]) || s.Values[i] <= 0 {
+				continue
+			}
+			top := plot.valueY(s.Values[i], 0, hi)
+			bar := canvas.NewRectangle(s.Color)
+			bar.Move(fyne.NewPos(left+barWidth*float32(k), top))
+			bar.Resize(fyne.NewSize(max(barWidth-1, 1), plot.top+plot.height-top))
+			objects = append(objects, bar)
+		}
+	}
+	return objects
+}
+
+// plotArea область построения внутри отступов
+type plotArea struct {
+	left, top, width, height float32
+}
+
+// newPlotArea вычисляет область построения для размера виджета
+func newPlotArea(size fyne.Size) plotArea {
+	return plotArea{
+		left:   chartMarginLeft,
+		top:    chartMarginTop,
+		width:  float32(math.Max(float64(size.Width-chartMarginLeft-chartMarginRight), 10)),
+		height: float32(math.Max(float64(size.Height-chartMarginTop-chartMarginBottom), 10)),
+	}
+}
+
+// pointX координата X точки i из n (точки по центрам равных интервалов)
+func (p plotArea) pointX(i, n int) float32 {
+	return p.left + p.width*(float32(i)+0.5)/float32(n)
+}
+
+// valueY координата Y значения v в диапазоне [lo, hi]
+func (p plotArea) valueY(v, lo, hi float64) float32 {
+	return p.top + p.height*float32((hi-v)/(hi-lo))
+}
+
+// axes строит оси, сетку и подписи оси Y
+func (p plotArea) axes(lo, hi float64, format func(float64) string) []fyne.CanvasObject {
+	axisColor := theme.Color(theme.ColorNameForeground)
+	gridColor := theme.Color(theme.ColorNameSeparator)
+
+	var objects []fyne.CanvasObject
+	for i := 0; i <= chartYTicks; i++ {
+		v := lo + (hi-lo)*float64(i)/chartYTicks
+		y := p.valueY(v, lo, hi)
+		grid := canvas.NewLine(gridColor)
+		grid.Position1, grid.Position2 = fyne.NewPos(p.left, y), fyne.NewPos(p.left+p.width, y)
+		label := chartText(format(v), fyne.Position{})
+		label.Alignment = fyne.TextAlignTrailing
+		label.Move(fyne.NewPos(p.left-label.MinSize().Width-6, y-label.MinSize().Height/2))
+		objects = append(objects, grid, label)
+	}
+	if lo < 0 && hi > 0 {
+		zero := canvas.NewLine(axisColor)
+		y := p.valueY(0, lo, hi)
+		zero.Position1, zero.Position2 = fyne.NewPos(p.left, y), fyne.NewPos(p.left+p.width, y)
+		objects = append(objects, zero)
+	}
+
+	xAxis := canvas.NewLine(axisColor)
+	xAxis.Position1, xAxis.Position2 = fyne.NewPos(p.left, p.top+p.height), fyne.NewPos(p.left+p.width, p.top+p.height)
+	yAxis := canvas.NewLine(axisColor)
+	yAxis.Position1, yAxis.Position2 = fyne.NewPos(p.left, p.top), fyne.NewPos(p.left, p.top+p.height)
+	return append(objects, xAxis, yAxis)
+}
+
+// xLabels строит подписи оси X, прореживая их при большом числе точек
+func (p plotArea) xLabels(labels []string, all bool) []fyne.CanvasObject {
+	n := len(labels)
+	step := 1
+	if !all && n > chartMaxXLabels {
+		step = int(math.Ceil(float64(n) / chartMaxXLabels))
+	}
+	var objects []fyne.CanvasObject
+	for i := 0; i < n; i += step {
+		label := chartText(labels[i], fyne.Position{})
+		x := p.pointX(i, n) - label.MinSize().Width/2
+		label.Move(fyne.NewPos(x, p.top+p.height+6))
+		objects = append(objects, label)
+	}
+	return objects
+}
+
+// chartTitle создает заголовок графика
+func chartTitle(title string) *canvas.Text {
+	text := canvas.NewText(title, theme.Color(theme.ColorNameForeground))
+	text.TextStyle = fyne.TextStyle{Bold: true}
+	text.Move(fyne.NewPos(chartMarginLeft, 8))
+	return text
+}
+
+// chartText создает подпись графика в заданной позиции
+func chartText(s string, pos fyne.Position) *canvas.Text {
+	text := canvas.NewText(s, theme.Color(theme.ColorNameForeground))
+	text.TextSize = theme.CaptionTextSize()
+	text.Move(pos)
+	return text
+}
+
+// chartLegend строит легенду рядов в правом верхнем углу (ряды без имени не показываются)
+func chartLegend(series []chartSeries, size fyne.Size) []fyne.CanvasObject {
+	var objects []fyne.CanvasObject
+	x := size.Width - chartMarginRight
+	for i := len(series) - 1; i >= 0; i-- {
+		s := series[i]
+		if s.Name == "" {
+			continue
+		}
+		label := chartText(s.Name, fyne.Position{})
+		x -= label.MinSize().Width
+		label.Move(fyne.NewPos(x, 12))
+		swatch := canvas.NewRectangle(s.Color)
+		swatch.Resize(fyne.NewSize(12, 12))
+		x -= 16
+		swatch.Move(fyne.NewPos(x, 14))
+		x -= 12
+		objects = append(objects, swatch, label)
+	}
+	return objects
+}
+
+// chartRenderer отрисовщик, перестраивающий объекты графика при изменении размера
+type chartRenderer struct {
+	draw    func(fyne.Size) []fyne.CanvasObject
+	target  fyne.Widget
+	objects []fyne.CanvasObject
+}
+
+func (r *chartRenderer) Layout(size fyne.Size) {
+	r.objects = r.draw(size)
+}
+
+func (r *chartRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(500, 280)
+}
+
+func (r *chartRenderer) Refresh() {
+	r.Layout(r.target.Size())
+	canvas.Refresh(r.target)
+}
+
+func (r *chartRenderer) Objects() []fyne.CanvasObject {
+	return r.objects
+}
+
+func (r *chartRenderer) Destroy() {}
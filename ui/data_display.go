@@ -3,6 +3,7 @@ package ui
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"testing-platform/db/models"
 	"testing-platform/pkg/logger"
@@ -29,6 +30,13 @@ type DataDisplayWindow struct {
 	subqueryBtn       *widget.Button
 	clearSubqueryBtn  *widget.Button
 	subqueryLabel     *widget.Label
+
+	// предупреждение об экспериментах с несоответствием соотношения групп
+	srmWarning *widget.Label
+
+	// эксперимент, выбранный в таблице experiments, и кнопка его редактирования
+	selectedExperimentID int
+	editBtn              *widget.Button
 }
 
 // convertValueToString конвертирует любое значение в строку для отображения
@@ -72,6 +80,9 @@ func convertValueToString(value interface{}) string {
 		return "Нет"
 	case []string:
 		return strings.Join(v, ", ")
+	case map[string]any:
+		// JSONB-объекты (параметры вариантов, схемы, снимки журнала) показываются как JSON
+		return models.FormatParams(v)
 	case []byte:
 		str := string(v)
 		// Пытаемся определить, является ли это составным типом
@@ -194,6 +205,14 @@ func (d *DataDisplayWindow) buildUI() {
 	// создание контейнера для таблицы
 	d.tableContainer = container.NewStack()
 
+	// кнопка создается до загрузки таблицы: выбор строки experiments включает ее
+	d.editBtn = widget.NewButton("Редактировать эксперимент", func() {
+		if d.selectedExperimentID != 0 {
+			NewExperimentEditWindow(d.mainWindow, d.selectedExperimentID).Show()
+		}
+	})
+	d.editBtn.Disable()
+
 	// Выбор таблицы
 	tableSelect := widget.NewSelect([]string{}, func(selected string) {
 		if selected != "" {
@@ -209,9 +228,16 @@ func (d *DataDisplayWindow) buildUI() {
 	d.loadTableList(tableSelect)
 
 	// Элементы управления для фильтра (выпадающие списки и поля ввода)
-	algorithmA := widget.NewSelect([]string{"", "collaborative", "content_based", "hybrid", "popularity_based"}, nil)
-	algorithmB := widget.NewSelect([]string{"", "collaborative", "content_based", "hybrid", "popularity_based"}, nil)
-	isActive := widget.NewCheck("Только активные", nil)
+	// в фильтре доступны все алгоритмы реестра, включая устаревшие
+	algorithmOptions := []string{""}
+	if algorithms, err := d.mainWindow.rep.GetAlgorithms(context.Background(), true); err != nil {
+		logger.Error("Ошибка загрузки реестра алгоритмов: %v", err)
+	} else {
+		algorithmOptions = append(algorithmOptions, models.AlgorithmNames(algorithms)...)
+	}
+	algorithmA := widget.NewSelect(algorithmOptions, nil)
+	algorithmB := widget.NewSelect(algorithmOptions, nil)
+	status := widget.NewSelect(append([]string{""}, statusTitles(models.ExperimentStatuses)...), nil)
 	dateFrom := widget.NewEntry()
 	dateTo := widget.NewEntry()
 
@@ -230,8 +256,8 @@ func (d *DataDisplayWindow) buildUI() {
 		if algorithmB.Selected != "" {
 			filter.AlgorithmB = algorithmB.Selected
 		}
-		if isActive.Checked {
-			filter.IsActive = &isActive.Checked
+		if status.Selected != "" {
+			filter.Status = statusFromTitle(status.Selected)
 		}
 
 		// Парсим даты
@@ -253,7 +279,7 @@ func (d *DataDisplayWindow) buildUI() {
 	clearFilters := func() {
 		algorithmA.SetSelected("")
 		algorithmB.SetSelected("")
-		isActive.SetChecked(false)
+		status.SetSelected("")
 		dateFrom.SetText("")
 		dateTo.SetText("")
 		d.updateTable(models.ExperimentFilter{})
@@ -295,8 +321,8 @@ func (d *DataDisplayWindow) buildUI() {
 				algorithmB,
 			),
 			container.NewVBox(
-				widget.NewLabel(" "), // Пустой label для выравнивания
-				isActive,
+				widget.NewLabel("Статус:"),
+				status,
 			),
 			container.NewVBox(
 				widget.NewLabel("Дата от:"),
@@ -311,6 +337,7 @@ func (d *DataDisplayWindow) buildUI() {
 			widget.NewButton("Применить фильтр", applyFilter),
 			widget.NewButton("Очистить фильтры", clearFilters),
 			refreshBtn,
+			d.editBtn,
 		),
 		widget.NewSeparator(),
 		// НОВАЯ СЕКЦИЯ: ПОДЗАПРОСЫ
@@ -330,6 +357,9 @@ func (d *DataDisplayWindow) buildUI() {
 		d.window.Close()
 	})
 
+	d.srmWarning = srmWarningLabel("")
+	d.srmWarning.Hide()
+
 	// первоначальная загрузка данных
 	d.updateTable(models.ExperimentFilter{})
 
@@ -342,7 +372,7 @@ func (d *DataDisplayWindow) buildUI() {
 
 	// создание основного контейнера с исправленным макетом
 	content := container.NewBorder(
-		nil,                         // верхняя панель (теперь в split)
+		d.srmWarning,                // верхняя панель с предупреждением SRM
 		container.NewHBox(closeBtn), // нижняя панель с кнопкой закрытия
 		nil, nil,
 		split, // центральная область с разделителем
@@ -540,6 +570,7 @@ func (d *DataDisplayWindow) loadTableList(tableSelect *widget.Select) {
 func (d *DataDisplayWindow) updateTable(filter models.ExperimentFilter) {
 	// Сохраняем текущий фильтр
 	d.currentFilter = filter
+	d.updateSRMWarning()
 
 	// Если есть активный подзапрос, используем его вместо обычного запроса
 	if d.subqueryCondition != nil {
@@ -580,7 +611,7 @@ func (d *DataDisplayWindow) updateTable(filter models.ExperimentFilter) {
 	var args []interface{}
 
 	// Применяем фильтры только для таблицы experiments
-	if d.tableName == "experiments" && (filter.AlgorithmA != "" || filter.AlgorithmB != "" || filter.IsActive != nil || !filter.StartDateFrom.IsZero() || !filter.StartDateTo.IsZero()) {
+	if d.tableName == "experiments" && (filter.AlgorithmA != "" || filter.AlgorithmB != "" || filter.IsActive != nil || filter.Status != "" || !filter.StartDateFrom.IsZero() || !filter.StartDateTo.IsZero()) {
 		query += " WHERE 1=1"
 		var conditions []string
 		argCount := 1
@@ -600,6 +631,11 @@ func (d *DataDisplayWindow) updateTable(filter models.ExperimentFilter) {
 			args = append(args, *filter.IsActive)
 			argCount++
 		}
+		if filter.Status != "" {
+			conditions = append(conditions, fmt.Sprintf("status = $%d", argCount))
+			args = append(args, filter.Status)
+			argCount++
+		}
 		if !filter.StartDateFrom.IsZero() {
 			conditions = append(conditions, fmt.Sprintf("start_date >= $%d", argCount))
 			args = append(args, filter.StartDateFrom)
@@ -645,6 +681,39 @@ func (d *DataDisplayWindow) updateTable(filter models.ExperimentFilter) {
 	d.createDynamicTable(result)
 }
 
+// updateSRMWarning показывает предупреждение об экспериментах, не прошедших проверку соотношения групп
+func (d *DataDisplayWindow) updateSRMWarning() {
+	if d.srmWarning == nil {
+		return
+	}
+	if d.tableName != "experiments" && d.tableName != "users" {
+		d.srmWarning.Hide()
+		return
+	}
+
+	checks, err := d.mainWindow.rep.CheckSampleRatios(context.Background())
+	if err != nil {
+		logger.Error("Ошибка проверки соотношения групп: %v", err)
+		d.srmWarning.Hide()
+		return
+	}
+
+	var failed []string
+	for _, check := range checks {
+		if check.Mismatch {
+			failed = append(failed, fmt.Sprintf("%d (p = %.6f)", check.ExperimentID, check.PValue))
+		}
+	}
+	if len(failed) == 0 {
+		d.srmWarning.Hide()
+		return
+	}
+
+	d.srmWarning.SetText("⚠ Несоответствие соотношения групп (SRM) в экспериментах: " + strings.Join(failed, ", ") +
+		". Распределение пользователей не совпадает с ожидаемым, результаты этих экспериментов ненадежны.")
+	d.srmWarning.Show()
+}
+
 // executeQueryWithParams выполняет параметризованный запрос
 func (d *DataDisplayWindow) executeQueryWithParams(ctx context.Context, query string, args ...interface{}) (*models.QueryResult, error) {
 	logger.Info("Выполнение параметризованного запроса: %s с параметрами: %v", query, args)
@@ -746,13 +815,18 @@ func (d *DataDisplayWindow) createDynamicTable(result *models.QueryResult) {
 					text := data[i.Row-1][i.Col]
 
 					// Специальная обработка для boolean значений
-					if result.Columns[i.Col] == "is_active" {
+					if result.Columns[i.Col] == "is_active" || result.Columns[i.Col] == "deprecated" {
 						if text == "true" {
 							text = "Да"
 						} else if text == "false" {
 							text = "Нет"
 						}
 					}
+					// статусы жизненного цикла показываются по-русски
+					switch result.Columns[i.Col] {
+					case "status", "from_status", "to_status":
+						text = models.StatusTitle(text)
+					}
 
 					label.SetText(text)
 					label.TextStyle = fyne.TextStyle{}
@@ -760,6 +834,23 @@ func (d *DataDisplayWindow) createDynamicTable(result *models.QueryResult) {
 			}
 		})
 
+	// строка таблицы experiments выбирает эксперимент для редактирования
+	d.selectedExperimentID = 0
+	d.editBtn.Disable()
+	if d.tableName == "experiments" {
+		table.OnSelected = func(id widget.TableCellID) {
+			d.selectedExperimentID = 0
+			if id.Row > 0 && id.Row-1 < len(result.Rows) {
+				d.selectedExperimentID, _ = strconv.Atoi(convertValueToString(result.Rows[id.Row-1]["id"]))
+			}
+			if d.selectedExperimentID != 0 {
+				d.editBtn.Enable()
+			} else {
+				d.editBtn.Disable()
+			}
+		}
+	}
+
 	// Настраиваем ширину столбцов в зависимости от содержания
 	for i := 0; i < len(result.Columns); i++ {
 		colName := result.Columns[i]
@@ -772,7 +863,11 @@ func (d *DataDisplayWindow) createDynamicTable(result *models.QueryResult) {
 			table.SetColumnWidth(i, 250)
 		case "is_active":
 			table.SetColumnWidth(i, 100)
-		case "start_date":
+		case "status", "from_status", "to_status":
+			table.SetColumnWidth(i, 130)
+		case "reason", "old_data", "new_data":
+			table.SetColumnWidth(i, 300)
+		case "start_date", "planned_start", "planned_end":
 			table.SetColumnWidth(i, 150)
 		case "tags":
 			table.SetColumnWidth(i, 200)
@@ -810,6 +905,41 @@ func (d *DataDisplayWindow) formatColumnName(columnName string) string {
 		"type":         "Тип",
 		"name":         "Название",
 		"id":           "ID",
+
+		// варианты эксперимента
+		"algorithm": "Алгоритм",
+		"position":  "Позиция",
+		"weight":    "Вес трафика (%)",
+
+		// слои экспериментов
+		"layer_id":          "Слой",
+		"layer_range_start": "Начало диапазона слоя (%)",
+		"layer_range_end":   "Конец диапазона слоя (%)",
+
+		// жизненный цикл эксперимента
+		"status_changed_at": "Статус изменен",
+		"from_status":       "Прежний статус",
+		"to_status":         "Новый статус",
+		"reason":            "Причина",
+		"changed_at":        "Время изменения",
+		"planned_start":     "Плановый запуск",
+		"planned_end":       "Плановое завершение",
+
+		// журнал изменений экспериментов
+		"entity":     "Объект",
+		"operation":  "Операция",
+		"changed_by": "Автор изменения",
+		"old_data":   "Было",
+		"new_data":   "Стало",
+
+		// реестр алгоритмов
+		"owner":      "Владелец",
+		"version":    "Версия",
+		"deprecated": "Устарел",
+
+		// параметры алгоритмов
+		"params":        "Параметры",
+		"params_schema": "Схема параметров",
 	}
 
 	// Проверяем, есть ли столбец в карте
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math"
 	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"testing-platform/db/models"
@@ -16,6 +17,12 @@ import (
 	"fyne.io/fyne/v2/widget"
 )
 
+// вариант списка групп, при котором пользователь распределяется автоматически (AssignUser)
+const autoGroupOption = "Авто (распределить)"
+
+// вариант списка слоев для эксперимента вне слоя
+const noLayerOption = "Без слоя"
+
 // вспомогательная функция для показа ошибок пользователю
 func showUserError(win fyne.Window, msg string) {
 	dialog.ShowError(fmt.Errorf("%s", msg), win)
@@ -55,18 +62,23 @@ func (mw *MainWindow) showDataInputDialog() {
 func (mw *MainWindow) createExperimentForm() *widget.Form {
 	name := widget.NewEntry()
 	name.SetPlaceHolder("Введите название эксперимента")
-	algorithmA := widget.NewSelect([]string{"collaborative", "content_based", "hybrid", "popularity_based"}, nil)
-	algorithmB := widget.NewSelect([]string{"collaborative", "content_based", "hybrid", "popularity_based"}, nil)
 	userPercent := widget.NewEntry()
 	userPercent.SetPlaceHolder("Например: 10.5")
-	isActive := widget.NewCheck("Активный эксперимент", nil)
+	status := widget.NewSelect(statusTitles(models.InitialStatuses), nil)
+	status.SetSelected(models.StatusTitle(models.StatusRunning))
+	plannedStart := widget.NewEntry()
+	plannedStart.SetPlaceHolder("ГГГГ-ММ-ДД ЧЧ:ММ (необязательно)")
+	plannedEnd := widget.NewEntry()
+	plannedEnd.SetPlaceHolder("ГГГГ-ММ-ДД ЧЧ:ММ (необязательно)")
+	scheduleHint := widget.NewLabel("Запланированный эксперимент запустится в плановую дату, запущенный — завершится в плановую дату завершения")
+	scheduleHint.TextStyle = fyne.TextStyle{Italic: true}
 	tagsEntry := widget.NewEntry()
 	tagsEntry.SetPlaceHolder("Например: тест, рекомендации, основной")
 
 	nameHint := widget.NewLabel("Обязательное поле, максимум 255 символов")
 	nameHint.TextStyle = fyne.TextStyle{Italic: true}
 
-	algorithmHint := widget.NewLabel("Выберите два разных алгоритма из списка")
+	algorithmHint := widget.NewLabel("От 2 до 8 вариантов с разными алгоритмами или параметрами, A — контрольная группа; веса трафика в сумме 100")
 	algorithmHint.TextStyle = fyne.TextStyle{Italic: true}
 
 	userPercentHint := widget.NewLabel("Число от 0.1 до 100 (положительное)")
@@ -75,6 +87,108 @@ func (mw *MainWindow) createExperimentForm() *widget.Form {
 	tagsHint := widget.NewLabel("Теги через запятую (каждый до 50 символов)")
 	tagsHint.TextStyle = fyne.TextStyle{Italic: true}
 
+	// алгоритмы вариантов берутся из реестра (без устаревших)
+	var algorithmNames []string
+	registry := make(map[string]models.Algorithm)
+	if algorithms, err := mw.rep.GetAlgorithms(context.Background(), false); err != nil {
+		logger.Error("Ошибка загрузки реестра алгоритмов: %v", err)
+	} else {
+		algorithmNames = models.AlgorithmNames(algorithms)
+		for _, a := range algorithms {
+			registry[a.Name] = a
+		}
+	}
+
+	// варианты эксперимента (A/B/n), первый — контрольная группа
+	var variantSelects []*widget.Select
+	var weightEntries []*widget.Entry
+	var paramsEntries []*widget.Entry
+	variantRows := container.NewVBox()
+	weightsError := widget.NewLabel("")
+	weightsError.Hide()
+	// parseWeights проверяет веса вариантов: положительные числа с двумя знаками, в сумме 100
+	parseWeights := func() ([]float64, error) {
+		weights := make([]float64, len(weightEntries))
+		var sum float64
+		for i, entry := range weightEntries {
+			val, err := strconv.ParseFloat(strings.TrimSpace(entry.Text), 64)
+			if err != nil || math.IsNaN(val) || val <= 0 || val > 100 {
+				return nil, fmt.Errorf("вес группы %s должен быть числом больше 0 и не больше 100", models.VariantName(i))
+			}
+			weights[i] = val
+			sum += val
+		}
+		if math.Abs(sum-100) > 1e-6 {
+			return nil, fmt.Errorf("сумма весов должна быть равна 100 (сейчас %.2f)", sum)
+		}
+		return weights, nil
+	}
+	checkWeights := func(string) {
+		if _, err := parseWeights(); err != nil {
+			weightsError.SetText(err.Error())
+			weightsError.Show()
+		} else {
+			weightsError.Hide()
+		}
+	}
+	// при изменении числа вариантов трафик снова делится поровну
+	resetWeights := func() {
+		for i, w := range models.EqualWeights(len(weightEntries)) {
+			weightEntries[i].SetText(strconv.FormatFloat(w, 'f', -1, 64))
+		}
+	}
+	var addVariantBtn, removeVariantBtn *widget.Button
+	updateVariantButtons := func() {
+		if len(variantSelects) >= models.MaxVariants {
+			addVariantBtn.Disable()
+		} else {
+			addVariantBtn.Enable()
+		}
+		if len(variantSelects) <= 2 {
+			removeVariantBtn.Disable()
+		} else {
+			removeVariantBtn.Enable()
+		}
+	}
+	addVariant := func() {
+		// параметры алгоритма варианта; подсказка строится по схеме параметров выбранного алгоритма
+		params := widget.NewEntry()
+		params.SetPlaceHolder(paramsPlaceholder(models.Algorithm{}))
+		algorithm := widget.NewSelect(algorithmNames, func(selected string) {
+			params.SetPlaceHolder(paramsPlaceholder(registry[selected]))
+			params.Refresh()
+		})
+		algorithm.PlaceHolder = "Выберите алгоритм"
+		label := widget.NewLabel("Группа " + models.VariantName(len(variantSelects)))
+		weight := widget.NewEntry()
+		weight.SetPlaceHolder("Вес, %")
+		weight.OnChanged = checkWeights
+		variantSelects = append(variantSelects, algorithm)
+		weightEntries = append(weightEntries, weight)
+		paramsEntries = append(paramsEntries, params)
+		weightBox := container.NewGridWrap(fyne.NewSize(90, weight.MinSize().Height), weight)
+		variantRows.Add(container.NewBorder(nil, params, label, weightBox, algorithm))
+	}
+	addVariantBtn = widget.NewButton("Добавить вариант", func() {
+		addVariant()
+		resetWeights()
+		updateVariantButtons()
+	})
+	removeVariantBtn = widget.NewButton("Удалить последний", func() {
+		if n := len(variantSelects); n > 2 {
+			variantSelects = variantSelects[:n-1]
+			weightEntries = weightEntries[:n-1]
+			paramsEntries = paramsEntries[:n-1]
+			variantRows.Remove(variantRows.Objects[n-1])
+			resetWeights()
+		}
+		updateVariantButtons()
+	})
+	addVariant()
+	addVariant()
+	resetWeights()
+	updateVariantButtons()
+
 	// ошибки
 	nameError := widget.NewLabel("")
 	nameError.Hide()
@@ -207,13 +321,48 @@ func (mw *MainWindow) createExperimentForm() *widget.Form {
 		}
 	}
 
+	// планирование размера выборки по текущим значениям формы
+	plannerBtn := widget.NewButton("Рассчитать размер выборки и длительность", func() {
+		input := models.SampleSizeInput{
+			AlgorithmA: variantSelects[0].Selected,
+			AlgorithmB: variantSelects[1].Selected,
+			Groups:     len(variantSelects),
+			Tags:       parseTags(tagsEntry.Text),
+		}
+		if userPercent.Validator(userPercent.Text) == nil {
+			input.UserPercent, _ = strconv.ParseFloat(userPercent.Text, 64)
+		}
+		mw.showSampleSizePlanner(input, func(percent float64) {
+			userPercent.SetText(strconv.FormatFloat(percent, 'f', 2, 64))
+		})
+	})
+
+	// слой экспериментов: эксперименту выделяется свободный диапазон слоя шириной в процент пользователей
+	layerIDs := map[string]int{noLayerOption: 0}
+	layerOptions := []string{noLayerOption}
+	if usage, err := mw.rep.GetLayerUsage(context.Background()); err != nil {
+		logger.Error("Ошибка загрузки слоев: %v", err)
+	} else {
+		for _, u := range usage {
+			option := fmt.Sprintf("%d: %s (свободно %.2f%%)", u.Layer.ID, u.Layer.Name, u.FreePercent)
+			layerOptions = append(layerOptions, option)
+			layerIDs[option] = u.Layer.ID
+		}
+	}
+	layerSelect := widget.NewSelect(layerOptions, nil)
+	layerSelect.SetSelected(noLayerOption)
+	layerHint := widget.NewLabel("Эксперименты одного слоя не пересекаются по пользователям")
+	layerHint.TextStyle = fyne.TextStyle{Italic: true}
+
 	form := &widget.Form{
 		Items: []*widget.FormItem{
 			{Text: "Название", Widget: container.NewVBox(name, nameError)},
-			{Text: "Алгоритм A", Widget: algorithmA},
-			{Text: "Алгоритм B", Widget: algorithmB},
+			{Text: "Варианты", Widget: container.NewVBox(variantRows, weightsError, container.NewHBox(addVariantBtn, removeVariantBtn), algorithmHint)},
 			{Text: "Процент пользователей", Widget: container.NewVBox(userPercent, userPercentError)},
-			{Text: "Статус", Widget: isActive},
+			{Text: "Планирование", Widget: plannerBtn},
+			{Text: "Слой", Widget: container.NewVBox(layerSelect, layerHint)},
+			{Text: "Статус", Widget: status},
+			{Text: "Расписание", Widget: container.NewVBox(plannedStart, plannedEnd, scheduleHint)},
 			{Text: "Теги", Widget: container.NewVBox(tagsEntry, tagsError)},
 		},
 		OnSubmit: func() {
@@ -229,12 +378,45 @@ func (mw *MainWindow) createExperimentForm() *widget.Form {
 				showUserError(mw.window, "Ошибка в тегах: "+err.Error())
 				return
 			}
-			if algorithmA.Selected == "" || algorithmB.Selected == "" {
-				showUserError(mw.window, "Выберите оба алгоритма")
+			weights, weightsErr := parseWeights()
+			if weightsErr != nil {
+				showUserError(mw.window, "Ошибка в весах вариантов: "+weightsErr.Error())
+				return
+			}
+			var variants []models.Variant
+			selected := make(map[string]bool)
+			for i, algorithm := range variantSelects {
+				name := models.VariantName(i)
+				if algorithm.Selected == "" {
+					showUserError(mw.window, "Выберите алгоритм для группы "+name)
+					return
+				}
+				params, paramsErr := parseParams(paramsEntries[i].Text)
+				if paramsErr == nil {
+					a := registry[algorithm.Selected]
+					paramsErr = a.ValidateParams(params)
+				}
+				if paramsErr != nil {
+					showUserError(mw.window, "Ошибка в параметрах группы "+name+": "+paramsErr.Error())
+					return
+				}
+				config := algorithm.Selected + models.FormatParams(params)
+				if selected[config] {
+					showUserError(mw.window, "Варианты с одинаковым алгоритмом должны различаться параметрами")
+					return
+				}
+				selected[config] = true
+				variants = append(variants, models.Variant{Name: name, Algorithm: algorithm.Selected, Weight: weights[i], Params: params})
+			}
+
+			start, scheduleErr := parsePlannedTime(plannedStart.Text)
+			if scheduleErr != nil {
+				showUserError(mw.window, "Ошибка в плановом запуске: "+scheduleErr.Error())
 				return
 			}
-			if algorithmA.Selected == algorithmB.Selected {
-				showUserError(mw.window, "Алгоритмы не могут быть одинаковыми")
+			end, scheduleErr := parsePlannedTime(plannedEnd.Text)
+			if scheduleErr != nil {
+				showUserError(mw.window, "Ошибка в плановом завершении: "+scheduleErr.Error())
 				return
 			}
 
@@ -242,19 +424,31 @@ func (mw *MainWindow) createExperimentForm() *widget.Form {
 			tags := parseTags(tagsEntry.Text)
 
 			exp := &models.Experiment{
-				Name:        name.Text,
-				AlgorithmA:  algorithmA.Selected,
-				AlgorithmB:  algorithmB.Selected,
-				UserPercent: userPercentVal,
-				IsActive:    isActive.Checked,
-				Tags:        tags,
+				Name:         name.Text,
+				AlgorithmA:   variants[0].Algorithm,
+				AlgorithmB:   variants[1].Algorithm,
+				UserPercent:  userPercentVal,
+				Status:       statusFromTitle(status.Selected),
+				PlannedStart: start,
+				PlannedEnd:   end,
+				Tags:         tags,
+				Variants:     variants,
+				LayerID:      layerIDs[layerSelect.Selected],
 			}
 
-			ctx := context.Background()
+			ctx := mw.actorContext()
+			if err := exp.Validate(); err != nil {
+				showUserError(mw.window, "Не удалось создать эксперимент: "+err.Error())
+				return
+			}
 			err := mw.rep.CreateExperiment(ctx, exp)
 			if err != nil {
 				logger.Error("Ошибка создания эксперимента: %v", err)
-				showUserError(mw.window, "Не удалось создать эксперимент: проверьте корректность данных и соединение с БД")
+				if exp.LayerID != 0 {
+					showUserError(mw.window, "Не удалось создать эксперимент в слое: "+err.Error())
+				} else {
+					showUserError(mw.window, "Не удалось создать эксперимент: проверьте корректность данных и соединение с БД")
+				}
 			} else {
 				dialog.ShowInformation("Успех", fmt.Sprintf("Эксперимент создан (ID: %d)", exp.ID), mw.window)
 				logger.Info("Эксперимент '%s' успешно создан", exp.Name)
@@ -272,7 +466,8 @@ func (mw *MainWindow) createUserForm() *widget.Form {
 	userId := widget.NewEntry()
 	userId.SetPlaceHolder("Например: user_123")
 
-	groupName := widget.NewSelect([]string{"A", "B"}, nil)
+	groupName := widget.NewSelect([]string{}, nil)
+	groupName.PlaceHolder = "Сначала укажите эксперимент"
 
 	experimentIdHint := widget.NewLabel("Целое положительное число")
 	experimentIdHint.TextStyle = fyne.TextStyle{Italic: true}
@@ -280,9 +475,17 @@ func (mw *MainWindow) createUserForm() *widget.Form {
 	userIdHint := widget.NewLabel("Только буквы, цифры, дефисы и подчеркивания")
 	userIdHint.TextStyle = fyne.TextStyle{Italic: true}
 
-	groupHint := widget.NewLabel("Группа A или B для A/B тестирования")
+	groupHint := widget.NewLabel("Один из вариантов эксперимента (A — контрольная группа)")
 	groupHint.TextStyle = fyne.TextStyle{Italic: true}
 
+	// необязательные атрибуты для сегментного анализа
+	device := widget.NewEntry()
+	device.SetPlaceHolder("Например: mobile (необязательно)")
+	country := widget.NewEntry()
+	country.SetPlaceHolder("Например: RU (необязательно)")
+	cohort := widget.NewEntry()
+	cohort.SetPlaceHolder("Например: 2024-05 (необязательно)")
+
 	experimentIdError := widget.NewLabel("")
 	experimentIdError.Hide()
 	userIdError := widget.NewLabel("")
@@ -316,9 +519,29 @@ func (mw *MainWindow) createUserForm() *widget.Form {
 		if err := experimentId.Validator(s); err != nil {
 			experimentIdError.SetText(err.Error())
 			experimentIdError.Show()
-		} else {
-			experimentIdError.Hide()
+			return
+		}
+		experimentIdError.Hide()
+
+		// группы пользователя — варианты выбранного эксперимента
+		id, _ := strconv.Atoi(strings.TrimSpace(s))
+		variants, err := mw.rep.GetVariants(context.Background(), id)
+		if err != nil {
+			logger.Error("Ошибка загрузки вариантов эксперимента %d: %v", id, err)
+			return
+		}
+		var names []string
+		for _, v := range variants {
+			names = append(names, fmt.Sprintf("%s (%g%%)", v.Name, v.Weight))
 		}
+		if len(names) > 0 {
+			names = append(names, autoGroupOption)
+		}
+		groupName.Options = names
+		if !slices.Contains(names, groupName.Selected) {
+			groupName.ClearSelected()
+		}
+		groupName.Refresh()
 	}
 
 	userId.Validator = func(s string) error {
@@ -350,6 +573,9 @@ func (mw *MainWindow) createUserForm() *widget.Form {
 			{Text: "ID эксперимента", Widget: container.NewVBox(experimentId, experimentIdError)},
 			{Text: "ID пользователя", Widget: container.NewVBox(userId, userIdError)},
 			{Text: "Группа", Widget: groupName},
+			{Text: "Устройство", Widget: device},
+			{Text: "Страна", Widget: country},
+			{Text: "Когорта", Widget: cohort},
 		},
 		OnSubmit: func() {
 			if err := experimentId.Validator(experimentId.Text); err != nil {
@@ -361,7 +587,7 @@ func (mw *MainWindow) createUserForm() *widget.Form {
 				return
 			}
 			if groupName.Selected == "" {
-				showUserError(mw.window, "Выберите группу (A или B)")
+				showUserError(mw.window, "Выберите группу из вариантов эксперимента")
 				return
 			}
 
@@ -380,10 +606,38 @@ func (mw *MainWindow) createUserForm() *widget.Form {
 				return
 			}
 
+			group, _, _ := strings.Cut(groupName.Selected, " ")
 			user := &models.User{
 				ExperimentId: experimentIdVal,
 				UserId:       userId.Text,
-				GroupName:    groupName.Selected,
+				GroupName:    group,
+				Device:       strings.TrimSpace(device.Text),
+				Country:      strings.TrimSpace(country.Text),
+				Cohort:       strings.TrimSpace(cohort.Text),
+			}
+
+			// автоматическое распределение: хеш ID пользователя, доля трафика и веса вариантов
+			if groupName.Selected == autoGroupOption {
+				assignment, err := mw.rep.AssignUserWithAttributes(ctx, user)
+				switch {
+				case err != nil:
+					logger.Error("Ошибка распределения пользователя: %v", err)
+					showUserError(mw.window, "Не удалось распределить пользователя: "+err.Error())
+				case !assignment.Eligible:
+					dialog.ShowInformation("Пользователь не распределен",
+						"Пользователь не попал в долю трафика эксперимента и не добавлен", mw.window)
+				case !assignment.Created:
+					dialog.ShowInformation("Пользователь уже распределен",
+						"Пользователь уже участвует в эксперименте в группе "+assignment.GroupName, mw.window)
+				default:
+					dialog.ShowInformation("Успех", "Пользователь распределен в группу "+assignment.GroupName, mw.window)
+				}
+				return
+			}
+
+			if err := user.Validate(); err != nil {
+				showUserError(mw.window, err.Error())
+				return
 			}
 
 			err = mw.rep.AddUserToExperiment(ctx, user)
@@ -391,7 +645,7 @@ func (mw *MainWindow) createUserForm() *widget.Form {
 				logger.Error("Ошибка добавления пользователя: %v", err)
 				showUserError(mw.window, "Не удалось добавить пользователя: проверьте корректность данных и соединение с БД")
 			} else {
-				dialog.ShowInformation("Успех", "Пользователь успешно добавлен", mw.window)
+				dialog.ShowInformation("Успех", "Пользователь успешно добавлен в группу "+user.GroupName, mw.window)
 				logger.Info("Пользователь %s успешно добавлен", user.UserId)
 			}
 		},
@@ -0,0 +1,172 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing-platform/db"
+	"testing-platform/db/models"
+	"testing-platform/pkg/logger"
+	"testing-platform/pkg/stats"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// число итераций бутстрэпа по умолчанию
+const defaultBootstrapIterations = 5000
+
+// методы построения бутстрэп-интервала
+var bootstrapMethodOptions = map[string]stats.BootstrapMethod{
+	"Перцентильный": stats.BootstrapPercentile,
+	"BCa":           stats.BootstrapBCa,
+}
+
+// bootstrapControls элементы управления режимом бутстрэпа
+type bootstrapControls struct {
+	container       *fyne.Container
+	metricSelect    *widget.Select
+	methodSelect    *widget.Select
+	iterationsEntry *widget.Entry
+	progress        *widget.ProgressBar
+	cancelBtn       *widget.Button
+
+	// отмена выполняющегося расчета, nil если расчет не идет
+	cancel context.CancelFunc
+}
+
+// newBootstrapControls создает элементы режима бутстрэпа (скрыты, пока режим не выбран)
+func (p *ExperimentStatsPanel) newBootstrapControls() *bootstrapControls {
+	c := &bootstrapControls{}
+
+	var metrics []string
+	for _, m := range db.UserMetrics {
+		metrics = append(metrics, m.Title)
+	}
+	c.metricSelect = widget.NewSelect(metrics, nil)
+	c.metricSelect.SetSelected(metrics[0])
+
+	c.methodSelect = widget.NewSelect([]string{"Перцентильный", "BCa"}, nil)
+	c.methodSelect.SetSelected("Перцентильный")
+
+	c.iterationsEntry = widget.NewEntry()
+	c.iterationsEntry.SetText(strconv.Itoa(defaultBootstrapIterations))
+
+	c.progress = widget.NewProgressBar()
+	c.cancelBtn = widget.NewButton("Отменить", func() {
+		if c.cancel != nil {
+			c.cancel()
+		}
+	})
+	c.cancelBtn.Disable()
+
+	c.container = container.NewVBox(
+		container.NewHBox(
+			widget.NewLabel("Метрика:"),
+			c.metricSelect,
+			widget.NewLabel("Интервал:"),
+			c.methodSelect,
+			widget.NewLabel("Итераций:"),
+			c.iterationsEntry,
+			c.cancelBtn,
+		),
+		c.progress,
+	)
+	c.container.Hide()
+	return c
+}
+
+// selectedMetric возвращает выбранную метрику пользователя
+func (c *bootstrapControls) selectedMetric() db.UserMetric {
+	for _, m := range db.UserMetrics {
+		if m.Title == c.metricSelect.Selected {
+			return m
+		}
+	}
+	return db.UserMetrics[0]
+}
+
+// startBootstrap запускает бутстрэп в фоне, обновляя индикатор прогресса
+func (p *ExperimentStatsPanel) startBootstrap(experimentID int) {
+	c := p.bootstrap
+	if c.cancel != nil {
+		p.statusLabel.SetText("Бутстрэп уже выполняется")
+		return
+	}
+
+	iterations, err := strconv.Atoi(strings.TrimSpace(c.iterationsEntry.Text))
+	if err != nil || iterations < 100 {
+		p.statusLabel.SetText("Число итераций должно быть целым числом не меньше 100")
+		return
+	}
+
+	metric := c.selectedMetric()
+	opts := stats.BootstrapOptions{
+		Iterations: iterations,
+		Confidence: p.selectedConfidence(),
+		Method:     bootstrapMethodOptions[c.methodSelect.Selected],
+		Seed:       uint64(experimentID),
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.cancel = cancel
+	c.cancelBtn.Enable()
+	c.progress.SetValue(0)
+	p.statusLabel.SetText(fmt.Sprintf("Бутстрэп метрики «%s» для эксперимента %d...", metric.Title, experimentID))
+
+	go func() {
+		result, err := p.mw.rep.BootstrapExperiment(ctx, experimentID, metric, opts, func(done, total int) {
+			fyne.Do(func() { c.progress.SetValue(float64(done) / float64(total)) })
+		})
+		cancel()
+
+		fyne.Do(func() {
+			c.cancel = nil
+			c.cancelBtn.Disable()
+			switch {
+			case errors.Is(err, context.Canceled):
+				p.statusLabel.SetText("Бутстрэп отменен")
+			case err != nil:
+				logger.Error("Ошибка бутстрэпа эксперимента %d: %v", experimentID, err)
+				p.statusLabel.SetText("Ошибка бутстрэпа: " + err.Error())
+			default:
+				c.progress.SetValue(1)
+				p.showResult(renderBootstrapStats(result, metric))
+				p.statusLabel.SetText(fmt.Sprintf("Бутстрэп эксперимента %d завершен", experimentID))
+			}
+		})
+	}()
+}
+
+// renderBootstrapStats отображает бутстрэп-интервалы разности метрики
+func renderBootstrapStats(result *models.BootstrapStats, metric db.UserMetric) fyne.CanvasObject {
+	method := "перцентильный"
+	if result.Method == string(stats.BootstrapBCa) {
+		method = "BCa"
+	}
+	title := widget.NewLabelWithStyle(fmt.Sprintf("Бутстрэп «%s» по пользователям (%d итераций, %s интервал %.0f%%)",
+		metric.Title, result.Iterations, method, result.Confidence*100),
+		fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+
+	if len(result.Comparisons) == 0 {
+		return container.NewVBox(title, widget.NewLabel("Недостаточно данных для сравнения групп"))
+	}
+
+	var sb strings.Builder
+	for _, c := range result.Comparisons {
+		verdict := "различие не значимо"
+		if c.CILower > 0 || c.CIUpper < 0 {
+			verdict = "различие статистически значимо"
+		}
+		fmt.Fprintf(&sb, "%s (%d польз.) против %s (%d польз.): %s\n",
+			c.TreatmentGroup, result.Users[c.TreatmentGroup], c.ControlGroup, result.Users[c.ControlGroup], verdict)
+		fmt.Fprintf(&sb, "  Значение метрики: %.4f против %.4f, разность %+.4f\n",
+			c.TreatmentValue, c.ControlValue, c.Difference)
+		fmt.Fprintf(&sb, "  Стандартная ошибка: %.4f   Интервал разности: [%+.4f; %+.4f]   итераций учтено: %d\n",
+			c.StandardError, c.CILower, c.CIUpper, c.Iterations)
+	}
+	return container.NewVBox(title, monospaceLabel(strings.TrimSuffix(sb.String(), "\n")))
+}
@@ -0,0 +1,276 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"testing-platform/db"
+	"testing-platform/db/models"
+	"testing-platform/pkg/logger"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+// editVariantRow поля одного варианта в форме редактирования
+type editVariantRow struct {
+	algorithm *widget.Select
+	weight    *widget.Entry
+	params    *widget.Entry
+}
+
+// ExperimentEditWindow окно редактирования существующего эксперимента
+type ExperimentEditWindow struct {
+	mw     *MainWindow
+	window fyne.Window
+
+	infoLabel        *widget.Label
+	rulesLabel       *widget.Label
+	nameEntry        *widget.Entry
+	userPercentEntry *widget.Entry
+	tagsEntry        *widget.Entry
+	variantRows      *fyne.Container
+	addVariantBtn    *widget.Button
+	removeVariantBtn *widget.Button
+
+	experimentID int
+	version      int // версия эксперимента на момент загрузки, для оптимистичной блокировки
+	variants     []editVariantRow
+	registry     map[string]models.Algorithm
+	options      []string // алгоритмы для новых вариантов: неустаревшие из реестра
+}
+
+// NewExperimentEditWindow создает окно редактирования эксперимента experimentID
+func NewExperimentEditWindow(mw *MainWindow, experimentID int) *ExperimentEditWindow {
+	w := &ExperimentEditWindow{
+		mw:           mw,
+		window:       mw.app.NewWindow(fmt.Sprintf("Редактирование эксперимента %d", experimentID)),
+		experimentID: experimentID,
+		registry:     make(map[string]models.Algorithm),
+	}
+	w.window.Resize(fyne.NewSize(800, 650))
+	w.loadRegistry()
+	w.buildUI()
+	w.load()
+	return w
+}
+
+// loadRegistry загружает реестр алгоритмов: схемы нужны и для устаревших алгоритмов вариантов
+func (w *ExperimentEditWindow) loadRegistry() {
+	algorithms, err := w.mw.rep.GetAlgorithms(context.Background(), true)
+	if err != nil {
+		logger.Error("Ошибка загрузки реестра алгоритмов: %v", err)
+		return
+	}
+	for _, a := range algorithms {
+		w.registry[a.Name] = a
+		if !a.Deprecated {
+			w.options = append(w.options, a.Name)
+		}
+	}
+}
+
+func (w *ExperimentEditWindow) buildUI() {
+	w.infoLabel = widget.NewLabel("")
+	w.rulesLabel = widget.NewLabel("")
+	w.rulesLabel.Wrapping = fyne.TextWrapWord
+	w.rulesLabel.TextStyle = fyne.TextStyle{Italic: true}
+	w.nameEntry = widget.NewEntry()
+	w.userPercentEntry = widget.NewEntry()
+	w.userPercentEntry.SetPlaceHolder("Например: 10.5")
+	w.tagsEntry = widget.NewEntry()
+	w.tagsEntry.SetPlaceHolder("Теги через запятую")
+	w.variantRows = container.NewVBox()
+
+	w.addVariantBtn = widget.NewButton("Добавить вариант", func() {
+		w.addVariant(models.Variant{})
+		w.updateVariantButtons()
+	})
+	w.removeVariantBtn = widget.NewButton("Удалить последний", func() {
+		if n := len(w.variants); n > 2 {
+			w.variants = w.variants[:n-1]
+			w.variantRows.Remove(w.variantRows.Objects[n-1])
+		}
+		w.updateVariantButtons()
+	})
+
+	form := widget.NewForm(
+		widget.NewFormItem("Название", w.nameEntry),
+		widget.NewFormItem("Процент пользователей", w.userPercentEntry),
+		widget.NewFormItem("Теги", w.tagsEntry),
+		widget.NewFormItem("Варианты", container.NewVBox(w.variantRows,
+			container.NewHBox(w.addVariantBtn, w.removeVariantBtn))),
+	)
+
+	saveBtn := widget.NewButton("Сохранить", w.save)
+	reloadBtn := widget.NewButton("Загрузить заново", w.load)
+	closeBtn := widget.NewButton("Закрыть", func() { w.window.Close() })
+
+	w.window.SetContent(container.NewPadded(container.NewBorder(
+		container.NewVBox(w.infoLabel, w.rulesLabel, widget.NewSeparator()),
+		container.NewHBox(saveBtn, reloadBtn, layout.NewSpacer(), closeBtn),
+		nil, nil,
+		container.NewVScroll(form),
+	)))
+}
+
+// load загружает эксперимент и заполняет форму
+func (w *ExperimentEditWindow) load() {
+	exp, err := w.mw.rep.GetExperiment(context.Background(), w.experimentID)
+	if err != nil {
+		logger.Error("Ошибка загрузки эксперимента %d: %v", w.experimentID, err)
+		w.infoLabel.SetText("Ошибка загрузки эксперимента: " + err.Error())
+		return
+	}
+	w.version = exp.Version
+
+	info := fmt.Sprintf("Эксперимент %d, статус «%s», версия %d", exp.ID, models.StatusTitle(exp.Status), exp.Version)
+	if exp.LayerID != 0 {
+		info += fmt.Sprintf(", слой %d [%.2f%%; %.2f%%)", exp.LayerID, exp.LayerStart, exp.LayerEnd)
+	}
+	w.infoLabel.SetText(info)
+	w.rulesLabel.SetText(editRules(exp.Status))
+
+	w.nameEntry.SetText(exp.Name)
+	w.userPercentEntry.SetText(strconv.FormatFloat(exp.UserPercent, 'f', -1, 64))
+	w.tagsEntry.SetText(strings.Join(exp.Tags, ", "))
+
+	w.variants = nil
+	w.variantRows.RemoveAll()
+	for _, v := range exp.VariantList() {
+		w.addVariant(v)
+	}
+	w.updateVariantButtons()
+}
+
+// editRules описывает, что можно изменить у эксперимента в статусе status
+func editRules(status string) string {
+	switch status {
+	case models.StatusDraft, models.StatusScheduled:
+		return "Пока в эксперименте нет пользователей, меняется все: название, теги, процент пользователей и варианты. " +
+			"Статус и расписание меняются в окне «Статусы и история экспериментов»."
+	case models.StatusRunning, models.StatusPaused:
+		return "Эксперимент уже идет: меняются название и теги, процент пользователей можно только увеличить " +
+			"(кроме экспериментов слоя); варианты, алгоритмы, веса и параметры не меняются."
+	default:
+		return "Эксперимент окончен: меняются только название и теги."
+	}
+}
+
+// addVariant добавляет в форму строку варианта
+func (w *ExperimentEditWindow) addVariant(v models.Variant) {
+	options := w.options
+	if v.Algorithm != "" && !slices.Contains(options, v.Algorithm) {
+		// устаревший алгоритм остается доступным для уже назначенного варианта
+		options = append(slices.Clone(options), v.Algorithm)
+	}
+
+	row := editVariantRow{params: widget.NewEntry(), weight: widget.NewEntry()}
+	row.params.SetPlaceHolder(paramsPlaceholder(w.registry[v.Algorithm]))
+	row.params.SetText(models.FormatParams(v.Params))
+	row.algorithm = widget.NewSelect(options, func(selected string) {
+		row.params.SetPlaceHolder(paramsPlaceholder(w.registry[selected]))
+		row.params.Refresh()
+	})
+	row.algorithm.PlaceHolder = "Выберите алгоритм"
+	if v.Algorithm != "" {
+		row.algorithm.SetSelected(v.Algorithm)
+	}
+	row.weight.SetPlaceHolder("Вес, %")
+	if v.Weight > 0 {
+		row.weight.SetText(strconv.FormatFloat(v.Weight, 'f', -1, 64))
+	}
+
+	label := widget.NewLabel("Группа " + models.VariantName(len(w.variants)))
+	weightBox := container.NewGridWrap(fyne.NewSize(90, row.weight.MinSize().Height), row.weight)
+	w.variants = append(w.variants, row)
+	w.variantRows.Add(container.NewBorder(nil, row.params, label, weightBox, row.algorithm))
+}
+
+// updateVariantButtons включает кнопки добавления и удаления вариантов по их числу
+func (w *ExperimentEditWindow) updateVariantButtons() {
+	if len(w.variants) >= models.MaxVariants {
+		w.addVariantBtn.Disable()
+	} else {
+		w.addVariantBtn.Enable()
+	}
+	if len(w.variants) <= 2 {
+		w.removeVariantBtn.Disable()
+	} else {
+		w.removeVariantBtn.Enable()
+	}
+}
+
+// formExperiment собирает эксперимент из полей формы
+func (w *ExperimentEditWindow) formExperiment() (*models.Experiment, error) {
+	userPercent, err := strconv.ParseFloat(strings.TrimSpace(w.userPercentEntry.Text), 64)
+	if err != nil {
+		return nil, errors.New("процент пользователей должен быть числом (например: 10.5)")
+	}
+
+	var variants []models.Variant
+	for i, row := range w.variants {
+		name := models.VariantName(i)
+		if row.algorithm.Selected == "" {
+			return nil, errors.New("выберите алгоритм для группы " + name)
+		}
+		weight, err := strconv.ParseFloat(strings.TrimSpace(row.weight.Text), 64)
+		if err != nil {
+			return nil, fmt.Errorf("вес группы %s должен быть числом", name)
+		}
+		params, err := parseParams(row.params.Text)
+		if err != nil {
+			return nil, fmt.Errorf("параметры группы %s: %w", name, err)
+		}
+		variants = append(variants, models.Variant{Name: name, Algorithm: row.algorithm.Selected, Weight: weight, Params: params})
+	}
+
+	return &models.Experiment{
+		ID:          w.experimentID,
+		Name:        strings.TrimSpace(w.nameEntry.Text),
+		UserPercent: userPercent,
+		Tags:        parseTags(w.tagsEntry.Text),
+		Variants:    variants,
+		Version:     w.version,
+	}, nil
+}
+
+// save сохраняет изменения; при изменении эксперимента другим пользователем предлагает загрузить его заново
+func (w *ExperimentEditWindow) save() {
+	exp, err := w.formExperiment()
+	if err != nil {
+		showUserError(w.window, err.Error())
+		return
+	}
+	err = w.mw.rep.UpdateExperiment(w.mw.actorContext(), exp)
+	if errors.Is(err, db.ErrVersionConflict) {
+		dialog.ShowConfirm("Конфликт изменений",
+			"Эксперимент изменен после загрузки формы. Загрузить актуальные данные? Несохраненные правки будут потеряны.",
+			func(ok bool) {
+				if ok {
+					w.load()
+				}
+			}, w.window)
+		return
+	}
+	if err != nil {
+		showUserError(w.window, "Не удалось сохранить эксперимент: "+err.Error())
+		return
+	}
+
+	logger.Info("Эксперимент %d изменен через форму редактирования", exp.ID)
+	w.load()
+	w.mw.NotifyAllDataWindows()
+	dialog.ShowInformation("Сохранено", fmt.Sprintf("Эксперимент сохранен (версия %d)", exp.Version), w.window)
+}
+
+// Show отображает окно
+func (w *ExperimentEditWindow) Show() {
+	w.window.Show()
+}
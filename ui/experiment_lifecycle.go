@@ -0,0 +1,307 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing-platform/db/models"
+	"testing-platform/pkg/logger"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+// формат ввода плановых дат эксперимента
+const plannedTimeLayout = "2006-01-02 15:04"
+
+// parsePlannedTime разбирает необязательную плановую дату; пустая строка — дата не задана
+func parsePlannedTime(s string) (*time.Time, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(plannedTimeLayout, s)
+	if err != nil {
+		return nil, fmt.Errorf("дата %q должна быть в формате ГГГГ-ММ-ДД ЧЧ:ММ", s)
+	}
+	return &t, nil
+}
+
+// formatPlannedTime форматирует необязательную плановую дату для поля ввода
+func formatPlannedTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(plannedTimeLayout)
+}
+
+// statusTitles возвращает названия статусов для выпадающего списка
+func statusTitles(statuses []string) []string {
+	titles := make([]string, len(statuses))
+	for i, s := range statuses {
+		titles[i] = models.StatusTitle(s)
+	}
+	return titles
+}
+
+// statusFromTitle возвращает статус по его названию; пустая строка, если название не найдено
+func statusFromTitle(title string) string {
+	for status, t := range models.StatusTitles {
+		if t == title {
+			return status
+		}
+	}
+	return ""
+}
+
+// LifecycleWindow окно смены статуса эксперимента, истории его статусов и журнала изменений
+type LifecycleWindow struct {
+	mw     *MainWindow
+	window fyne.Window
+
+	experimentSelect *widget.Select
+	currentLabel     *widget.Label
+	statusSelect     *widget.Select
+	reasonEntry      *widget.Entry
+	historyLabel     *widget.Label
+
+	plannedStartEntry *widget.Entry
+	plannedEndEntry   *widget.Entry
+	changesLabel      *widget.Label
+
+	experimentIDs map[string]int
+}
+
+// NewLifecycleWindow создает окно жизненного цикла экспериментов
+func NewLifecycleWindow(mw *MainWindow) *LifecycleWindow {
+	w := &LifecycleWindow{
+		mw:            mw,
+		window:        mw.app.NewWindow("Статусы и история экспериментов"),
+		experimentIDs: make(map[string]int),
+	}
+	w.window.Resize(fyne.NewSize(900, 650))
+	w.buildUI()
+	return w
+}
+
+func (w *LifecycleWindow) buildUI() {
+	w.experimentSelect = widget.NewSelect([]string{}, func(string) { w.loadExperiment() })
+	w.experimentSelect.PlaceHolder = "Выберите эксперимент"
+	w.currentLabel = widget.NewLabel("")
+	w.statusSelect = widget.NewSelect([]string{}, nil)
+	w.statusSelect.PlaceHolder = "Новый статус"
+	w.reasonEntry = widget.NewEntry()
+	w.reasonEntry.SetPlaceHolder("Например: набран размер выборки")
+	w.historyLabel = monospaceLabel("")
+	w.changesLabel = monospaceLabel("")
+	w.plannedStartEntry = widget.NewEntry()
+	w.plannedStartEntry.SetPlaceHolder("ГГГГ-ММ-ДД ЧЧ:ММ")
+	w.plannedEndEntry = widget.NewEntry()
+	w.plannedEndEntry.SetPlaceHolder("ГГГГ-ММ-ДД ЧЧ:ММ")
+
+	transitionBtn := widget.NewButton("Сменить статус", w.transition)
+	scheduleBtn := widget.NewButton("Сохранить расписание", w.saveSchedule)
+	closeBtn := widget.NewButton("Закрыть", func() { w.window.Close() })
+
+	help := widget.NewLabel("Черновик и запланированный эксперимент можно запустить; запущенный — поставить на паузу, " +
+		"остановить досрочно или завершить; остановленный и завершенный — отправить в архив. " +
+		"Пользователи распределяются только в запущенные эксперименты, архивные освобождают диапазон своего слоя. " +
+		"Запланированный эксперимент запускается автоматически в плановую дату запуска, " +
+		"запущенный и приостановленный — завершаются в плановую дату завершения.")
+	help.Wrapping = fyne.TextWrapWord
+
+	form := widget.NewForm(
+		widget.NewFormItem("Текущий статус", w.currentLabel),
+		widget.NewFormItem("Новый статус", w.statusSelect),
+		widget.NewFormItem("Причина", w.reasonEntry),
+		widget.NewFormItem("Плановый запуск", w.plannedStartEntry),
+		widget.NewFormItem("Плановое завершение", w.plannedEndEntry),
+	)
+
+	w.window.SetContent(container.NewPadded(container.NewBorder(
+		container.NewVBox(
+			container.NewHBox(widget.NewLabel("Эксперимент:"), w.experimentSelect),
+			form,
+			container.NewHBox(transitionBtn, scheduleBtn),
+			help,
+		),
+		container.NewHBox(layout.NewSpacer(), closeBtn),
+		nil, nil,
+		container.NewAppTabs(
+			container.NewTabItem("История статусов", container.NewScroll(w.historyLabel)),
+			container.NewTabItem("История изменений", container.NewScroll(w.changesLabel)),
+		),
+	)))
+
+	w.loadExperiments()
+}
+
+// loadExperiments загружает список экспериментов
+func (w *LifecycleWindow) loadExperiments() {
+	experiments, err := w.mw.rep.GetExperiments(context.Background(), models.ExperimentFilter{})
+	if err != nil {
+		logger.Error("Ошибка загрузки списка экспериментов: %v", err)
+		w.currentLabel.SetText("Ошибка загрузки списка экспериментов: " + err.Error())
+		return
+	}
+	selected := w.experimentSelect.Selected
+	w.experimentIDs = make(map[string]int)
+	var options []string
+	for _, exp := range experiments {
+		option := fmt.Sprintf("%d: %s", exp.ID, exp.Name)
+		options = append(options, option)
+		w.experimentIDs[option] = exp.ID
+	}
+	w.experimentSelect.Options = options
+	w.experimentSelect.Refresh()
+	if _, ok := w.experimentIDs[selected]; ok {
+		w.loadExperiment()
+	}
+}
+
+// loadExperiment показывает текущий статус выбранного эксперимента, доступные переходы и историю
+func (w *LifecycleWindow) loadExperiment() {
+	experimentID, ok := w.experimentIDs[w.experimentSelect.Selected]
+	if !ok {
+		return
+	}
+	ctx := context.Background()
+	exp, err := w.mw.rep.GetExperiment(ctx, experimentID)
+	if err != nil {
+		w.currentLabel.SetText("Ошибка загрузки эксперимента: " + err.Error())
+		return
+	}
+	w.currentLabel.SetText(fmt.Sprintf("%s (с %s)", models.StatusTitle(exp.Status),
+		exp.StatusChangedAt.Format("02.01.2006 15:04")))
+	w.plannedStartEntry.SetText(formatPlannedTime(exp.PlannedStart))
+	w.plannedEndEntry.SetText(formatPlannedTime(exp.PlannedEnd))
+
+	next := models.NextStatuses(exp.Status)
+	w.statusSelect.Options = statusTitles(next)
+	w.statusSelect.ClearSelected()
+	if len(next) == 0 {
+		w.statusSelect.PlaceHolder = "Переходов нет"
+	} else {
+		w.statusSelect.PlaceHolder = "Новый статус"
+	}
+	w.statusSelect.Refresh()
+
+	history, err := w.mw.rep.GetStatusHistory(ctx, experimentID)
+	if err != nil {
+		w.historyLabel.SetText("Ошибка загрузки истории: " + err.Error())
+		return
+	}
+	w.historyLabel.SetText(formatStatusHistory(history))
+
+	changes, err := w.mw.rep.GetExperimentChanges(ctx, experimentID)
+	if err != nil {
+		w.changesLabel.SetText("Ошибка загрузки журнала изменений: " + err.Error())
+		return
+	}
+	w.changesLabel.SetText(formatExperimentChanges(changes))
+}
+
+// названия операций журнала изменений
+var changeOperationTitles = map[string]string{
+	"INSERT": "создание",
+	"UPDATE": "изменение",
+	"DELETE": "удаление",
+}
+
+// formatExperimentChanges формирует журнал изменений эксперимента: для каждой записи — автор,
+// время и различающиеся поля снимков «было → стало»
+func formatExperimentChanges(changes []models.ExperimentChange) string {
+	if len(changes) == 0 {
+		return "Журнал изменений пуст"
+	}
+	var b strings.Builder
+	for _, c := range changes {
+		subject := "эксперимент"
+		if c.Entity == models.ChangeEntityVariant {
+			name, _ := c.After["name"].(string)
+			if name == "" {
+				name, _ = c.Before["name"].(string)
+			}
+			subject = "вариант " + name
+		}
+		fmt.Fprintf(&b, "%s  %s: %s (%s)\n", c.ChangedAt.Format("02.01.2006 15:04:05"),
+			subject, changeOperationTitles[c.Operation], c.ChangedBy)
+		for _, f := range c.Diff() {
+			fmt.Fprintf(&b, "    %-20s %s → %s\n", f.Field, f.Before, f.After)
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// formatStatusHistory формирует историю статусов эксперимента, новые смены сверху
+func formatStatusHistory(history []models.StatusChange) string {
+	if len(history) == 0 {
+		return "История статусов пуста"
+	}
+	var b strings.Builder
+	for i := len(history) - 1; i >= 0; i-- {
+		c := history[i]
+		transition := models.StatusTitle(c.ToStatus)
+		if c.FromStatus != "" {
+			transition = models.StatusTitle(c.FromStatus) + " → " + transition
+		}
+		fmt.Fprintf(&b, "%s  %-28s %s\n", c.ChangedAt.Format("02.01.2006 15:04:05"), transition, c.Reason)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// transition переводит выбранный эксперимент в новый статус
+func (w *LifecycleWindow) transition() {
+	experimentID, ok := w.experimentIDs[w.experimentSelect.Selected]
+	if !ok {
+		dialog.ShowInformation("Не выбран эксперимент", "Выберите эксперимент из списка", w.window)
+		return
+	}
+	status := statusFromTitle(w.statusSelect.Selected)
+	if status == "" {
+		dialog.ShowInformation("Не выбран статус", "Выберите новый статус эксперимента", w.window)
+		return
+	}
+	if err := w.mw.rep.TransitionExperiment(w.mw.actorContext(), experimentID, status, w.reasonEntry.Text); err != nil {
+		dialog.ShowError(err, w.window)
+		return
+	}
+	w.reasonEntry.SetText("")
+	w.loadExperiment()
+	w.mw.NotifyAllDataWindows()
+}
+
+// saveSchedule сохраняет плановые даты выбранного эксперимента
+func (w *LifecycleWindow) saveSchedule() {
+	experimentID, ok := w.experimentIDs[w.experimentSelect.Selected]
+	if !ok {
+		dialog.ShowInformation("Не выбран эксперимент", "Выберите эксперимент из списка", w.window)
+		return
+	}
+	plannedStart, err := parsePlannedTime(w.plannedStartEntry.Text)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("плановый запуск: %w", err), w.window)
+		return
+	}
+	plannedEnd, err := parsePlannedTime(w.plannedEndEntry.Text)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("плановое завершение: %w", err), w.window)
+		return
+	}
+	if err := w.mw.rep.SetExperimentSchedule(w.mw.actorContext(), experimentID, plannedStart, plannedEnd); err != nil {
+		dialog.ShowError(err, w.window)
+		return
+	}
+	w.loadExperiment()
+	w.mw.NotifyAllDataWindows()
+}
+
+// Show отображает окно
+func (w *LifecycleWindow) Show() {
+	w.window.Show()
+}
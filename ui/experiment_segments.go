@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"testing-platform/db/models"
+	"testing-platform/pkg/logger"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// измерения сегментации в том порядке, в котором они показываются в списке
+var segmentDimensionTitles = []struct {
+	title     string
+	dimension string
+}{
+	{"Устройство", models.SegmentByDevice},
+	{"Страна", models.SegmentByCountry},
+	{"Когорта", models.SegmentByCohort},
+	{"Теги эксперимента", models.SegmentByTag},
+}
+
+// segmentControls элементы управления режимом сегментного анализа
+type segmentControls struct {
+	container       *fyne.Container
+	dimensionSelect *widget.Select
+	allExperiments  *widget.Check
+}
+
+// newSegmentControls создает элементы режима сегментов (скрыты, пока режим не выбран)
+func newSegmentControls() *segmentControls {
+	c := &segmentControls{}
+
+	var titles []string
+	for _, d := range segmentDimensionTitles {
+		titles = append(titles, d.title)
+	}
+	c.dimensionSelect = widget.NewSelect(titles, nil)
+	c.dimensionSelect.SetSelected(titles[0])
+	c.allExperiments = widget.NewCheck("По всем экспериментам (для тегов)", nil)
+
+	c.container = container.NewHBox(widget.NewLabel("Сегментировать по:"), c.dimensionSelect, c.allExperiments)
+	c.container.Hide()
+	return c
+}
+
+// selectedDimension возвращает выбранное измерение сегментации
+func (c *segmentControls) selectedDimension() string {
+	for _, d := range segmentDimensionTitles {
+		if d.title == c.dimensionSelect.Selected {
+			return d.dimension
+		}
+	}
+	return models.SegmentByDevice
+}
+
+// calculateAllExperimentSegments рассчитывает сегменты по всем экспериментам сразу
+func (p *ExperimentStatsPanel) calculateAllExperimentSegments() {
+	report, err := p.mw.rep.GetSegmentStats(context.Background(), 0, p.segments.selectedDimension(), p.selectedConfidence())
+	if err != nil {
+		logger.Error("Ошибка сегментного анализа по всем экспериментам: %v", err)
+		p.statusLabel.SetText("Ошибка сегментного анализа: " + err.Error())
+		return
+	}
+	p.showResult(renderSegmentReport(report, nil))
+	p.statusLabel.SetText("Сегментный анализ по всем экспериментам выполнен")
+}
+
+// renderSegmentReport отображает статистику групп и прирост CTR в каждом сегменте;
+// adjusted — проверки эксперимента с поправками на множественность (nil, если их нет)
+func renderSegmentReport(report *models.SegmentReport, adjusted *models.MultipleTestingReport) fyne.CanvasObject {
+	scope := fmt.Sprintf("эксперимент %d", report.ExperimentID)
+	if report.ExperimentID == 0 {
+		scope = "все эксперименты"
+	}
+	objects := []fyne.CanvasObject{
+		widget.NewLabelWithStyle(fmt.Sprintf("Сегменты по измерению «%s» (%s, доверие %.0f%%)",
+			report.Dimension, scope, report.Confidence*100), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+	}
+	if len(report.Segments) == 0 {
+		return container.NewVBox(append(objects, widget.NewLabel("Нет данных для сегментации"))...)
+	}
+
+	for _, segment := range report.Segments {
+		var groups []string
+		for name := range segment.Groups {
+			groups = append(groups, name)
+		}
+		sort.Strings(groups)
+
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "Сегмент: %s\n", segment.Segment)
+		for _, name := range groups {
+			g := segment.Groups[name]
+			fmt.Fprintf(&sb, "  Группа %-4s пользователей %6d   показов %8d   кликов %7d   CTR %8s   оценка %.2f\n",
+				name, g.Users, g.TotalRecommendations, g.TotalClicks, formatPercent(g.CTR), g.AvgRating)
+		}
+		if len(segment.Comparisons) == 0 && report.ExperimentID != 0 {
+			sb.WriteString("  Недостаточно данных для сравнения групп\n")
+		}
+		for _, c := range segment.Comparisons {
+			verdict := "не значимо"
+			if c.Significant {
+				verdict = "значимо"
+			}
+			fmt.Fprintf(&sb, "  %s против %s: %+.2f п.п. (%+.2f%%), интервал [%+.2f; %+.2f] п.п., p-value = %.4f%s — %s\n",
+				c.TreatmentGroup, c.ControlGroup, c.AbsoluteLift*100, c.RelativeLift*100,
+				c.CILower*100, c.CIUpper*100, c.PValue,
+				adjustedPValues(adjusted, models.PValueFamilySegments,
+					models.SegmentPValueTest(report.Dimension, segment.Segment), c.TreatmentGroup),
+				verdict)
+		}
+		objects = append(objects, monospaceLabel(strings.TrimSuffix(sb.String(), "\n")))
+	}
+	if report.ExperimentID == 0 {
+		objects = append(objects, widget.NewLabel("По всем экспериментам показываются только итоги сегментов: "+
+			"группы разных экспериментов несопоставимы, сравнивайте группы внутри одного эксперимента."))
+	} else {
+		objects = append(objects, widget.NewLabel("Сравнения в сегментах — отдельные проверки: "+
+			"ориентируйтесь на p-значения с поправкой на множественные сравнения (режим «Все проверки»)."))
+	}
+	return container.NewVBox(objects...)
+}
@@ -0,0 +1,728 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"testing-platform/db/models"
+	"testing-platform/pkg/logger"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// уровни доверия, доступные в сводном окне
+var confidenceOptions = map[string]float64{
+	"90%": 0.90,
+	"95%": 0.95,
+	"99%": 0.99,
+}
+
+// режимы анализа в сводном окне
+const (
+	analysisModeCTR       = "Конверсия (CTR)"
+	analysisModeRatings   = "Оценки"
+	analysisModeBayesian  = "Байесовский (CTR)"
+	analysisModeBootstrap = "Бутстрэп"
+	analysisModeCUPED     = "CUPED (CTR)"
+	analysisModeMetrics   = "Метрики реестра"
+	analysisModeMultiple  = "Все проверки (поправки)"
+	analysisModeSegments  = "Сегменты (CTR)"
+)
+
+// ExperimentStatsPanel панель статистического анализа эксперимента в сводном окне
+type ExperimentStatsPanel struct {
+	mw     *MainWindow
+	window fyne.Window
+
+	experimentSelect *widget.Select
+	modeSelect       *widget.Select
+	confidenceSelect *widget.Select
+	statusLabel      *widget.Label
+	resultContainer  *fyne.Container
+
+	// элементы режимов бутстрэпа и сегментов
+	bootstrap *bootstrapControls
+	segments  *segmentControls
+
+	// соответствие подписи в списке и ID эксперимента
+	experimentIDs map[string]int
+}
+
+// NewExperimentStatsPanel создает панель статистики для окна window
+func NewExperimentStatsPanel(mw *MainWindow, window fyne.Window) *ExperimentStatsPanel {
+	return &ExperimentStatsPanel{
+		mw:            mw,
+		window:        window,
+		experimentIDs: make(map[string]int),
+	}
+}
+
+// Build создает элементы панели
+func (p *ExperimentStatsPanel) Build() fyne.CanvasObject {
+	p.experimentSelect = widget.NewSelect([]string{}, nil)
+	p.experimentSelect.PlaceHolder = "Выберите эксперимент"
+
+	p.bootstrap = p.newBootstrapControls()
+	p.segments = newSegmentControls()
+
+	p.modeSelect = widget.NewSelect([]string{analysisModeCTR, analysisModeRatings, analysisModeBayesian, analysisModeCUPED,
+		analysisModeMetrics, analysisModeSegments, analysisModeMultiple, analysisModeBootstrap},
+		func(mode string) {
+			showIf(p.bootstrap.container, mode == analysisModeBootstrap)
+			showIf(p.segments.container, mode == analysisModeSegments)
+		})
+	p.modeSelect.SetSelected(analysisModeCTR)
+
+	p.confidenceSelect = widget.NewSelect([]string{"90%", "95%", "99%"}, nil)
+	p.confidenceSelect.SetSelected("95%")
+
+	p.statusLabel = widget.NewLabel("Выберите эксперимент и нажмите «Рассчитать»")
+	p.statusLabel.Wrapping = fyne.TextWrapWord
+
+	p.resultContainer = container.NewVBox()
+
+	calculateBtn := widget.NewButton("Рассчитать", p.calculate)
+	lookBtn := widget.NewButton("Зафиксировать просмотр", p.recordSequentialLook)
+	reloadBtn := widget.NewButton("Обновить список", p.LoadExperiments)
+
+	controls := container.NewHBox(
+		widget.NewLabel("Эксперимент:"),
+		p.experimentSelect,
+		widget.NewLabel("Режим:"),
+		p.modeSelect,
+		widget.NewLabel("Уровень доверия:"),
+		p.confidenceSelect,
+		calculateBtn,
+		lookBtn,
+		reloadBtn,
+	)
+
+	p.LoadExperiments()
+
+	return container.NewBorder(
+		container.NewVBox(
+			widget.NewLabelWithStyle("Статистический анализ", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			controls,
+			p.bootstrap.container,
+			p.segments.container,
+			p.statusLabel,
+		),
+		nil, nil, nil,
+		container.NewVScroll(p.resultContainer),
+	)
+}
+
+// LoadExperiments загружает список экспериментов в выпадающий список
+func (p *ExperimentStatsPanel) LoadExperiments() {
+	experiments, err := p.mw.rep.GetExperiments(context.Background(), models.ExperimentFilter{})
+	if err != nil {
+		logger.Error("Ошибка загрузки списка экспериментов: %v", err)
+		p.statusLabel.SetText("Ошибка загрузки списка экспериментов: " + err.Error())
+		return
+	}
+
+	options := make([]string, 0, len(experiments))
+	p.experimentIDs = make(map[string]int, len(experiments))
+	for _, exp := range experiments {
+		option := fmt.Sprintf("%d: %s", exp.ID, exp.Name)
+		options = append(options, option)
+		p.experimentIDs[option] = exp.ID
+	}
+
+	p.experimentSelect.Options = options
+	p.experimentSelect.Refresh()
+}
+
+// showIf показывает или скрывает элемент
+func showIf(object fyne.CanvasObject, visible bool) {
+	if visible {
+		object.Show()
+	} else {
+		object.Hide()
+	}
+}
+
+// selectedExperimentID возвращает ID выбранного эксперимента
+func (p *ExperimentStatsPanel) selectedExperimentID() (int, bool) {
+	id, ok := p.experimentIDs[p.experimentSelect.Selected]
+	return id, ok
+}
+
+// selectedConfidence возвращает выбранный уровень доверия
+func (p *ExperimentStatsPanel) selectedConfidence() float64 {
+	if level, ok := confidenceOptions[p.confidenceSelect.Selected]; ok {
+		return level
+	}
+	return models.DefaultConfidence
+}
+
+// calculate рассчитывает статистику выбранного эксперимента
+func (p *ExperimentStatsPanel) calculate() {
+	if p.modeSelect.Selected == analysisModeSegments && p.segments.allExperiments.Checked {
+		p.calculateAllExperimentSegments()
+		return
+	}
+
+	experimentID, ok := p.selectedExperimentID()
+	if !ok {
+		dialog.ShowInformation("Не выбран эксперимент", "Выберите эксперимент из списка", p.window)
+		return
+	}
+
+	if p.modeSelect.Selected == analysisModeBootstrap {
+		p.startBootstrap(experimentID)
+		return
+	}
+
+	p.statusLabel.SetText("Расчет статистики...")
+	ctx := context.Background()
+	confidence := p.selectedConfidence()
+
+	// p-значения с поправками на множественность показываются рядом с исходными
+	var adjusted *models.MultipleTestingReport
+	if mode := p.modeSelect.Selected; mode != analysisModeBayesian && mode != analysisModeMultiple {
+		adjusted = p.multipleTestingReport(ctx, experimentID, confidence)
+	}
+
+	var result fyne.CanvasObject
+	var err error
+	switch p.modeSelect.Selected {
+	case analysisModeRatings:
+		var stats *models.RatingStats
+		if stats, err = p.mw.rep.GetRatingStats(ctx, experimentID, confidence); err == nil {
+			dist, distErr := p.mw.rep.GetRatingDistribution(ctx, experimentID)
+			if distErr != nil {
+				logger.Warn("Распределение оценок эксперимента %d не получено: %v", experimentID, distErr)
+			}
+			result = renderRatingStats(stats, dist, adjusted)
+		}
+	case analysisModeBayesian:
+		var stats *models.BayesianStats
+		if stats, err = p.mw.rep.GetBayesianStats(ctx, experimentID, confidence); err == nil {
+			result = renderBayesianStats(stats)
+		}
+	case analysisModeCUPED:
+		var stats *models.CUPEDStats
+		if stats, err = p.mw.rep.GetCUPEDStats(ctx, experimentID, confidence); err == nil {
+			result = renderCUPEDStats(stats, adjusted)
+		}
+	case analysisModeMetrics:
+		var report *models.MetricsReport
+		if report, err = p.mw.rep.EvaluateMetrics(ctx, experimentID, confidence); err == nil {
+			result = renderMetricsReport(report, adjusted)
+		}
+	case analysisModeMultiple:
+		var report *models.MultipleTestingReport
+		if report, err = p.mw.rep.GetMultipleTestingReport(ctx, experimentID, 1-confidence); err == nil {
+			result = renderMultipleTestingReport(report)
+		}
+	case analysisModeSegments:
+		var report *models.SegmentReport
+		if report, err = p.mw.rep.GetSegmentStats(ctx, experimentID, p.segments.selectedDimension(), confidence); err == nil {
+			result = renderSegmentReport(report, adjusted)
+		}
+	default:
+		var stats *models.ExperimentStats
+		if stats, err = p.mw.rep.GetExperimentStatsWithConfidence(ctx, experimentID, confidence); err == nil {
+			result = renderCTRStats(stats, adjusted)
+		}
+	}
+	if err != nil {
+		logger.Error("Ошибка расчета статистики эксперимента %d: %v", experimentID, err)
+		p.statusLabel.SetText("Ошибка расчета статистики: " + err.Error())
+		return
+	}
+
+	objects := []fyne.CanvasObject{p.sequentialStatus(ctx, experimentID), widget.NewSeparator(), result}
+	if warning := p.srmWarning(ctx, experimentID); warning != nil {
+		objects = append([]fyne.CanvasObject{warning}, objects...)
+	}
+	p.showResult(objects...)
+	p.statusLabel.SetText(fmt.Sprintf("Статистика эксперимента %d рассчитана (%s)", experimentID, p.modeSelect.Selected))
+}
+
+// multipleTestingReport возвращает все проверки эксперимента с поправками на множественность
+// или nil, если их не удалось рассчитать
+func (p *ExperimentStatsPanel) multipleTestingReport(ctx context.Context, experimentID int, confidence float64) *models.MultipleTestingReport {
+	report, err := p.mw.rep.GetMultipleTestingReport(ctx, experimentID, 1-confidence)
+	if err != nil {
+		logger.Warn("Поправки на множественность для эксперимента %d не рассчитаны: %v", experimentID, err)
+		return nil
+	}
+	return report
+}
+
+// adjustedPValues форматирует p-значения проверки с поправками Бонферрони и Бенджамини–Хохберга
+// для вывода рядом с исходным; пустая строка, если проверки нет в отчете
+func adjustedPValues(report *models.MultipleTestingReport, family, test, treatment string) string {
+	t := report.Find(family, test, treatment)
+	if t == nil {
+		return ""
+	}
+	return fmt.Sprintf(" (с поправкой: Бонферрони %.4f, BH %.4f)", t.BonferroniPValue, t.BHAdjustedPValue)
+}
+
+// srmWarning возвращает предупреждение о несоответствии соотношения групп или nil
+func (p *ExperimentStatsPanel) srmWarning(ctx context.Context, experimentID int) fyne.CanvasObject {
+	check, err := p.mw.rep.CheckSampleRatio(ctx, experimentID)
+	if err != nil {
+		logger.Error("Ошибка проверки соотношения групп эксперимента %d: %v", experimentID, err)
+		return nil
+	}
+	if !check.Mismatch {
+		return nil
+	}
+	return srmWarningLabel(fmt.Sprintf("⚠ НЕСООТВЕТСТВИЕ СООТНОШЕНИЯ ГРУПП (SRM): p-value = %.6f. "+
+		"Распределение пользователей не совпадает с ожидаемым — результаты эксперимента ненадежны, проверьте назначение групп.",
+		check.PValue))
+}
+
+// srmWarningLabel создает заметное предупреждение о SRM
+func srmWarningLabel(text string) *widget.Label {
+	label := widget.NewLabelWithStyle(text, fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	label.Importance = widget.DangerImportance
+	label.Wrapping = fyne.TextWrapWord
+	return label
+}
+
+// sequentialStatus возвращает отображение текущего решения последовательного теста;
+// расчет статистики просмотр не фиксирует
+func (p *ExperimentStatsPanel) sequentialStatus(ctx context.Context, experimentID int) fyne.CanvasObject {
+	status, err := p.mw.rep.GetSequentialStatus(ctx, experimentID)
+	if err != nil {
+		logger.Error("Ошибка последовательного теста эксперимента %d: %v", experimentID, err)
+		return widget.NewLabel("Последовательный тест недоступен: " + err.Error())
+	}
+	return renderSequentialStatus(status)
+}
+
+// recordSequentialLook фиксирует просмотр выбранного запущенного эксперимента в последовательном тесте
+// и показывает обновленное решение
+func (p *ExperimentStatsPanel) recordSequentialLook() {
+	experimentID, ok := p.selectedExperimentID()
+	if !ok {
+		dialog.ShowInformation("Не выбран эксперимент", "Выберите эксперимент из списка", p.window)
+		return
+	}
+
+	dialog.ShowConfirm("Фиксация просмотра",
+		"Каждый зафиксированный просмотр расходует часть уровня значимости последовательного теста. "+
+			"Зафиксировать просмотр по текущим данным?",
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			status, err := p.mw.rep.RecordSequentialLook(context.Background(), experimentID, models.DefaultSequentialAlpha)
+			if err != nil {
+				logger.Error("Ошибка фиксации просмотра эксперимента %d: %v", experimentID, err)
+				showUserError(p.window, "Не удалось зафиксировать просмотр: "+err.Error())
+				return
+			}
+			p.statusLabel.SetText(fmt.Sprintf("Просмотр эксперимента %d зафиксирован", experimentID))
+			p.showResult(renderSequentialStatus(status))
+		}, p.window)
+}
+
+// showResult заменяет содержимое области результатов
+func (p *ExperimentStatsPanel) showResult(objects ...fyne.CanvasObject) {
+	p.resultContainer.Objects = objects
+	p.resultContainer.Refresh()
+}
+
+// monospaceLabel создает метку с моноширинным текстом для табличного вывода
+func monospaceLabel(text string) *widget.Label {
+	label := widget.NewLabel(text)
+	label.TextStyle = fyne.TextStyle{Monospace: true}
+	return label
+}
+
+// formatPercent форматирует долю как процент
+func formatPercent(value float64) string {
+	return fmt.Sprintf("%.2f%%", value*100)
+}
+
+// renderCTRStats отображает статистику групп и z-тесты CTR
+func renderCTRStats(stats *models.ExperimentStats, adjusted *models.MultipleTestingReport) fyne.CanvasObject {
+	var groups []string
+	for name := range stats.Groups {
+		groups = append(groups, name)
+	}
+	sort.Strings(groups)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%-8s %-17s %12s %12s %10s %10s %10s\n", "Группа", "Алгоритм", "Пользователей", "Рекомендаций", "Кликов", "CTR", "Рейтинг")
+	var params []string
+	for _, name := range groups {
+		g := stats.Groups[name]
+		fmt.Fprintf(&sb, "%-8s %-17s %12d %12d %10d %10s %10.2f\n",
+			name, g.Algorithm, g.Users, g.TotalRecommendations, g.TotalClicks, formatPercent(g.CTR), g.AvgRating)
+		if g.Params != "" {
+			params = append(params, fmt.Sprintf("%-8s %s %s", name, g.Algorithm, g.Params))
+		}
+	}
+	// параметры алгоритмов выводятся отдельно, чтобы не раздвигать таблицу
+	if len(params) > 0 {
+		sb.WriteString("\nПараметры алгоритмов:\n")
+		sb.WriteString(strings.Join(params, "\n"))
+	}
+
+	objects := []fyne.CanvasObject{
+		widget.NewLabelWithStyle("Показатели групп", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		monospaceLabel(sb.String()),
+	}
+	if stats.SRM != nil {
+		objects = append(objects, renderSRMCheck(stats.SRM))
+	}
+	objects = append(objects,
+		widget.NewLabelWithStyle(fmt.Sprintf("Сравнение CTR с контрольной группой (z-тест, доверие %.0f%%)", stats.Confidence*100),
+			fyne.TextAlignLeading, fyne.TextStyle{Bold: true}))
+
+	if len(stats.Comparisons) == 0 {
+		objects = append(objects, widget.NewLabel("Недостаточно данных для сравнения групп"))
+		return container.NewVBox(objects...)
+	}
+
+	for _, c := range stats.Comparisons {
+		verdict := "различие не значимо"
+		if c.Significant {
+			verdict = "различие статистически значимо"
+		}
+		text := fmt.Sprintf(
+			"%s против %s: %s\n"+
+				"  Абсолютный прирост: %+.2f п.п.   Относительный прирост: %+.2f%%\n"+
+				"  Стандартная ошибка: %.4f   z = %.3f   p-value = %.4f%s\n"+
+				"  Доверительный интервал разности: [%+.2f; %+.2f] п.п.",
+			c.TreatmentGroup, c.ControlGroup, verdict,
+			c.AbsoluteLift*100, c.RelativeLift*100,
+			c.StandardError, c.ZScore, c.PValue,
+			adjustedPValues(adjusted, models.PValueFamilyCTR, models.PValueTestCTR, c.TreatmentGroup),
+			c.CILower*100, c.CIUpper*100)
+		objects = append(objects, monospaceLabel(text))
+	}
+	return container.NewVBox(objects...)
+}
+
+// renderRatingStats отображает распределение оценок и тесты Уэлча и Манна–Уитни
+func renderRatingStats(stats *models.RatingStats, dist *models.RatingDistribution, adjusted *models.MultipleTestingReport) fyne.CanvasObject {
+	var groups []string
+	for name := range stats.Groups {
+		groups = append(groups, name)
+	}
+	sort.Strings(groups)
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%-8s %8s %8s %10s %8s %8s\n", "Группа", "n", "Среднее", "Дисперсия", "СКО", "Медиана")
+	for _, name := range groups {
+		g := stats.Groups[name]
+		fmt.Fprintf(&sb, "%-8s %8d %8.3f %10.3f %8.3f %8.1f\n", name, g.N, g.Mean, g.Variance, g.StdDev, g.Median)
+	}
+
+	objects := []fyne.CanvasObject{
+		widget.NewLabelWithStyle("Оценки по группам (rating > 0)", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		monospaceLabel(sb.String()),
+		widget.NewLabelWithStyle("Сравнение с контрольной группой", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+	}
+
+	if len(stats.Comparisons) == 0 {
+		objects = append(objects, widget.NewLabel("Недостаточно данных для сравнения групп"))
+	}
+
+	for _, c := range stats.Comparisons {
+		var text strings.Builder
+		fmt.Fprintf(&text, "%s против %s\n", c.TreatmentGroup, c.ControlGroup)
+		if c.Welch != nil {
+			fmt.Fprintf(&text, "  t-тест Уэлча: разность средних %+.3f, t = %.3f, df = %.1f, p-value = %.4f%s\n",
+				c.Welch.MeanDiff, c.Welch.TStatistic, c.Welch.DegreesOfFreedom, c.Welch.PValue,
+				adjustedPValues(adjusted, models.PValueFamilyRatings, models.PValueTestWelch, c.TreatmentGroup))
+			fmt.Fprintf(&text, "  Доверительный интервал (%.0f%%): [%+.3f; %+.3f]\n",
+				stats.Confidence*100, c.Welch.CILower, c.Welch.CIUpper)
+		} else {
+			text.WriteString("  t-тест Уэлча: недостаточно оценок (нужно минимум 2 в каждой группе)\n")
+		}
+		if c.MannWhitney != nil {
+			fmt.Fprintf(&text, "  U-тест Манна–Уитни: U = %.1f, z = %.3f, p-value = %.4f%s\n",
+				c.MannWhitney.U, c.MannWhitney.ZScore, c.MannWhitney.PValue,
+				adjustedPValues(adjusted, models.PValueFamilyRatings, models.PValueTestMannWhitney, c.TreatmentGroup))
+			fmt.Fprintf(&text, "  Вероятность более высокой оценки в группе %s: %s",
+				c.TreatmentGroup, formatPercent(c.MannWhitney.ProbabilitySuperiority))
+		} else {
+			text.WriteString("  U-тест Манна–Уитни: недостаточно оценок")
+		}
+		objects = append(objects, monospaceLabel(text.String()))
+	}
+
+	if dist != nil {
+		objects = append(objects, renderRatingDistribution(dist, adjusted)...)
+	}
+	return container.NewVBox(objects...)
+}
+
+// renderRatingDistribution отображает распределение значений оценки по группам и критерий хи-квадрат
+func renderRatingDistribution(dist *models.RatingDistribution, adjusted *models.MultipleTestingReport) []fyne.CanvasObject {
+	groups := sortedGroupNames(dist.Counts)
+	labels := make([]string, len(dist.Values))
+	for i, v := range dist.Values {
+		labels[i] = strconv.Itoa(v)
+	}
+	labels[0] = "0 (нет)"
+
+	var series []chartSeries
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%-8s", "Группа")
+	for _, label := range labels {
+		fmt.Fprintf(&sb, " %16s", label)
+	}
+	sb.WriteString("\n")
+	for i, name := range groups {
+		series = append(series, chartSeries{Name: "Группа " + name, Values: dist.Shares[name], Color: chartColor(i)})
+		fmt.Fprintf(&sb, "%-8s", name)
+		for j, count := range dist.Counts[name] {
+			fmt.Fprintf(&sb, " %16s", fmt.Sprintf("%d (%s)", count, formatPercent(dist.Shares[name][j])))
+		}
+		sb.WriteString("\n")
+	}
+
+	if dist.ChiSquare == nil {
+		sb.WriteString("Критерий хи-квадрат: недостаточно данных (нужны две группы с оценками)")
+	} else {
+		c := dist.ChiSquare
+		fmt.Fprintf(&sb, "Критерий однородности хи-квадрат: χ² = %.3f, df = %d, p-value = %.4f%s", c.Statistic, c.DF, c.PValue,
+			adjustedPValues(adjusted, models.PValueFamilyRatings, models.PValueTestRatingChiSquare, models.PValueAllGroups))
+		if c.LowExpectedCells > 0 {
+			fmt.Fprintf(&sb, "\nВнимание: в %d ячейках ожидаемая частота меньше 5, p-значение приближенное", c.LowExpectedCells)
+		}
+	}
+
+	return []fyne.CanvasObject{
+		widget.NewLabelWithStyle("Распределение значений оценки", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		newBarChart("Доля результатов с оценкой", labels, series, formatPercent),
+		monospaceLabel(sb.String()),
+	}
+}
+
+// renderBayesianStats отображает апостериорные распределения CTR и вероятности превосходства
+func renderBayesianStats(stats *models.BayesianStats) fyne.CanvasObject {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%-8s %10s %22s %12s %12s %12s\n",
+		"Группа", "CTR (апост.)", "Интервал достоверности", "P(> контроля)", "P(лучшая)", "Потери")
+	for _, g := range stats.Groups {
+		beat := "—"
+		if g.Group != models.ControlGroup {
+			beat = formatPercent(g.ProbBeatControl)
+		}
+		fmt.Fprintf(&sb, "%-8s %10s %22s %12s %12s %11.3fп.п.\n",
+			g.Group, formatPercent(g.PosteriorMean),
+			fmt.Sprintf("[%s; %s]", formatPercent(g.CredibleLower), formatPercent(g.CredibleUpper)),
+			beat, formatPercent(g.ProbBest), g.ExpectedLoss*100)
+	}
+
+	return container.NewVBox(
+		widget.NewLabelWithStyle(fmt.Sprintf("Байесовский анализ CTR (априор Beta(%.0f, %.0f), %d выборок, интервалы %.0f%%)",
+			stats.PriorAlpha, stats.PriorBeta, stats.Draws, stats.Credibility*100),
+			fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		monospaceLabel(sb.String()),
+		widget.NewLabel("Потери — ожидаемое снижение CTR при выборе группы вместо лучшей. "+
+			"Группу можно выбирать, когда потери меньше допустимого для бизнеса порога."),
+	)
+}
+
+// renderCUPEDStats отображает исходный и скорректированный CUPED прирост CTR
+func renderCUPEDStats(stats *models.CUPEDStats, adjusted *models.MultipleTestingReport) fyne.CanvasObject {
+	objects := []fyne.CanvasObject{
+		widget.NewLabelWithStyle(fmt.Sprintf("CUPED: ковариата — CTR пользователя в предыдущих экспериментах (доверие %.0f%%)",
+			stats.Confidence*100), fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		monospaceLabel(fmt.Sprintf(
+			"Пользователей с историей: %d из %d (%s)\n"+
+				"theta = %.4f   корреляция с предпериодом = %.3f   снижение дисперсии = %s",
+			stats.UsersWithHistory, stats.Users, formatPercent(stats.Coverage),
+			stats.Theta, stats.Correlation, formatPercent(stats.VarianceReduction))),
+	}
+
+	if len(stats.Comparisons) == 0 {
+		objects = append(objects, widget.NewLabel("Недостаточно данных для сравнения групп"))
+		return container.NewVBox(objects...)
+	}
+
+	for _, c := range stats.Comparisons {
+		text := fmt.Sprintf(
+			"%s против %s (средний CTR пользователя %s против %s)\n"+
+				"  Без корректировки: прирост %+.2f п.п. (%+.2f%%), SE = %.4f, p-value = %.4f, интервал [%+.2f; %+.2f] п.п.\n"+
+				"  С CUPED:           прирост %+.2f п.п. (%+.2f%%), SE = %.4f, p-value = %.4f, интервал [%+.2f; %+.2f] п.п.\n"+
+				"%s"+
+				"  Снижение дисперсии оценки: %s",
+			c.TreatmentGroup, c.ControlGroup, formatPercent(c.TreatmentMean), formatPercent(c.ControlMean),
+			c.Raw.MeanDiff*100, c.RelativeLiftRaw*100, c.Raw.StandardError, c.Raw.PValue, c.Raw.CILower*100, c.Raw.CIUpper*100,
+			c.Adjusted.MeanDiff*100, c.RelativeLiftAdjusted*100, c.Adjusted.StandardError, c.Adjusted.PValue,
+			c.Adjusted.CILower*100, c.Adjusted.CIUpper*100,
+			cupedAdjustedPValues(adjusted, c.TreatmentGroup),
+			formatPercent(c.VarianceReduction))
+		objects = append(objects, monospaceLabel(text))
+	}
+	return container.NewVBox(objects...)
+}
+
+// cupedAdjustedPValues строка с поправками на множественность для p-значения после CUPED
+func cupedAdjustedPValues(report *models.MultipleTestingReport, treatment string) string {
+	text := adjustedPValues(report, models.PValueFamilyCUPED, models.PValueTestCUPED, treatment)
+	if text == "" {
+		return ""
+	}
+	return "  p-value с CUPED" + text + "\n"
+}
+
+// renderMetricsReport отображает значения и сравнения всех метрик реестра
+func renderMetricsReport(report *models.MetricsReport, adjusted *models.MultipleTestingReport) fyne.CanvasObject {
+	objects := []fyne.CanvasObject{
+		widget.NewLabelWithStyle(fmt.Sprintf("Метрики реестра (доверие %.0f%%)", report.Confidence*100),
+			fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+	}
+	if len(report.Metrics) == 0 {
+		return container.NewVBox(append(objects, widget.NewLabel("Реестр метрик пуст"))...)
+	}
+
+	for _, res := range report.Metrics {
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "%s (%s, %s)\n", res.Metric.Title, res.Metric.Name, res.Metric.Type)
+		if res.Error != "" {
+			fmt.Fprintf(&sb, "  Ошибка вычисления: %s", res.Error)
+			objects = append(objects, monospaceLabel(sb.String()))
+			continue
+		}
+		for _, g := range res.Groups {
+			fmt.Fprintf(&sb, "  Группа %-4s пользователей %6d   значение %.4f\n", g.Group, g.Users, g.Value)
+		}
+		for _, c := range res.Comparisons {
+			verdict := "различие не значимо"
+			switch {
+			case c.Improvement:
+				verdict = "значимое улучшение"
+			case c.Significant:
+				verdict = "значимое ухудшение"
+			}
+			fmt.Fprintf(&sb, "  %s против %s: %+.4f (%+.2f%%), p-value = %.4f%s, интервал [%+.4f; %+.4f] — %s\n",
+				c.TreatmentGroup, c.ControlGroup, c.AbsoluteLift, c.RelativeLift*100, c.PValue,
+				adjustedPValues(adjusted, models.PValueFamilyMetrics, res.Metric.Title, c.TreatmentGroup),
+				c.CILower, c.CIUpper, verdict)
+		}
+		objects = append(objects, monospaceLabel(strings.TrimSuffix(sb.String(), "\n")))
+	}
+	return container.NewVBox(objects...)
+}
+
+// renderMultipleTestingReport отображает все p-значения эксперимента с поправками на множественность
+func renderMultipleTestingReport(report *models.MultipleTestingReport) fyne.CanvasObject {
+	title := widget.NewLabelWithStyle(fmt.Sprintf("Поправки на множественные сравнения (α = %.2f, проверок: %d)",
+		report.Alpha, len(report.Tests)), fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	if len(report.Tests) == 0 {
+		return container.NewVBox(title, widget.NewLabel("Для эксперимента не рассчитано ни одной проверки"))
+	}
+
+	mark := func(significant bool) string {
+		if significant {
+			return "*"
+		}
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%-16s %-28s %-8s %10s %12s %10s\n", "Семейство", "Проверка", "Группы", "p-value", "Бонферрони", "BH (FDR)")
+	for _, t := range report.Tests {
+		fmt.Fprintf(&sb, "%-16s %-28s %-8s %9.4f%1s %11.4f%1s %9.4f%1s\n",
+			t.Family, t.Test, t.TreatmentGroup+"/"+t.ControlGroup,
+			t.PValue, mark(t.Significant),
+			t.BonferroniPValue, mark(t.SignificantBonferroni),
+			t.BHAdjustedPValue, mark(t.SignificantBH))
+	}
+
+	summary := fmt.Sprintf("Значимых без поправки: %d, с поправкой Бонферрони (FWER): %d, "+
+		"с поправкой Бенджамини–Хохберга (FDR): %d.\n"+
+		"Вероятность хотя бы одного ложного срабатывания без поправки: %s (* — значимо при α = %.2f)",
+		report.SignificantRaw, report.SignificantBonferroni, report.SignificantBH,
+		formatPercent(report.FamilyWiseErrorRate), report.Alpha)
+	summaryLabel := widget.NewLabel(summary)
+	summaryLabel.Wrapping = fyne.TextWrapWord
+
+	content := container.NewVBox(title, monospaceLabel(strings.TrimSuffix(sb.String(), "\n")), summaryLabel)
+	if items := report.Items; items != nil && len(items.Tests) > 0 {
+		content.Add(renderItemPValues(items))
+	}
+	return content
+}
+
+// renderItemPValues отображает сводку по семейству z-тестов рекомендаций и рекомендации,
+// значимые после поправки Бенджамини–Хохберга
+func renderItemPValues(items *models.MultipleTestingReport) fyne.CanvasObject {
+	summary := widget.NewLabel(fmt.Sprintf("Рекомендации (отдельное семейство, проверок: %d): значимых без поправки %d, "+
+		"с поправкой Бонферрони %d, с поправкой Бенджамини–Хохберга %d. "+
+		"Вероятность хотя бы одного ложного срабатывания без поправки: %s",
+		len(items.Tests), items.SignificantRaw, items.SignificantBonferroni, items.SignificantBH,
+		formatPercent(items.FamilyWiseErrorRate)))
+	summary.Wrapping = fyne.TextWrapWord
+	if items.SignificantBH == 0 {
+		return summary
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%-40s %-8s %10s %12s %10s\n", "Проверка", "Группы", "p-value", "Бонферрони", "BH (FDR)")
+	for _, t := range items.Tests {
+		if !t.SignificantBH {
+			continue
+		}
+		fmt.Fprintf(&sb, "%-40s %-8s %10.4f %12.4f %10.4f\n",
+			t.Test, t.TreatmentGroup+"/"+t.ControlGroup, t.PValue, t.BonferroniPValue, t.BHAdjustedPValue)
+	}
+	return container.NewVBox(summary, monospaceLabel(strings.TrimSuffix(sb.String(), "\n")))
+}
+
+// sequentialDecisionText возвращает описание решения последовательного теста
+func sequentialDecisionText(decision string) string {
+	if group, ok := strings.CutPrefix(decision, models.SequentialStopPrefix); ok {
+		return "остановить эксперимент в пользу группы " + group
+	}
+	return "продолжать эксперимент"
+}
+
+// renderSequentialStatus отображает решение последовательного теста и последние просмотры групп
+func renderSequentialStatus(status *models.SequentialStatus) fyne.CanvasObject {
+	title := widget.NewLabelWithStyle("Последовательный тест (mSPRT): "+sequentialDecisionText(status.Decision),
+		fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+	if len(status.Looks) == 0 {
+		return container.NewVBox(title, widget.NewLabel("Просмотров еще не было: их фиксирует кнопка «Зафиксировать просмотр» у запущенного эксперимента"))
+	}
+
+	var sb strings.Builder
+	for _, look := range status.Looks {
+		fmt.Fprintf(&sb, "%s против %s, просмотр №%d (%s): z = %.3f, граница |z| ≥ %.3f, всегда валидное p = %.4f (α = %.2f)\n",
+			look.TreatmentGroup, models.ControlGroup, look.LookNumber, look.LookedAt.Format("2006-01-02 15:04"),
+			look.ZScore, look.ZBoundary, look.PValue, look.Alpha)
+	}
+	return container.NewVBox(title, monospaceLabel(strings.TrimSuffix(sb.String(), "\n")))
+}
+
+// renderSRMCheck отображает проверку соотношения размеров групп
+func renderSRMCheck(check *models.SRMCheck) fyne.CanvasObject {
+	var groups []string
+	for name := range check.Observed {
+		groups = append(groups, name)
+	}
+	sort.Strings(groups)
+
+	var parts []string
+	for _, name := range groups {
+		parts = append(parts, fmt.Sprintf("%s: %d (ожидалось %.1f)", name, check.Observed[name], check.Expected[name]))
+	}
+	text := fmt.Sprintf("Проверка соотношения групп (SRM): %s; chi2 = %.3f, df = %d, p-value = %.6f",
+		strings.Join(parts, ", "), check.ChiSquare, check.DF, check.PValue)
+
+	if check.Mismatch {
+		return srmWarningLabel("⚠ " + text + " — обнаружено несоответствие")
+	}
+	label := widget.NewLabel(text + " — соответствует ожиданию")
+	label.Wrapping = fyne.TextWrapWord
+	return label
+}
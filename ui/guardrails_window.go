@@ -0,0 +1,302 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing-platform/db/models"
+	"testing-platform/pkg/logger"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+// GuardrailsWindow окно ограничительных метрик экспериментов и истории их проверок
+type GuardrailsWindow struct {
+	mw     *MainWindow
+	window fyne.Window
+
+	experimentSelect *widget.Select
+	metricSelect     *widget.Select
+	degradationEntry *widget.Entry
+	alphaEntry       *widget.Entry
+	guardrailList    *widget.List
+	historyLabel     *widget.Label
+	statusLabel      *widget.Label
+
+	experimentIDs map[string]int
+	metricIDs     map[string]int
+	guardrails    []models.Guardrail
+	selected      int // индекс выбранного ограничения, -1 — нет
+}
+
+// NewGuardrailsWindow создает окно ограничительных метрик
+func NewGuardrailsWindow(mw *MainWindow) *GuardrailsWindow {
+	w := &GuardrailsWindow{
+		mw:            mw,
+		window:        mw.app.NewWindow("Ограничительные метрики"),
+		experimentIDs: make(map[string]int),
+		metricIDs:     make(map[string]int),
+		selected:      -1,
+	}
+	w.window.Resize(fyne.NewSize(1100, 700))
+	w.buildUI()
+	return w
+}
+
+func (w *GuardrailsWindow) buildUI() {
+	w.experimentSelect = widget.NewSelect([]string{}, func(string) { w.loadExperimentData() })
+	w.experimentSelect.PlaceHolder = "Выберите эксперимент"
+
+	w.metricSelect = widget.NewSelect([]string{}, nil)
+	w.metricSelect.PlaceHolder = "Метрика реестра"
+	w.degradationEntry = widget.NewEntry()
+	w.degradationEntry.SetPlaceHolder("Например: 0.2")
+	w.alphaEntry = widget.NewEntry()
+	w.alphaEntry.SetText(strconv.FormatFloat(models.DefaultGuardrailAlpha, 'f', -1, 64))
+
+	w.guardrailList = widget.NewList(
+		func() int { return len(w.guardrails) },
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			g := w.guardrails[i]
+			state := "включено"
+			if !g.IsEnabled {
+				state = "выключено"
+			}
+			o.(*widget.Label).SetText(fmt.Sprintf("%s: ухудшение не более %.4f, α = %.3f (%s)",
+				g.MetricName, g.MaxDegradation, g.Alpha, state))
+		},
+	)
+	w.guardrailList.OnSelected = func(id widget.ListItemID) { w.selected = id }
+
+	w.historyLabel = monospaceLabel("")
+	w.statusLabel = widget.NewLabel("")
+	w.statusLabel.Wrapping = fyne.TextWrapWord
+
+	addForm := widget.NewForm(
+		widget.NewFormItem("Метрика", w.metricSelect),
+		widget.NewFormItem("Допустимое ухудшение", w.degradationEntry),
+		widget.NewFormItem("Уровень значимости", w.alphaEntry),
+	)
+
+	addBtn := widget.NewButton("Добавить ограничение", w.addGuardrail)
+	deleteBtn := widget.NewButton("Удалить выбранное", w.deleteGuardrail)
+	evaluateBtn := widget.NewButton("Проверить сейчас", w.evaluate)
+	closeBtn := widget.NewButton("Закрыть", func() { w.window.Close() })
+
+	help := widget.NewLabel("Ухудшение измеряется в единицах метрики с учетом направления улучшения. " +
+		"Ограничение нарушено, если односторонний тест показывает, что ухудшение значимо больше допустимого; " +
+		"тогда активный эксперимент выключается автоматически. Проверки выполняются периодически в фоне, " +
+		"поэтому p-значение всегда валидное (последовательный тест): оно не растет от проверки к проверке, " +
+		"и вероятность ложной остановки не превышает уровень значимости при любом числе проверок.")
+	help.Wrapping = fyne.TextWrapWord
+
+	left := container.NewBorder(
+		container.NewVBox(
+			widget.NewLabelWithStyle("Ограничения эксперимента", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			addForm,
+			container.NewHBox(addBtn, deleteBtn, evaluateBtn),
+			help,
+		),
+		nil, nil, nil,
+		container.NewScroll(w.guardrailList),
+	)
+	right := container.NewBorder(
+		widget.NewLabelWithStyle("История проверок", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+		nil, nil, nil,
+		container.NewScroll(w.historyLabel),
+	)
+	split := container.NewHSplit(left, right)
+	split.SetOffset(0.45)
+
+	w.window.SetContent(container.NewPadded(container.NewBorder(
+		container.NewVBox(container.NewHBox(widget.NewLabel("Эксперимент:"), w.experimentSelect), w.statusLabel),
+		container.NewHBox(layout.NewSpacer(), closeBtn),
+		nil, nil,
+		split,
+	)))
+
+	w.loadOptions()
+}
+
+// loadOptions загружает списки экспериментов и метрик
+func (w *GuardrailsWindow) loadOptions() {
+	ctx := context.Background()
+	experiments, err := w.mw.rep.GetExperiments(ctx, models.ExperimentFilter{})
+	if err != nil {
+		logger.Error("Ошибка загрузки списка экспериментов: %v", err)
+		w.statusLabel.SetText("Ошибка загрузки списка экспериментов: " + err.Error())
+		return
+	}
+	var options []string
+	for _, exp := range experiments {
+		option := fmt.Sprintf("%d: %s", exp.ID, exp.Name)
+		options = append(options, option)
+		w.experimentIDs[option] = exp.ID
+	}
+	w.experimentSelect.Options = options
+	w.experimentSelect.Refresh()
+
+	metrics, err := w.mw.rep.GetMetrics(ctx)
+	if err != nil {
+		logger.Error("Ошибка загрузки метрик: %v", err)
+		w.statusLabel.SetText("Ошибка загрузки метрик: " + err.Error())
+		return
+	}
+	var metricOptions []string
+	for _, m := range metrics {
+		option := fmt.Sprintf("%s (%s)", m.Title, m.Name)
+		metricOptions = append(metricOptions, option)
+		w.metricIDs[option] = m.ID
+	}
+	w.metricSelect.Options = metricOptions
+	w.metricSelect.Refresh()
+}
+
+// selectedExperimentID возвращает ID выбранного эксперимента
+func (w *GuardrailsWindow) selectedExperimentID() (int, bool) {
+	id, ok := w.experimentIDs[w.experimentSelect.Selected]
+	return id, ok
+}
+
+// loadExperimentData загружает ограничения и историю проверок выбранного эксперимента
+func (w *GuardrailsWindow) loadExperimentData() {
+	experimentID, ok := w.selectedExperimentID()
+	if !ok {
+		return
+	}
+	ctx := context.Background()
+
+	guardrails, err := w.mw.rep.GetGuardrails(ctx, experimentID)
+	if err != nil {
+		w.statusLabel.SetText("Ошибка загрузки ограничений: " + err.Error())
+		return
+	}
+	w.guardrails = guardrails
+	w.selected = -1
+	w.guardrailList.UnselectAll()
+	w.guardrailList.Refresh()
+
+	evaluations, err := w.mw.rep.GetGuardrailEvaluations(ctx, experimentID)
+	if err != nil {
+		w.statusLabel.SetText("Ошибка загрузки истории проверок: " + err.Error())
+		return
+	}
+	w.historyLabel.SetText(formatGuardrailHistory(evaluations))
+}
+
+// addGuardrail добавляет ограничение к выбранному эксперименту
+func (w *GuardrailsWindow) addGuardrail() {
+	experimentID, ok := w.selectedExperimentID()
+	if !ok {
+		dialog.ShowInformation("Не выбран эксперимент", "Выберите эксперимент из списка", w.window)
+		return
+	}
+	degradation, err := strconv.ParseFloat(strings.TrimSpace(w.degradationEntry.Text), 64)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("допустимое ухудшение должно быть числом"), w.window)
+		return
+	}
+	alpha, err := strconv.ParseFloat(strings.TrimSpace(w.alphaEntry.Text), 64)
+	if err != nil {
+		dialog.ShowError(fmt.Errorf("уровень значимости должен быть числом"), w.window)
+		return
+	}
+
+	g := &models.Guardrail{
+		ExperimentID:   experimentID,
+		MetricID:       w.metricIDs[w.metricSelect.Selected],
+		MaxDegradation: degradation,
+		Alpha:          alpha,
+		IsEnabled:      true,
+	}
+	if err := w.mw.rep.CreateGuardrail(context.Background(), g); err != nil {
+		dialog.ShowError(err, w.window)
+		return
+	}
+	w.degradationEntry.SetText("")
+	w.loadExperimentData()
+	w.statusLabel.SetText("Ограничение добавлено")
+}
+
+// deleteGuardrail удаляет выбранное ограничение
+func (w *GuardrailsWindow) deleteGuardrail() {
+	if w.selected < 0 || w.selected >= len(w.guardrails) {
+		dialog.ShowInformation("Не выбрано ограничение", "Выберите ограничение в списке", w.window)
+		return
+	}
+	if err := w.mw.rep.DeleteGuardrail(context.Background(), w.guardrails[w.selected].ID); err != nil {
+		dialog.ShowError(err, w.window)
+		return
+	}
+	w.loadExperimentData()
+	w.statusLabel.SetText("Ограничение удалено")
+}
+
+// evaluate проверяет ограничения выбранного эксперимента немедленно
+func (w *GuardrailsWindow) evaluate() {
+	experimentID, ok := w.selectedExperimentID()
+	if !ok {
+		dialog.ShowInformation("Не выбран эксперимент", "Выберите эксперимент из списка", w.window)
+		return
+	}
+	evaluations, err := w.mw.rep.EvaluateGuardrails(w.mw.actorContext(), experimentID)
+	if err != nil {
+		dialog.ShowError(err, w.window)
+		return
+	}
+	w.loadExperimentData()
+
+	stopped := false
+	breached := 0
+	for _, e := range evaluations {
+		if e.Breached {
+			breached++
+		}
+		stopped = stopped || e.ExperimentStopped
+	}
+	switch {
+	case len(evaluations) == 0:
+		w.statusLabel.SetText("Нет включенных ограничений или данных для проверки")
+	case stopped:
+		w.statusLabel.SetText(fmt.Sprintf("Нарушено ограничений: %d. Эксперимент автоматически остановлен", breached))
+		w.mw.NotifyAllDataWindows()
+	case breached > 0:
+		w.statusLabel.SetText(fmt.Sprintf("Нарушено ограничений: %d (эксперимент уже не активен)", breached))
+	default:
+		w.statusLabel.SetText("Ограничения не нарушены")
+	}
+}
+
+// formatGuardrailHistory форматирует историю проверок для моноширинного вывода
+func formatGuardrailHistory(evaluations []models.GuardrailEvaluation) string {
+	if len(evaluations) == 0 {
+		return "Проверок еще не было"
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%-16s %-18s %-6s %10s %10s %10s %8s %s\n",
+		"Время", "Метрика", "Группа", "Ухудшение", "Допустимо", "SE", "p-value", "Итог")
+	for _, e := range evaluations {
+		outcome := "в норме"
+		if e.Breached {
+			outcome = "НАРУШЕНО"
+			if e.ExperimentStopped {
+				outcome += ", эксперимент остановлен"
+			}
+		}
+		fmt.Fprintf(&sb, "%-16s %-18s %-6s %10.4f %10.4f %10.4f %8.4f %s\n",
+			e.EvaluatedAt.Format("2006-01-02 15:04"), e.MetricName, e.TreatmentGroup,
+			e.Degradation, e.MaxDegradation, e.StandardError, e.PValue, outcome)
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// Show отображает окно
+func (w *GuardrailsWindow) Show() {
+	w.window.Show()
+}
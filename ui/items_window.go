@@ -0,0 +1,343 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"testing-platform/db/models"
+	"testing-platform/pkg/logger"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+// itemColumn столбец таблицы рекомендаций: заголовок, значение ячейки и ключ сортировки
+type itemColumn struct {
+	title string
+	text  func(models.ItemPerformance) string
+	less  func(a, b models.ItemPerformance) bool
+}
+
+// ItemsWindow окно эффективности отдельных рекомендаций эксперимента
+type ItemsWindow struct {
+	mw     *MainWindow
+	window fyne.Window
+
+	experimentSelect    *widget.Select
+	treatmentSelect     *widget.Select
+	confidenceSelect    *widget.Select
+	minImpressionsEntry *widget.Entry
+	statusLabel         *widget.Label
+	table               *widget.Table
+	leadersLabel        *widget.Label
+
+	experimentIDs map[string]int
+	variants      map[int][]models.Variant
+	report        *models.ItemReport
+	columns       []itemColumn
+	sortColumn    int
+	sortDesc      bool
+}
+
+// NewItemsWindow создает окно эффективности рекомендаций
+func NewItemsWindow(mw *MainWindow) *ItemsWindow {
+	w := &ItemsWindow{
+		mw:            mw,
+		window:        mw.app.NewWindow("Эффективность рекомендаций"),
+		experimentIDs: make(map[string]int),
+		variants:      make(map[int][]models.Variant),
+		sortColumn:    1,
+		sortDesc:      true,
+	}
+	w.window.Resize(fyne.NewSize(1150, 700))
+	w.buildUI()
+	return w
+}
+
+func (w *ItemsWindow) buildUI() {
+	w.treatmentSelect = widget.NewSelect([]string{}, nil)
+	w.treatmentSelect.PlaceHolder = "Группа"
+	w.experimentSelect = widget.NewSelect([]string{}, func(option string) {
+		// тестовые группы выбранного эксперимента
+		var treatments []string
+		for _, v := range w.variants[w.experimentIDs[option]] {
+			if v.Name != models.ControlGroup {
+				treatments = append(treatments, v.Name)
+			}
+		}
+		w.treatmentSelect.Options = treatments
+		w.treatmentSelect.ClearSelected()
+		if len(treatments) > 0 {
+			w.treatmentSelect.SetSelected(treatments[0])
+		}
+	})
+	w.experimentSelect.PlaceHolder = "Выберите эксперимент"
+	w.minImpressionsEntry = widget.NewEntry()
+	w.minImpressionsEntry.SetText(strconv.Itoa(models.DefaultItemMinImpressions))
+	w.confidenceSelect = widget.NewSelect([]string{"90%", "95%", "99%"}, nil)
+	w.confidenceSelect.SetSelected("95%")
+
+	w.statusLabel = widget.NewLabel("Выберите эксперимент и нажмите «Показать»")
+	w.leadersLabel = monospaceLabel("")
+
+	w.table = widget.NewTable(
+		func() (int, int) {
+			if w.report == nil {
+				return 0, len(w.columns)
+			}
+			return len(w.report.Items), len(w.columns)
+		},
+		func() fyne.CanvasObject { return widget.NewLabel("") },
+		func(id widget.TableCellID, o fyne.CanvasObject) {
+			o.(*widget.Label).SetText(w.columns[id.Col].text(w.report.Items[id.Row]))
+		},
+	)
+	w.table.ShowHeaderRow = true
+	w.table.CreateHeader = func() fyne.CanvasObject { return widget.NewButton("", nil) }
+	w.table.UpdateHeader = func(id widget.TableCellID, o fyne.CanvasObject) {
+		btn := o.(*widget.Button)
+		if id.Col < 0 || id.Col >= len(w.columns) {
+			return
+		}
+		title := w.columns[id.Col].title
+		if id.Col == w.sortColumn {
+			if w.sortDesc {
+				title += " ▼"
+			} else {
+				title += " ▲"
+			}
+		}
+		btn.SetText(title)
+		col := id.Col
+		btn.OnTapped = func() { w.sortBy(col) }
+	}
+	w.setColumns(models.ControlGroup, "B")
+
+	showBtn := widget.NewButton("Показать", w.load)
+	closeBtn := widget.NewButton("Закрыть", func() { w.window.Close() })
+
+	tabs := container.NewAppTabs(
+		container.NewTabItem("Все рекомендации", w.table),
+		container.NewTabItem("Лидеры и аутсайдеры", container.NewScroll(w.leadersLabel)),
+	)
+
+	w.window.SetContent(container.NewPadded(container.NewBorder(
+		container.NewVBox(
+			container.NewHBox(
+				widget.NewLabel("Эксперимент:"), w.experimentSelect,
+				widget.NewLabel("Сравнить с контролем группу:"), w.treatmentSelect,
+				widget.NewLabel("Мин. показов в группе:"), w.minImpressionsEntry,
+				widget.NewLabel("Уровень доверия:"), w.confidenceSelect,
+				showBtn,
+			),
+			w.statusLabel,
+		),
+		container.NewHBox(layout.NewSpacer(), closeBtn),
+		nil, nil,
+		tabs,
+	)))
+
+	experiments, err := w.mw.rep.GetExperiments(context.Background(), models.ExperimentFilter{})
+	if err != nil {
+		logger.Error("Ошибка загрузки списка экспериментов: %v", err)
+		w.statusLabel.SetText("Ошибка загрузки списка экспериментов: " + err.Error())
+		return
+	}
+	var options []string
+	for _, exp := range experiments {
+		option := fmt.Sprintf("%d: %s", exp.ID, exp.Name)
+		options = append(options, option)
+		w.experimentIDs[option] = exp.ID
+		w.variants[exp.ID] = exp.Variants
+	}
+	w.experimentSelect.Options = options
+	w.experimentSelect.Refresh()
+}
+
+// setColumns задает столбцы таблицы для контрольной и тестовой групп
+func (w *ItemsWindow) setColumns(control, treatment string) {
+	groupCTR := func(group string) func(models.ItemPerformance) float64 {
+		return func(item models.ItemPerformance) float64 { return item.Groups[group].CTR }
+	}
+	groupRating := func(group string) func(models.ItemPerformance) float64 {
+		return func(item models.ItemPerformance) float64 { return item.Groups[group].AvgRating }
+	}
+	byFloat := func(value func(models.ItemPerformance) float64) func(a, b models.ItemPerformance) bool {
+		return func(a, b models.ItemPerformance) bool { return value(a) < value(b) }
+	}
+	groupText := func(group string, value func(models.ItemPerformance) float64, format func(float64) string) func(models.ItemPerformance) string {
+		return func(item models.ItemPerformance) string {
+			if _, ok := item.Groups[group]; !ok {
+				return "—"
+			}
+			return format(value(item))
+		}
+	}
+	rating := func(v float64) string { return fmt.Sprintf("%.2f", v) }
+
+	w.columns = []itemColumn{
+		{"ID рекомендации",
+			func(item models.ItemPerformance) string { return item.RecommendationID },
+			func(a, b models.ItemPerformance) bool { return a.RecommendationID < b.RecommendationID }},
+		{"Показы",
+			func(item models.ItemPerformance) string { return strconv.Itoa(item.Impressions) },
+			func(a, b models.ItemPerformance) bool { return a.Impressions < b.Impressions }},
+		{"Клики",
+			func(item models.ItemPerformance) string { return strconv.Itoa(item.Clicks) },
+			func(a, b models.ItemPerformance) bool { return a.Clicks < b.Clicks }},
+		{"CTR",
+			func(item models.ItemPerformance) string { return formatPercent(item.CTR) },
+			func(a, b models.ItemPerformance) bool { return a.CTR < b.CTR }},
+		{"CTR " + control, groupText(control, groupCTR(control), formatPercent), byFloat(groupCTR(control))},
+		{"CTR " + treatment, groupText(treatment, groupCTR(treatment), formatPercent), byFloat(groupCTR(treatment))},
+		{"Разность",
+			func(item models.ItemPerformance) string {
+				if !item.Comparable {
+					return "—"
+				}
+				return fmt.Sprintf("%+.2f п.п.", item.AbsoluteLift*100)
+			},
+			// несравнимые рекомендации всегда в конце списка при сортировке по убыванию
+			func(a, b models.ItemPerformance) bool {
+				if a.Comparable != b.Comparable {
+					return !a.Comparable
+				}
+				return a.AbsoluteLift < b.AbsoluteLift
+			}},
+		{"p-value",
+			func(item models.ItemPerformance) string {
+				if !item.Comparable {
+					return "—"
+				}
+				return fmt.Sprintf("%.4f", item.PValue)
+			},
+			func(a, b models.ItemPerformance) bool {
+				if a.Comparable != b.Comparable {
+					return a.Comparable
+				}
+				return a.PValue < b.PValue
+			}},
+		{"p (BH)",
+			func(item models.ItemPerformance) string {
+				if !item.Comparable {
+					return "—"
+				}
+				return fmt.Sprintf("%.4f", item.AdjustedPValue)
+			},
+			func(a, b models.ItemPerformance) bool {
+				if a.Comparable != b.Comparable {
+					return a.Comparable
+				}
+				return a.AdjustedPValue < b.AdjustedPValue
+			}},
+		{"Оценка " + control, groupText(control, groupRating(control), rating), byFloat(groupRating(control))},
+		{"Оценка " + treatment, groupText(treatment, groupRating(treatment), rating), byFloat(groupRating(treatment))},
+	}
+	for i, width := range []float32{200, 90, 90, 90, 90, 90, 120, 90, 90, 90, 90} {
+		w.table.SetColumnWidth(i, width)
+	}
+}
+
+// sortBy сортирует таблицу по столбцу; повторное нажатие меняет направление
+func (w *ItemsWindow) sortBy(col int) {
+	if col == w.sortColumn {
+		w.sortDesc = !w.sortDesc
+	} else {
+		w.sortColumn, w.sortDesc = col, col != 0
+	}
+	w.applySort()
+}
+
+// applySort упорядочивает строки отчета по текущему столбцу
+func (w *ItemsWindow) applySort() {
+	if w.report != nil {
+		less := w.columns[w.sortColumn].less
+		sort.SliceStable(w.report.Items, func(i, j int) bool {
+			if w.sortDesc {
+				return less(w.report.Items[j], w.report.Items[i])
+			}
+			return less(w.report.Items[i], w.report.Items[j])
+		})
+	}
+	w.table.Refresh()
+}
+
+// load загружает эффективность рекомендаций выбранного эксперимента
+func (w *ItemsWindow) load() {
+	experimentID, ok := w.experimentIDs[w.experimentSelect.Selected]
+	if !ok {
+		dialog.ShowInformation("Не выбран эксперимент", "Выберите эксперимент из списка", w.window)
+		return
+	}
+	minImpressions, err := strconv.Atoi(strings.TrimSpace(w.minImpressionsEntry.Text))
+	if err != nil || minImpressions < 1 {
+		dialog.ShowError(fmt.Errorf("минимальное число показов должно быть целым положительным числом"), w.window)
+		return
+	}
+
+	confidence, ok := confidenceOptions[w.confidenceSelect.Selected]
+	if !ok {
+		confidence = models.DefaultConfidence
+	}
+
+	report, err := w.mw.rep.GetItemPerformance(context.Background(), experimentID, w.treatmentSelect.Selected, minImpressions, confidence)
+	if err != nil {
+		w.statusLabel.SetText("Ошибка загрузки эффективности рекомендаций: " + err.Error())
+		return
+	}
+	w.report = report
+	treatment := report.TreatmentGroup
+	if treatment == "" {
+		treatment = "B"
+	}
+	w.setColumns(report.ControlGroup, treatment)
+	w.applySort()
+
+	algorithms := make(map[string]string)
+	for _, v := range w.variants[experimentID] {
+		algorithms[v.Name] = v.Algorithm
+	}
+	algorithmsText := ""
+	if algorithms[report.ControlGroup] != "" && algorithms[treatment] != "" {
+		algorithmsText = fmt.Sprintf(" (%s — %s, %s — %s)", report.ControlGroup, algorithms[report.ControlGroup],
+			treatment, algorithms[treatment])
+	}
+	w.leadersLabel.SetText(formatItemLeaders(report, treatment))
+	w.statusLabel.SetText(fmt.Sprintf("Эксперимент %d%s: %d рекомендаций", experimentID, algorithmsText, len(report.Items)))
+}
+
+// formatItemLeaders форматирует списки рекомендаций с наибольшим выигрышем и проигрышем тестовой группы
+func formatItemLeaders(report *models.ItemReport, treatment string) string {
+	var sb strings.Builder
+	section := func(title string, items []models.ItemPerformance) {
+		fmt.Fprintf(&sb, "%s\n", title)
+		if len(items) == 0 {
+			sb.WriteString("  нет рекомендаций\n\n")
+			return
+		}
+		fmt.Fprintf(&sb, "  %-30s %9s %9s %12s %9s %9s\n", "ID рекомендации", "CTR "+report.ControlGroup, "CTR "+treatment,
+			"Разность", "p-value", "p (BH)")
+		for _, item := range items {
+			fmt.Fprintf(&sb, "  %-30s %9s %9s %+9.2f п.п. %9.4f %9.4f\n", item.RecommendationID,
+				formatPercent(item.Groups[report.ControlGroup].CTR), formatPercent(item.Groups[treatment].CTR),
+				item.AbsoluteLift*100, item.PValue, item.AdjustedPValue)
+		}
+		sb.WriteString("\n")
+	}
+	section(fmt.Sprintf("Группа %s лучше группы %s:", treatment, report.ControlGroup), report.Winners)
+	section(fmt.Sprintf("Группа %s хуже группы %s:", treatment, report.ControlGroup), report.Losers)
+	fmt.Fprintf(&sb, "Учитываются рекомендации, показанные в каждой группе не менее %d раз.\n", report.MinImpressions)
+	fmt.Fprintf(&sb, "Сравнения по отдельным рекомендациям многочисленны, поэтому в списки попадают только рекомендации\n"+
+		"со значимой разностью после поправки Бенджамини–Хохберга: p (BH) < %.2f.", 1-report.Confidence)
+	return sb.String()
+}
+
+// Show отображает окно
+func (w *ItemsWindow) Show() {
+	w.window.Show()
+}
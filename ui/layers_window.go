@@ -0,0 +1,233 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+	"testing-platform/db/models"
+	"testing-platform/pkg/logger"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+// цвет свободной части слоя
+var layerFreeColor = color.NRGBA{R: 0xd0, G: 0xd0, B: 0xd0, A: 0xff}
+
+// LayerBar полоса трафика слоя 0–100%: занятые экспериментами диапазоны и свободные промежутки
+type LayerBar struct {
+	widget.BaseWidget
+	ranges []models.LayerRange
+}
+
+// newLayerBar создает полосу трафика слоя
+func newLayerBar(ranges []models.LayerRange) *LayerBar {
+	b := &LayerBar{ranges: ranges}
+	b.ExtendBaseWidget(b)
+	return b
+}
+
+// CreateRenderer создает отрисовщик полосы
+func (b *LayerBar) CreateRenderer() fyne.WidgetRenderer {
+	return &layerBarRenderer{chartRenderer{draw: b.draw, target: b}}
+}
+
+// draw строит прямоугольники диапазонов для заданного размера
+func (b *LayerBar) draw(size fyne.Size) []fyne.CanvasObject {
+	background := canvas.NewRectangle(layerFreeColor)
+	background.Resize(size)
+	objects := []fyne.CanvasObject{background}
+
+	for i, r := range b.ranges {
+		x := size.Width * float32(r.Start/100)
+		width := max(size.Width*float32((r.End-r.Start)/100), 1)
+		rect := canvas.NewRectangle(chartColor(i))
+		rect.Move(fyne.NewPos(x, 0))
+		rect.Resize(fyne.NewSize(width, size.Height))
+		objects = append(objects, rect)
+
+		label := chartText(strconv.Itoa(r.ExperimentID), fyne.NewPos(x+3, 2))
+		if label.MinSize().Width+6 <= width {
+			objects = append(objects, label)
+		}
+	}
+	return objects
+}
+
+// layerBarRenderer отрисовщик полосы слоя: как у графиков, но невысокий
+type layerBarRenderer struct {
+	chartRenderer
+}
+
+func (r *layerBarRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(400, 24)
+}
+
+// LayersWindow окно слоев экспериментов и занятости их трафика
+type LayersWindow struct {
+	mw     *MainWindow
+	window fyne.Window
+
+	nameEntry        *widget.Entry
+	descriptionEntry *widget.Entry
+	layerSelect      *widget.Select
+	usageBox         *fyne.Container
+	statusLabel      *widget.Label
+
+	layerIDs map[string]int
+}
+
+// NewLayersWindow создает окно слоев экспериментов
+func NewLayersWindow(mw *MainWindow) *LayersWindow {
+	w := &LayersWindow{
+		mw:       mw,
+		window:   mw.app.NewWindow("Слои экспериментов"),
+		layerIDs: make(map[string]int),
+	}
+	w.window.Resize(fyne.NewSize(1000, 700))
+	w.buildUI()
+	return w
+}
+
+func (w *LayersWindow) buildUI() {
+	w.nameEntry = widget.NewEntry()
+	w.nameEntry.SetPlaceHolder("Например: Главная страница")
+	w.descriptionEntry = widget.NewMultiLineEntry()
+	w.descriptionEntry.SetMinRowsVisible(2)
+	w.layerSelect = widget.NewSelect([]string{}, nil)
+	w.layerSelect.PlaceHolder = "Слой для удаления"
+
+	w.usageBox = container.NewVBox()
+	w.statusLabel = widget.NewLabel("")
+	w.statusLabel.Wrapping = fyne.TextWrapWord
+
+	createForm := widget.NewForm(
+		widget.NewFormItem("Название", w.nameEntry),
+		widget.NewFormItem("Описание", w.descriptionEntry),
+	)
+	createBtn := widget.NewButton("Создать слой", w.createLayer)
+	deleteBtn := widget.NewButton("Удалить", w.deleteLayer)
+	refreshBtn := widget.NewButton("Обновить", w.load)
+	closeBtn := widget.NewButton("Закрыть", func() { w.window.Close() })
+
+	help := widget.NewLabel("Эксперименты одного слоя получают непересекающиеся диапазоны трафика слоя, " +
+		"ширина диапазона равна доле пользователей эксперимента. Пользователь попадает только в тот эксперимент слоя, " +
+		"чей диапазон содержит его хеш; эксперименты разных слоев распределяются независимо.")
+	help.Wrapping = fyne.TextWrapWord
+
+	w.window.SetContent(container.NewPadded(container.NewBorder(
+		container.NewVBox(
+			widget.NewLabelWithStyle("Новый слой", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			createForm,
+			container.NewHBox(createBtn, layout.NewSpacer(), w.layerSelect, deleteBtn, refreshBtn),
+			help,
+			w.statusLabel,
+			widget.NewSeparator(),
+		),
+		container.NewHBox(layout.NewSpacer(), closeBtn),
+		nil, nil,
+		container.NewVScroll(w.usageBox),
+	)))
+
+	w.load()
+}
+
+// load загружает слои и перестраивает полосы занятости трафика
+func (w *LayersWindow) load() {
+	usage, err := w.mw.rep.GetLayerUsage(context.Background())
+	if err != nil {
+		logger.Error("Ошибка загрузки слоев: %v", err)
+		w.statusLabel.SetText("Ошибка загрузки слоев: " + err.Error())
+		return
+	}
+
+	w.layerIDs = make(map[string]int)
+	var options []string
+	w.usageBox.RemoveAll()
+	if len(usage) == 0 {
+		w.usageBox.Add(widget.NewLabel("Слоев пока нет"))
+	}
+	for _, u := range usage {
+		option := fmt.Sprintf("%d: %s", u.Layer.ID, u.Layer.Name)
+		options = append(options, option)
+		w.layerIDs[option] = u.Layer.ID
+
+		title := widget.NewLabelWithStyle(
+			fmt.Sprintf("%s — свободно %.2f%%, экспериментов: %d", u.Layer.Name, u.FreePercent, len(u.Ranges)),
+			fyne.TextAlignLeading, fyne.TextStyle{Bold: true})
+		w.usageBox.Add(title)
+		if u.Layer.Description != "" {
+			description := widget.NewLabel(u.Layer.Description)
+			description.Wrapping = fyne.TextWrapWord
+			w.usageBox.Add(description)
+		}
+		w.usageBox.Add(newLayerBar(u.Ranges))
+		w.usageBox.Add(monospaceLabel(formatLayerRanges(u.Ranges)))
+	}
+	w.usageBox.Refresh()
+
+	w.layerSelect.Options = options
+	w.layerSelect.ClearSelected()
+	w.layerSelect.Refresh()
+}
+
+// formatLayerRanges формирует список диапазонов экспериментов слоя
+func formatLayerRanges(ranges []models.LayerRange) string {
+	if len(ranges) == 0 {
+		return "Весь трафик слоя свободен"
+	}
+	var b strings.Builder
+	for _, r := range ranges {
+		fmt.Fprintf(&b, "[%6.2f%%; %6.2f%%)  #%d %s (%s)\n", r.Start, r.End, r.ExperimentID, r.ExperimentName,
+			models.StatusTitle(r.Status))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// createLayer создает слой по данным формы
+func (w *LayersWindow) createLayer() {
+	layer := &models.Layer{
+		Name:        w.nameEntry.Text,
+		Description: strings.TrimSpace(w.descriptionEntry.Text),
+	}
+	if err := w.mw.rep.CreateLayer(context.Background(), layer); err != nil {
+		dialog.ShowError(err, w.window)
+		return
+	}
+	w.nameEntry.SetText("")
+	w.descriptionEntry.SetText("")
+	w.load()
+	w.statusLabel.SetText(fmt.Sprintf("Слой «%s» создан (ID %d)", layer.Name, layer.ID))
+}
+
+// deleteLayer удаляет выбранный слой после подтверждения
+func (w *LayersWindow) deleteLayer() {
+	layerID, ok := w.layerIDs[w.layerSelect.Selected]
+	if !ok {
+		dialog.ShowInformation("Не выбран слой", "Выберите слой из списка", w.window)
+		return
+	}
+	dialog.ShowConfirm("Удаление слоя", fmt.Sprintf("Удалить слой «%s»?", w.layerSelect.Selected),
+		func(confirmed bool) {
+			if !confirmed {
+				return
+			}
+			if err := w.mw.rep.DeleteLayer(context.Background(), layerID); err != nil {
+				dialog.ShowError(err, w.window)
+				return
+			}
+			w.load()
+			w.statusLabel.SetText("Слой удален")
+		}, w.window)
+}
+
+// Show отображает окно
+func (w *LayersWindow) Show() {
+	w.window.Show()
+}
@@ -25,6 +25,9 @@ type MainWindow struct {
 	titleLabel    *widget.Label
 	subtitleLabel *widget.Label
 
+	// имя оператора для журнала изменений экспериментов
+	operator string
+
 	// Для отслеживания открытых окон данных
 	dataWindows []*DataDisplayWindow
 	dataMutex   sync.Mutex
@@ -34,7 +37,7 @@ type MainWindow struct {
 	summaryMutex   sync.Mutex
 }
 
-func NewMainWindow(app fyne.App, rep *db.Repository) *MainWindow {
+func NewMainWindow(app fyne.App, rep *db.Repository, operator string) *MainWindow {
 	window := app.NewWindow("Testing Platform")
 	window.SetFixedSize(false)
 	window.Resize(fyne.NewSize(900, 600))
@@ -43,6 +46,7 @@ func NewMainWindow(app fyne.App, rep *db.Repository) *MainWindow {
 		app:            app,
 		window:         window,
 		rep:            rep,
+		operator:       operator,
 		dataWindows:    make([]*DataDisplayWindow, 0),
 		summaryWindows: make([]fyne.Window, 0),
 	}
@@ -429,6 +433,15 @@ func (mw *MainWindow) createMenu() *fyne.MainMenu {
 			fyne.NewMenuItem("Внести данные", mw.showDataInputDialog),
 			fyne.NewMenuItem("Показать данные", mw.showDataDisplayWindow),
 			fyne.NewMenuItem("Сводные данные", mw.showSummaryWindow),
+			fyne.NewMenuItem("Статусы и история экспериментов", mw.showLifecycle),
+		),
+		fyne.NewMenu("Анализ",
+			fyne.NewMenuItem("Реестр метрик", mw.showMetrics),
+			fyne.NewMenuItem("Реестр алгоритмов", mw.showAlgorithms),
+			fyne.NewMenuItem("Ограничительные метрики", mw.showGuardrails),
+			fyne.NewMenuItem("Динамика эксперимента", mw.showTimeSeries),
+			fyne.NewMenuItem("Эффективность рекомендаций", mw.showItems),
+			fyne.NewMenuItem("Слои экспериментов", mw.showLayers),
 		),
 		fyne.NewMenu("База данных",
 			fyne.NewMenuItem("ALTER TABLE", mw.showAlterTable),
@@ -501,9 +514,14 @@ func (mw *MainWindow) showSummaryWindow() {
 		resultLabel.SetText(fmt.Sprintf("Таблица 'results': %d строк, %d столбцов", len(result.Rows), len(result.Columns)))
 	}
 
+	// Панель статистического анализа экспериментов
+	statsPanel := NewExperimentStatsPanel(mw, resultsWin)
+	statsContent := statsPanel.Build()
+
 	// Кнопки управления
 	refreshBtn := widget.NewButton("Обновить", func() {
 		loadResultsData()
+		statsPanel.LoadExperiments()
 	})
 
 	closeBtn := widget.NewButton("Закрыть", func() {
@@ -516,11 +534,18 @@ func (mw *MainWindow) showSummaryWindow() {
 		closeBtn,
 	)
 
+	// Разделение на статистику и таблицу результатов
+	split := container.NewVSplit(
+		statsContent,
+		container.NewScroll(tableContainer),
+	)
+	split.SetOffset(0.5)
+
 	// Основной контент
 	content := container.NewBorder(
 		container.NewVBox(resultLabel, controlPanel),
 		nil, nil, nil,
-		container.NewScroll(tableContainer),
+		split,
 	)
 
 	resultsWin.SetContent(content)
@@ -572,20 +597,39 @@ func (mw *MainWindow) Show() {
 	mw.ShowInstructionDialog()
 }
 
+// actorContext возвращает контекст, изменения экспериментов в котором записываются в журнал
+// от имени оператора
+func (mw *MainWindow) actorContext() context.Context {
+	return db.WithActor(context.Background(), mw.operator)
+}
+
 // Показ диалога с инструкцией
 func (mw *MainWindow) ShowInstructionDialog() {
 	instructionText := `Перед внесением данных ознакомьтесь с правилами:
 
     Эксперимент:
     - Название: обязательно, не длиннее 255 символов
-    - Алгоритмы: должны быть разными
+    - Варианты: от 2 до 8 групп (A — контрольная), алгоритмы должны быть зарегистрированы
+      в реестре алгоритмов (устаревшие не назначаются); один алгоритм можно использовать
+      в нескольких вариантах с разными параметрами
+    - Параметры варианта: необязательный JSON-объект, например {"weight": 0.3},
+      проверяется по схеме параметров алгоритма из реестра
+    - Веса вариантов: доля трафика в процентах, в сумме 100
     - Процент пользователей: число от 0.1 до 100
+    - Статус: черновик, запланирован или запущен; дальнейшие переходы — в окне «Статусы и история экспериментов»
+    - Расписание: необязательно, формат ГГГГ-ММ-ДД ЧЧ:ММ; запланированный эксперимент требует даты запуска
+      и запускается автоматически, по плановой дате завершения эксперимент завершается
+    - Слой: необязательно; в слое эксперимент получает свободный диапазон трафика шириной
+      в процент пользователей, эксперименты одного слоя не пересекаются по пользователям
     - Теги: через запятую, каждый тег не длинее 50 символов
+    - Редактирование: выберите строку в таблице experiments окна данных и нажмите «Редактировать эксперимент»;
+      у запущенного эксперимента меняются только название, теги и (с ростом) процент пользователей
 
     Пользователь:
     - ID эксперимента: целое положительное число
     - ID пользователя: обязательно, не длинее 255 символов (только буквы, цифры, дефисы и подчеркивания)
-    - Группа: A или B
+    - Группа: один из вариантов эксперимента или «Авто»: группа определяется хешем ID пользователя
+      с учетом процента пользователей и весов вариантов
 
     Результат:
     - ID пользователя: целое положительное число
@@ -604,6 +648,41 @@ func (mw *MainWindow) showCustomTypes() {
 	customTypesWin.Show()
 }
 
+func (mw *MainWindow) showMetrics() {
+	metricsWin := NewMetricsWindow(mw.rep)
+	metricsWin.Show()
+}
+
+func (mw *MainWindow) showAlgorithms() {
+	algorithmsWin := NewAlgorithmsWindow(mw)
+	algorithmsWin.Show()
+}
+
+func (mw *MainWindow) showGuardrails() {
+	guardrailsWin := NewGuardrailsWindow(mw)
+	guardrailsWin.Show()
+}
+
+func (mw *MainWindow) showTimeSeries() {
+	timeSeriesWin := NewTimeSeriesWindow(mw)
+	timeSeriesWin.Show()
+}
+
+func (mw *MainWindow) showItems() {
+	itemsWin := NewItemsWindow(mw)
+	itemsWin.Show()
+}
+
+func (mw *MainWindow) showLifecycle() {
+	lifecycleWin := NewLifecycleWindow(mw)
+	lifecycleWin.Show()
+}
+
+func (mw *MainWindow) showLayers() {
+	layersWin := NewLayersWindow(mw)
+	layersWin.Show()
+}
+
 func (mw *MainWindow) showSubqueryBuilder() {
 	// Можно открыть общий построитель или показать сообщение
 	dialog.ShowInformation("Подзапросы",
@@ -0,0 +1,224 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing-platform/db"
+	"testing-platform/db/models"
+	"testing-platform/pkg/logger"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+// MetricsWindow окно управления реестром метрик
+type MetricsWindow struct {
+	window fyne.Window
+	rep    *db.Repository
+
+	metricList       *widget.List
+	nameEntry        *widget.Entry
+	titleEntry       *widget.Entry
+	typeSelect       *widget.Select
+	numeratorEntry   *widget.Entry
+	denominatorEntry *widget.Entry
+	higherCheck      *widget.Check
+	statusLabel      *widget.Label
+
+	metrics    []models.Metric
+	selectedID int // ID выбранной метрики, 0 — новая метрика
+}
+
+// NewMetricsWindow создает окно реестра метрик
+func NewMetricsWindow(rep *db.Repository) *MetricsWindow {
+	w := &MetricsWindow{
+		window: fyne.CurrentApp().NewWindow("Реестр метрик"),
+		rep:    rep,
+	}
+	w.window.Resize(fyne.NewSize(1000, 650))
+	w.buildUI()
+	w.loadMetrics()
+	return w
+}
+
+func (w *MetricsWindow) buildUI() {
+	w.metricList = widget.NewList(
+		func() int { return len(w.metrics) },
+		func() fyne.CanvasObject {
+			return container.NewVBox(
+				widget.NewLabelWithStyle("", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+				widget.NewLabel(""),
+			)
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			m := w.metrics[i]
+			cont := o.(*fyne.Container)
+			cont.Objects[0].(*widget.Label).SetText(fmt.Sprintf("%s (%s)", m.Title, m.Name))
+			direction := "больше — лучше"
+			if !m.HigherIsBetter {
+				direction = "меньше — лучше"
+			}
+			cont.Objects[1].(*widget.Label).SetText(fmt.Sprintf("%s, %s", m.Type, direction))
+		},
+	)
+	w.metricList.OnSelected = w.onMetricSelected
+
+	w.nameEntry = widget.NewEntry()
+	w.nameEntry.SetPlaceHolder("Системное имя (например: clicks_per_user)")
+	w.titleEntry = widget.NewEntry()
+	w.titleEntry.SetPlaceHolder("Название для отчетов")
+	w.typeSelect = widget.NewSelect(models.MetricTypes, nil)
+	w.typeSelect.SetSelected(models.MetricTypeProportion)
+	w.numeratorEntry = widget.NewMultiLineEntry()
+	w.numeratorEntry.SetPlaceHolder("COUNT(r.id) FILTER (WHERE r.clicked)")
+	w.denominatorEntry = widget.NewMultiLineEntry()
+	w.denominatorEntry.SetPlaceHolder("COUNT(r.id)")
+	w.higherCheck = widget.NewCheck("Рост метрики — улучшение", nil)
+	w.higherCheck.SetChecked(true)
+
+	w.statusLabel = widget.NewLabel("")
+	w.statusLabel.Wrapping = fyne.TextWrapWord
+
+	help := widget.NewLabel("Числитель и знаменатель — агрегатные SQL-выражения, вычисляемые для каждого пользователя " +
+		"эксперимента по его результатам (u — users, r — results).\n" +
+		"proportion: сумма числителей / сумма знаменателей (z-тест); " +
+		"mean: среднее числитель/знаменатель на пользователя (t-тест Уэлча, знаменатель можно не указывать); " +
+		"ratio: отношение сумм (дельта-метод).")
+	help.Wrapping = fyne.TextWrapWord
+
+	form := widget.NewForm(
+		widget.NewFormItem("Имя", w.nameEntry),
+		widget.NewFormItem("Название", w.titleEntry),
+		widget.NewFormItem("Тип", w.typeSelect),
+		widget.NewFormItem("Числитель", w.numeratorEntry),
+		widget.NewFormItem("Знаменатель", w.denominatorEntry),
+		widget.NewFormItem("", w.higherCheck),
+	)
+
+	saveBtn := widget.NewButton("Сохранить", w.saveMetric)
+	newBtn := widget.NewButton("Новая метрика", w.clearForm)
+	deleteBtn := widget.NewButton("Удалить", w.deleteMetric)
+	closeBtn := widget.NewButton("Закрыть", func() { w.window.Close() })
+
+	editor := container.NewBorder(
+		container.NewVBox(
+			widget.NewLabelWithStyle("Определение метрики", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+			form,
+			container.NewHBox(saveBtn, newBtn, deleteBtn),
+			w.statusLabel,
+		),
+		nil, nil, nil,
+		help,
+	)
+
+	split := container.NewHSplit(container.NewScroll(w.metricList), editor)
+	split.SetOffset(0.35)
+
+	w.window.SetContent(container.NewPadded(container.NewBorder(
+		nil,
+		container.NewHBox(layout.NewSpacer(), closeBtn),
+		nil, nil,
+		split,
+	)))
+}
+
+// loadMetrics загружает метрики реестра
+func (w *MetricsWindow) loadMetrics() {
+	metrics, err := w.rep.GetMetrics(context.Background())
+	if err != nil {
+		logger.Error("Ошибка загрузки метрик: %v", err)
+		w.statusLabel.SetText("Ошибка загрузки метрик: " + err.Error())
+		return
+	}
+	w.metrics = metrics
+	w.metricList.UnselectAll()
+	w.metricList.Refresh()
+}
+
+// onMetricSelected заполняет форму выбранной метрикой
+func (w *MetricsWindow) onMetricSelected(id widget.ListItemID) {
+	if id < 0 || id >= len(w.metrics) {
+		return
+	}
+	m := w.metrics[id]
+	w.selectedID = m.ID
+	w.nameEntry.SetText(m.Name)
+	w.titleEntry.SetText(m.Title)
+	w.typeSelect.SetSelected(m.Type)
+	w.numeratorEntry.SetText(m.NumeratorSQL)
+	w.denominatorEntry.SetText(m.DenominatorSQL)
+	w.higherCheck.SetChecked(m.HigherIsBetter)
+	w.statusLabel.SetText(fmt.Sprintf("Редактирование метрики %s", m.Name))
+}
+
+// clearForm очищает форму для новой метрики
+func (w *MetricsWindow) clearForm() {
+	w.selectedID = 0
+	w.metricList.UnselectAll()
+	w.nameEntry.SetText("")
+	w.titleEntry.SetText("")
+	w.typeSelect.SetSelected(models.MetricTypeProportion)
+	w.numeratorEntry.SetText("")
+	w.denominatorEntry.SetText("")
+	w.higherCheck.SetChecked(true)
+	w.statusLabel.SetText("")
+}
+
+// saveMetric создает новую или изменяет выбранную метрику
+func (w *MetricsWindow) saveMetric() {
+	m := &models.Metric{
+		ID:             w.selectedID,
+		Name:           strings.TrimSpace(w.nameEntry.Text),
+		Title:          strings.TrimSpace(w.titleEntry.Text),
+		Type:           w.typeSelect.Selected,
+		NumeratorSQL:   strings.TrimSpace(w.numeratorEntry.Text),
+		DenominatorSQL: strings.TrimSpace(w.denominatorEntry.Text),
+		HigherIsBetter: w.higherCheck.Checked,
+	}
+
+	ctx := context.Background()
+	var err error
+	if m.ID == 0 {
+		err = w.rep.CreateMetric(ctx, m)
+	} else {
+		err = w.rep.UpdateMetric(ctx, m)
+	}
+	if err != nil {
+		dialog.ShowError(err, w.window)
+		return
+	}
+
+	w.loadMetrics()
+	w.clearForm()
+	w.statusLabel.SetText(fmt.Sprintf("Метрика %s сохранена", m.Name))
+}
+
+// deleteMetric удаляет выбранную метрику после подтверждения
+func (w *MetricsWindow) deleteMetric() {
+	if w.selectedID == 0 {
+		dialog.ShowInformation("Не выбрана метрика", "Выберите метрику в списке", w.window)
+		return
+	}
+	id := w.selectedID
+	dialog.ShowConfirm("Удаление метрики", "Удалить выбранную метрику из реестра?", func(ok bool) {
+		if !ok {
+			return
+		}
+		if err := w.rep.DeleteMetric(context.Background(), id); err != nil {
+			dialog.ShowError(err, w.window)
+			return
+		}
+		w.loadMetrics()
+		w.clearForm()
+		w.statusLabel.SetText("Метрика удалена")
+	}, w.window)
+}
+
+// Show отображает окно
+func (w *MetricsWindow) Show() {
+	w.window.Show()
+}
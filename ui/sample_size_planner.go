@@ -0,0 +1,121 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing-platform/db/models"
+	"testing-platform/pkg/logger"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// parsePercentEntry разбирает значение в процентах (например, "5" или "5,5") и возвращает долю
+func parsePercentEntry(s string) (float64, error) {
+	s = strings.ReplaceAll(strings.TrimSpace(s), ",", ".")
+	s = strings.TrimSuffix(s, "%")
+	val, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("значение должно быть числом")
+	}
+	return val / 100, nil
+}
+
+// showSampleSizePlanner показывает окно планирования размера выборки и длительности эксперимента.
+// input содержит значения из формы эксперимента, onApply вызывается с рекомендуемым процентом пользователей
+func (mw *MainWindow) showSampleSizePlanner(input models.SampleSizeInput, onApply func(userPercent float64)) {
+	baseline := widget.NewEntry()
+	baseline.SetPlaceHolder("Например: 10")
+	mde := widget.NewEntry()
+	mde.SetPlaceHolder("Например: 5")
+	alpha := widget.NewSelect([]string{"1%", "5%", "10%"}, nil)
+	alpha.SetSelected("5%")
+	power := widget.NewSelect([]string{"80%", "90%", "95%"}, nil)
+	power.SetSelected("80%")
+	targetDays := widget.NewEntry()
+	targetDays.SetPlaceHolder("Необязательно, например: 14")
+
+	resultLabel := monospaceLabel("Заполните параметры и нажмите «Рассчитать»")
+	var suggestedPercent float64
+	applyBtn := widget.NewButton("Применить процент пользователей", func() {
+		if suggestedPercent > 0 && onApply != nil {
+			onApply(suggestedPercent)
+		}
+	})
+	applyBtn.Disable()
+
+	calculate := func() {
+		var err error
+		if input.BaselineCTR, err = parsePercentEntry(baseline.Text); err != nil {
+			showUserError(mw.window, "Ошибка в базовом CTR: "+err.Error())
+			return
+		}
+		if input.MinDetectableEffect, err = parsePercentEntry(mde.Text); err != nil {
+			showUserError(mw.window, "Ошибка в минимальном эффекте: "+err.Error())
+			return
+		}
+		input.Alpha, _ = parsePercentEntry(alpha.Selected)
+		input.Power, _ = parsePercentEntry(power.Selected)
+
+		plan, err := mw.rep.PlanExperiment(context.Background(), input)
+		if err != nil {
+			logger.Error("Ошибка планирования эксперимента: %v", err)
+			showUserError(mw.window, "Не удалось рассчитать план: "+err.Error())
+			return
+		}
+
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "Показов рекомендаций на группу: %d\n", plan.RecommendationsPerGroup)
+		fmt.Fprintf(&sb, "Рекомендаций на пользователя:   %.2f\n", plan.RecommendationsPerUser)
+		fmt.Fprintf(&sb, "Пользователей на группу:        %d\n", plan.UsersPerGroup)
+		fmt.Fprintf(&sb, "Всего пользователей (%d групп):  %d\n", plan.Input.Groups, plan.TotalUsers)
+		if plan.DailyUsersPerPercent == 0 {
+			sb.WriteString("Длительность: нет истории назначения пользователей для оценки\n")
+		} else {
+			fmt.Fprintf(&sb, "Темп назначения (похожих экспериментов: %d): %.1f польз./день на 1%% трафика\n",
+				plan.SimilarExperiments, plan.DailyUsersPerPercent)
+			if plan.EstimatedDays > 0 {
+				fmt.Fprintf(&sb, "При %.2f%% пользователей: ~%.1f дней\n", input.UserPercent, plan.EstimatedDays)
+			}
+		}
+
+		suggestedPercent = 0
+		applyBtn.Disable()
+		if days, err := strconv.ParseFloat(strings.TrimSpace(targetDays.Text), 64); err == nil && days > 0 {
+			percent := plan.UserPercentForDays(days)
+			switch {
+			case percent == 0:
+			case percent > 100:
+				fmt.Fprintf(&sb, "За %.0f дней выборку не набрать даже на 100%% трафика", days)
+			default:
+				suggestedPercent = max(percent, 1)
+				fmt.Fprintf(&sb, "Для завершения за %.0f дней нужно %.2f%% пользователей", days, suggestedPercent)
+				applyBtn.Enable()
+			}
+		}
+		resultLabel.SetText(strings.TrimSuffix(sb.String(), "\n"))
+	}
+
+	form := widget.NewForm(
+		widget.NewFormItem("Базовый CTR, %", baseline),
+		widget.NewFormItem("Мин. эффект (относит.), %", mde),
+		widget.NewFormItem("Уровень значимости", alpha),
+		widget.NewFormItem("Мощность", power),
+		widget.NewFormItem("Желаемая длительность, дней", targetDays),
+	)
+
+	content := container.NewVBox(
+		form,
+		container.NewHBox(widget.NewButton("Рассчитать", calculate), applyBtn),
+		widget.NewSeparator(),
+		resultLabel,
+	)
+
+	d := dialog.NewCustom("Планирование размера выборки", "Закрыть", content, mw.window)
+	d.Resize(fyne.NewSize(600, 450))
+	d.Show()
+}
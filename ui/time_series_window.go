@@ -0,0 +1,229 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"testing-platform/db/models"
+	"testing-platform/pkg/logger"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/widget"
+)
+
+// шаги временного ряда, доступные в окне динамики
+var granularityOptions = map[string]string{
+	"По дням":  models.TimeGranularityDay,
+	"По часам": models.TimeGranularityHour,
+}
+
+// TimeSeriesWindow окно динамики показателей групп эксперимента
+type TimeSeriesWindow struct {
+	mw     *MainWindow
+	window fyne.Window
+
+	experimentSelect  *widget.Select
+	granularitySelect *widget.Select
+	statusLabel       *widget.Label
+	tabs              *container.AppTabs
+
+	experimentIDs map[string]int
+}
+
+// NewTimeSeriesWindow создает окно динамики эксперимента
+func NewTimeSeriesWindow(mw *MainWindow) *TimeSeriesWindow {
+	w := &TimeSeriesWindow{
+		mw:            mw,
+		window:        mw.app.NewWindow("Динамика эксперимента"),
+		experimentIDs: make(map[string]int),
+	}
+	w.window.Resize(fyne.NewSize(1100, 750))
+	w.buildUI()
+	return w
+}
+
+func (w *TimeSeriesWindow) buildUI() {
+	w.experimentSelect = widget.NewSelect([]string{}, nil)
+	w.experimentSelect.PlaceHolder = "Выберите эксперимент"
+	w.granularitySelect = widget.NewSelect([]string{"По дням", "По часам"}, nil)
+	w.granularitySelect.SetSelected("По дням")
+
+	w.statusLabel = widget.NewLabel("Выберите эксперимент и нажмите «Построить»")
+	w.tabs = container.NewAppTabs()
+
+	buildBtn := widget.NewButton("Построить", w.build)
+	closeBtn := widget.NewButton("Закрыть", func() { w.window.Close() })
+
+	w.window.SetContent(container.NewPadded(container.NewBorder(
+		container.NewVBox(
+			container.NewHBox(
+				widget.NewLabel("Эксперимент:"), w.experimentSelect,
+				widget.NewLabel("Шаг:"), w.granularitySelect,
+				buildBtn,
+			),
+			w.statusLabel,
+		),
+		container.NewHBox(layout.NewSpacer(), closeBtn),
+		nil, nil,
+		w.tabs,
+	)))
+
+	experiments, err := w.mw.rep.GetExperiments(context.Background(), models.ExperimentFilter{})
+	if err != nil {
+		logger.Error("Ошибка загрузки списка экспериментов: %v", err)
+		w.statusLabel.SetText("Ошибка загрузки списка экспериментов: " + err.Error())
+		return
+	}
+	var options []string
+	for _, exp := range experiments {
+		option := fmt.Sprintf("%d: %s", exp.ID, exp.Name)
+		options = append(options, option)
+		w.experimentIDs[option] = exp.ID
+	}
+	w.experimentSelect.Options = options
+	w.experimentSelect.Refresh()
+}
+
+// build загружает временной ряд и строит графики
+func (w *TimeSeriesWindow) build() {
+	experimentID, ok := w.experimentIDs[w.experimentSelect.Selected]
+	if !ok {
+		dialog.ShowInformation("Не выбран эксперимент", "Выберите эксперимент из списка", w.window)
+		return
+	}
+	granularity := granularityOptions[w.granularitySelect.Selected]
+
+	series, err := w.mw.rep.GetExperimentTimeSeries(context.Background(), experimentID, granularity, models.DefaultConfidence)
+	if err != nil {
+		w.statusLabel.SetText("Ошибка построения динамики: " + err.Error())
+		return
+	}
+	if len(series.Buckets) == 0 {
+		w.tabs.SetItems(nil)
+		w.statusLabel.SetText("В эксперименте еще нет результатов")
+		return
+	}
+
+	layoutStr := "02.01"
+	if granularity == models.TimeGranularityHour {
+		layoutStr = "02.01 15:00"
+	}
+	labels := make([]string, len(series.Buckets))
+	for i, b := range series.Buckets {
+		labels[i] = b.Format(layoutStr)
+	}
+
+	var groups []string
+	for name := range series.Groups {
+		groups = append(groups, name)
+	}
+	sort.Strings(groups)
+
+	groupSeries := func(value func(models.TimeSeriesPoint) float64) []chartSeries {
+		var result []chartSeries
+		for i, name := range groups {
+			values := make([]float64, len(series.Buckets))
+			for j, p := range series.Groups[name] {
+				values[j] = value(p)
+			}
+			result = append(result, chartSeries{Name: "Группа " + name, Values: values, Color: chartColor(i)})
+		}
+		return result
+	}
+
+	ctr := groupSeries(func(p models.TimeSeriesPoint) float64 {
+		if p.Recommendations == 0 {
+			return math.NaN()
+		}
+		return p.CTR
+	})
+	cumulativeCTR := groupSeries(func(p models.TimeSeriesPoint) float64 {
+		if p.CumulativeRecommendations == 0 {
+			return math.NaN()
+		}
+		return p.CumulativeCTR
+	})
+	recommendations := groupSeries(func(p models.TimeSeriesPoint) float64 { return float64(p.Recommendations) })
+	clicks := groupSeries(func(p models.TimeSeriesPoint) float64 { return float64(p.Clicks) })
+	rating := groupSeries(func(p models.TimeSeriesPoint) float64 {
+		if p.Ratings == 0 {
+			return math.NaN()
+		}
+		return p.AvgRating
+	})
+
+	var lift []chartSeries
+	for i, name := range sortedGroupNames(series.Lift) {
+		points := series.Lift[name]
+		value, lower, upper := make([]float64, len(points)), make([]float64, len(points)), make([]float64, len(points))
+		for j, p := range points {
+			value[j], lower[j], upper[j] = math.NaN(), math.NaN(), math.NaN()
+			if p.Defined {
+				value[j], lower[j], upper[j] = p.AbsoluteLift, p.CILower, p.CIUpper
+			}
+		}
+		c := chartColor(i + 1)
+		lift = append(lift,
+			chartSeries{Name: name + " − " + models.ControlGroup, Values: value, Color: c},
+			chartSeries{Name: fmt.Sprintf("интервал %.0f%%", series.Confidence*100), Values: lower, Color: c, Dashed: true},
+			chartSeries{Values: upper, Color: c, Dashed: true},
+		)
+	}
+
+	count := func(v float64) string { return fmt.Sprintf("%.0f", v) }
+	points := func(v float64) string { return fmt.Sprintf("%+.2f п.п.", v*100) }
+
+	w.tabs.SetItems([]*container.TabItem{
+		container.NewTabItem("CTR", container.NewGridWithRows(2,
+			newLineChart("CTR за интервал", labels, ctr, formatPercent),
+			newLineChart("Накопленный CTR", labels, cumulativeCTR, formatPercent),
+		)),
+		container.NewTabItem("Накопленный прирост", newLineChart(
+			"Накопленная разность CTR с контролем (поточечный интервал)", labels, lift, points)),
+		container.NewTabItem("Показы и клики", container.NewGridWithRows(2,
+			newLineChart("Показы рекомендаций", labels, recommendations, count),
+			newLineChart("Клики", labels, clicks, count),
+		)),
+		container.NewTabItem("Оценки", newLineChart("Средняя оценка", labels, rating, nil)),
+		container.NewTabItem("Таблица", container.NewScroll(monospaceLabel(formatTimeSeriesTable(series, groups, labels)))),
+	})
+	w.statusLabel.SetText(fmt.Sprintf("Эксперимент %d: %d интервалов", experimentID, len(series.Buckets)))
+}
+
+// sortedGroupNames возвращает имена групп в алфавитном порядке
+func sortedGroupNames[T any](groups map[string]T) []string {
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// formatTimeSeriesTable форматирует временной ряд для моноширинного вывода
+func formatTimeSeriesTable(series *models.ExperimentTimeSeries, groups, labels []string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%-12s %-6s %8s %8s %9s %8s %12s\n", "Интервал", "Группа", "Показы", "Клики", "CTR", "Оценка", "Накопл. CTR")
+	for i := range series.Buckets {
+		for _, name := range groups {
+			p := series.Groups[name][i]
+			rating := "—"
+			if p.Ratings > 0 {
+				rating = fmt.Sprintf("%.2f", p.AvgRating)
+			}
+			fmt.Fprintf(&sb, "%-12s %-6s %8d %8d %9s %8s %12s\n",
+				labels[i], name, p.Recommendations, p.Clicks, formatPercent(p.CTR), rating, formatPercent(p.CumulativeCTR))
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// Show отображает окно
+func (w *TimeSeriesWindow) Show() {
+	w.window.Show()
+}